@@ -0,0 +1,188 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	grpcauth "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/zalgonoise/tendigitprimes/log"
+)
+
+const (
+	// Header is the HTTP header holding an API key, as an alternative to an `Authorization: Bearer <key>` header.
+	Header = "X-Api-Key"
+	// MetadataKey is the gRPC metadata key holding an API key, as an alternative to an `authorization: Bearer <key>`
+	// entry.
+	MetadataKey = "x-api-key"
+
+	bearerScheme = "bearer"
+
+	ReasonMissing = "missing"
+	ReasonInvalid = "invalid"
+	ReasonRevoked = "revoked"
+)
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, apiKey string) (Key, error)
+}
+
+type Metrics interface {
+	IncAPIKeyRequests(key, method string)
+	IncAPIKeyRejections(reason string)
+}
+
+type keyCtx struct{}
+
+// WithKey returns a copy of ctx holding the input Key, as the identity of the caller.
+func WithKey(ctx context.Context, key Key) context.Context {
+	return context.WithValue(ctx, keyCtx{}, key)
+}
+
+// KeyFrom returns the Key identifying the caller in ctx, if any.
+func KeyFrom(ctx context.Context) (Key, bool) {
+	key, ok := ctx.Value(keyCtx{}).(Key)
+
+	return key, ok
+}
+
+// AuthFunc returns a grpcauth.AuthFunc that authenticates calls with the API key in their metadata, either under
+// MetadataKey or as a bearer token in the authorization entry. The key's identity is added to the call's context, for
+// its handler, and for the logs written with it.
+func AuthFunc(a Authenticator, m Metrics, logger *slog.Logger) grpcauth.AuthFunc {
+	return func(ctx context.Context) (context.Context, error) {
+		apiKey := metadata.ValueFromIncomingContext(ctx, MetadataKey)
+		if len(apiKey) == 0 {
+			token, err := grpcauth.AuthFromMD(ctx, bearerScheme)
+			if err != nil {
+				m.IncAPIKeyRejections(ReasonMissing)
+
+				return nil, toStatus(ErrMissingKey).Err()
+			}
+
+			apiKey = []string{token}
+		}
+
+		key, err := authenticate(ctx, a, m, logger, apiKey[0])
+		if err != nil {
+			return nil, toStatus(err).Err()
+		}
+
+		method, _ := grpc.Method(ctx)
+		m.IncAPIKeyRequests(key.ID, method)
+
+		return withIdentity(ctx, key), nil
+	}
+}
+
+// Middleware returns an HTTP middleware that authenticates requests with the API key in their Header, or as a bearer
+// token in their Authorization header, except for requests on the input public paths. The key's identity is added to
+// the request's context.
+//
+// Rejected requests are answered with the same status body as the gateway's errors. Accepted requests are counted by
+// the gRPC server they are forwarded to, along with the key.
+func Middleware(a Authenticator, m Metrics, logger *slog.Logger, public ...string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(public, r.URL.Path) {
+				h.ServeHTTP(w, r)
+
+				return
+			}
+
+			apiKey := r.Header.Get(Header)
+			if apiKey == "" {
+				scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+				if strings.EqualFold(scheme, bearerScheme) {
+					apiKey = token
+				}
+			}
+
+			if apiKey == "" {
+				m.IncAPIKeyRejections(ReasonMissing)
+				reject(w, toStatus(ErrMissingKey))
+
+				return
+			}
+
+			key, err := authenticate(r.Context(), a, m, logger, apiKey)
+			if err != nil {
+				reject(w, toStatus(err))
+
+				return
+			}
+
+			h.ServeHTTP(w, r.WithContext(withIdentity(r.Context(), key)))
+		})
+	}
+}
+
+func authenticate(ctx context.Context, a Authenticator, m Metrics, logger *slog.Logger, apiKey string) (Key, error) {
+	key, err := a.Authenticate(ctx, apiKey)
+
+	switch {
+	case err == nil:
+		return key, nil
+	case errors.Is(err, ErrRevokedKey):
+		m.IncAPIKeyRejections(ReasonRevoked)
+		logger.WarnContext(ctx, "rejected revoked API key",
+			slog.String("api_key_id", key.ID),
+			slog.String("api_key_name", key.Name),
+		)
+	case errors.Is(err, ErrInvalidKey):
+		m.IncAPIKeyRejections(ReasonInvalid)
+	default:
+		logger.ErrorContext(ctx, "failed to authenticate API key", slog.String("error", err.Error()))
+	}
+
+	return Key{}, err
+}
+
+func withIdentity(ctx context.Context, key Key) context.Context {
+	return log.WithAttrs(WithKey(ctx, key),
+		slog.String("api_key_id", key.ID),
+		slog.String("api_key_name", key.Name),
+	)
+}
+
+// toStatus returns the gRPC status for an authentication error: revoked keys are known, but not allowed. Other
+// failures are not detailed to the caller, as they are logged instead.
+func toStatus(err error) *status.Status {
+	switch {
+	case errors.Is(err, ErrRevokedKey):
+		return status.New(codes.PermissionDenied, err.Error())
+	case errors.Is(err, ErrInvalidKey), errors.Is(err, ErrMissingKey):
+		return status.New(codes.Unauthenticated, err.Error())
+	default:
+		return status.New(codes.Internal, "failed to authenticate API key")
+	}
+}
+
+func reject(w http.ResponseWriter, s *status.Status) {
+	code := runtime.HTTPStatusFromCode(s.Code())
+
+	body, err := protojson.Marshal(s.Proto())
+	if err != nil {
+		http.Error(w, s.Message(), code)
+
+		return
+	}
+
+	if s.Code() == codes.Unauthenticated {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,147 @@
+package apikeys
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMiddleware(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	key, apiKey, err := s.Create(ctx, "ci")
+	require.NoError(t, err)
+
+	revoked, revokedKey, err := s.Create(ctx, "old")
+	require.NoError(t, err)
+	require.NoError(t, s.Revoke(ctx, revoked.ID))
+
+	handler := Middleware(s, metrics.Noop{}, log.NoOp(), "/ready")(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := KeyFrom(r.Context())
+			if ok {
+				w.Header().Set("X-Key-Name", got.Name)
+			}
+
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	for _, testcase := range []struct {
+		name     string
+		path     string
+		header   http.Header
+		wants    int
+		wantsKey string
+	}{
+		{
+			name:  "Missing",
+			path:  "/v1/primes",
+			wants: http.StatusUnauthorized,
+		},
+		{
+			name:   "Invalid",
+			path:   "/v1/primes",
+			header: http.Header{Header: {"pk_0000000000000000_secret"}},
+			wants:  http.StatusUnauthorized,
+		},
+		{
+			name:   "Revoked",
+			path:   "/v1/primes",
+			header: http.Header{Header: {revokedKey}},
+			wants:  http.StatusForbidden,
+		},
+		{
+			name:     "Header",
+			path:     "/v1/primes",
+			header:   http.Header{Header: {apiKey}},
+			wants:    http.StatusOK,
+			wantsKey: key.Name,
+		},
+		{
+			name:     "Bearer",
+			path:     "/v1/primes",
+			header:   http.Header{"Authorization": {"Bearer " + apiKey}},
+			wants:    http.StatusOK,
+			wantsKey: key.Name,
+		},
+		{
+			name:  "Public",
+			path:  "/ready",
+			wants: http.StatusOK,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, testcase.path, http.NoBody)
+
+			for k, v := range testcase.header {
+				r.Header[k] = v
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			require.Equal(t, testcase.wants, w.Code)
+			require.Equal(t, testcase.wantsKey, w.Header().Get("X-Key-Name"))
+		})
+	}
+}
+
+func TestAuthFunc(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	key, apiKey, err := s.Create(ctx, "ci")
+	require.NoError(t, err)
+
+	authFunc := AuthFunc(s, metrics.Noop{}, log.NoOp())
+
+	for _, testcase := range []struct {
+		name  string
+		md    metadata.MD
+		wants codes.Code
+	}{
+		{
+			name:  "Missing",
+			md:    metadata.MD{},
+			wants: codes.Unauthenticated,
+		},
+		{
+			name:  "Invalid",
+			md:    metadata.Pairs(MetadataKey, "pk_0000000000000000_secret"),
+			wants: codes.Unauthenticated,
+		},
+		{
+			name:  "MetadataKey",
+			md:    metadata.Pairs(MetadataKey, apiKey),
+			wants: codes.OK,
+		},
+		{
+			name:  "Bearer",
+			md:    metadata.Pairs("authorization", "Bearer "+apiKey),
+			wants: codes.OK,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			got, err := authFunc(metadata.NewIncomingContext(ctx, testcase.md))
+			require.Equal(t, testcase.wants, status.Code(err))
+
+			if testcase.wants != codes.OK {
+				return
+			}
+
+			identity, ok := KeyFrom(got)
+			require.True(t, ok)
+			require.Equal(t, key, identity)
+		})
+	}
+}
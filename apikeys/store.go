@@ -0,0 +1,197 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// keyPrefix starts every API key, as `pk_<id>_<secret>`, so that keys are recognizable in configs and logs
+	keyPrefix  = "pk"
+	idSize     = 8
+	secretSize = 32
+
+	createTableQuery = `
+	CREATE TABLE IF NOT EXISTS api_keys (
+	  id          TEXT PRIMARY KEY NOT NULL,
+	  name        TEXT NOT NULL,
+	  hash        BLOB NOT NULL,
+	  created_at  INTEGER NOT NULL,
+	  revoked_at  INTEGER
+	) STRICT;
+`
+	insertKeyQuery = `INSERT INTO api_keys (id, name, hash, created_at) VALUES (?, ?, ?, ?);`
+	selectKeyQuery = `SELECT name, hash, created_at, revoked_at FROM api_keys WHERE id = ?;`
+	listKeysQuery  = `SELECT id, name, created_at, revoked_at FROM api_keys ORDER BY created_at, rowid;`
+	revokeKeyQuery = `UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL;`
+)
+
+var (
+	ErrMissingKey  = errors.New("missing API key")
+	ErrInvalidKey  = errors.New("invalid API key")
+	ErrRevokedKey  = errors.New("API key is revoked")
+	ErrKeyNotFound = errors.New("API key not found")
+	ErrEmptyName   = errors.New("API key name is empty")
+)
+
+// Key is an API key's identity. Its secret is only known when it is created, as only its hash is stored.
+type Key struct {
+	ID        string
+	Name      string
+	CreatedAt time.Time
+	// RevokedAt is when the key was revoked, or the zero time if it is active.
+	RevokedAt time.Time
+}
+
+func (k Key) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// Store keeps API keys in an SQLite database, storing a SHA-256 hash of each key's secret. As secrets are random, a
+// fast hash is enough to make a leaked database useless to authenticate with.
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// Migrate creates the API keys table, if it does not exist yet.
+func (s *Store) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, createTableQuery)
+
+	return err
+}
+
+// Create stores a new API key with the input name, returning its identity along with the key to authenticate with,
+// which cannot be retrieved afterward.
+func (s *Store) Create(ctx context.Context, name string) (Key, string, error) {
+	if strings.TrimSpace(name) == "" {
+		return Key{}, "", ErrEmptyName
+	}
+
+	id := make([]byte, idSize)
+	secret := make([]byte, secretSize)
+
+	if _, err := rand.Read(id); err != nil {
+		return Key{}, "", err
+	}
+
+	if _, err := rand.Read(secret); err != nil {
+		return Key{}, "", err
+	}
+
+	key := Key{ID: hex.EncodeToString(id), Name: name, CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+	hash := sha256.Sum256([]byte(encoded))
+
+	if _, err := s.db.ExecContext(ctx, insertKeyQuery, key.ID, key.Name, hash[:], key.CreatedAt.Unix()); err != nil {
+		return Key{}, "", err
+	}
+
+	return key, strings.Join([]string{keyPrefix, key.ID, encoded}, "_"), nil
+}
+
+// List returns all API keys, including revoked ones, in the order they were created.
+func (s *Store) List(ctx context.Context) ([]Key, error) {
+	rows, err := s.db.QueryContext(ctx, listKeysQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	keys := make([]Key, 0, 16)
+
+	for rows.Next() {
+		var (
+			key       Key
+			createdAt int64
+			revokedAt sql.NullInt64
+		)
+
+		if err = rows.Scan(&key.ID, &key.Name, &createdAt, &revokedAt); err != nil {
+			return nil, err
+		}
+
+		key.CreatedAt = time.Unix(createdAt, 0).UTC()
+
+		if revokedAt.Valid {
+			key.RevokedAt = time.Unix(revokedAt.Int64, 0).UTC()
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, errors.Join(rows.Close(), rows.Err())
+}
+
+// Revoke revokes the API key with the input ID, which is rejected from then on.
+func (s *Store) Revoke(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, revokeKeyQuery, time.Now().UTC().Unix(), id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return fmt.Errorf("%w: %q, or it is already revoked", ErrKeyNotFound, id)
+	}
+
+	return nil
+}
+
+// Authenticate returns the identity of the input API key, or an error if it is invalid or revoked. Keys are looked up
+// on each call, so that revocations apply immediately.
+func (s *Store) Authenticate(ctx context.Context, apiKey string) (Key, error) {
+	prefix, rest, _ := strings.Cut(apiKey, "_")
+	id, secret, ok := strings.Cut(rest, "_")
+
+	if prefix != keyPrefix || !ok || len(id) != 2*idSize {
+		return Key{}, ErrInvalidKey
+	}
+
+	var (
+		key       = Key{ID: id}
+		hash      []byte
+		createdAt int64
+		revokedAt sql.NullInt64
+	)
+
+	err := s.db.QueryRowContext(ctx, selectKeyQuery, id).Scan(&key.Name, &hash, &createdAt, &revokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Key{}, ErrInvalidKey
+		}
+
+		return Key{}, err
+	}
+
+	if sum := sha256.Sum256([]byte(secret)); subtle.ConstantTimeCompare(sum[:], hash) != 1 {
+		return Key{}, ErrInvalidKey
+	}
+
+	key.CreatedAt = time.Unix(createdAt, 0).UTC()
+
+	if revokedAt.Valid {
+		key.RevokedAt = time.Unix(revokedAt.Int64, 0).UTC()
+
+		return key, ErrRevokedKey
+	}
+
+	return key, nil
+}
@@ -0,0 +1,79 @@
+package apikeys
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/database"
+	"github.com/zalgonoise/tendigitprimes/log"
+)
+
+func newTestStore(t *testing.T) *Store {
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "keys.db"), database.ReadWritePragmas(), log.NoOp())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	s := NewStore(db)
+	require.NoError(t, s.Migrate(context.Background()))
+
+	return s
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	s := newTestStore(t)
+
+	key, apiKey, err := s.Create(ctx, "ci")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(apiKey, "pk_"+key.ID+"_"))
+
+	other, _, err := s.Create(ctx, "dashboard")
+	require.NoError(t, err)
+
+	t.Run("Authenticate", func(t *testing.T) {
+		got, err := s.Authenticate(ctx, apiKey)
+		require.NoError(t, err)
+		require.Equal(t, key, got)
+	})
+
+	t.Run("InvalidKeys", func(t *testing.T) {
+		for _, invalid := range []string{
+			"",
+			"pk_" + key.ID,
+			"pk_" + key.ID + "_wrong-secret",
+			"pk_0000000000000000_" + apiKey[len("pk_")+len(key.ID)+1:],
+			"xx" + apiKey[2:],
+		} {
+			_, err := s.Authenticate(ctx, invalid)
+			require.ErrorIs(t, err, ErrInvalidKey, invalid)
+		}
+	})
+
+	t.Run("EmptyName", func(t *testing.T) {
+		_, _, err := s.Create(ctx, " ")
+		require.ErrorIs(t, err, ErrEmptyName)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		require.NoError(t, s.Revoke(ctx, key.ID))
+		require.ErrorIs(t, s.Revoke(ctx, key.ID), ErrKeyNotFound)
+		require.ErrorIs(t, s.Revoke(ctx, "unknown"), ErrKeyNotFound)
+
+		got, err := s.Authenticate(ctx, apiKey)
+		require.ErrorIs(t, err, ErrRevokedKey)
+		require.True(t, got.Revoked())
+
+		keys, err := s.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		require.Equal(t, key.ID, keys[0].ID)
+		require.True(t, keys[0].Revoked())
+		require.Equal(t, other, keys[1])
+	})
+}
@@ -0,0 +1,289 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrNoCertificates = errors.New("no certificates found in CA bundle")
+	ErrNoPeerCert     = errors.New("peer presented no certificate")
+	ErrUntrustedCert  = errors.New("certificate is not trusted")
+)
+
+type Metrics interface {
+	IncCertificateReloads()
+	IncCertificateReloadsFailed()
+}
+
+// Files are the paths to the PEM-encoded files a Reloader loads.
+type Files struct {
+	// Cert and Key are the certificate chain and private key presented to peers. Either both or none are set.
+	Cert string
+	Key  string
+	// CA is a bundle of certificates to verify peers with: the client CAs for a server, or the root CAs for a client.
+	// Clients verify servers with the system roots if it is not set, and servers do not request client certificates.
+	CA string
+}
+
+// bundle is a set of loaded files, swapped as a whole on reload.
+type bundle struct {
+	cert *tls.Certificate
+	pool *x509.CertPool
+}
+
+// Reloader holds the certificates loaded from a set of Files, and reloads them when the files change on disk, so that
+// rotated certificates are picked up without restarting the servers.
+//
+// Its tls.Config values read the current certificates on each handshake, for both the certificate presented to peers
+// and the CA bundle they are verified against.
+type Reloader struct {
+	files   Files
+	current atomic.Pointer[bundle]
+
+	// mu serializes reloads
+	mu       sync.Mutex
+	modTimes map[string]time.Time
+
+	m      Metrics
+	logger *slog.Logger
+}
+
+// New loads the input Files, returning an error if any of them fails to load.
+func New(files Files, m Metrics, logger *slog.Logger) (*Reloader, error) {
+	r := &Reloader{
+		files:    files,
+		modTimes: make(map[string]time.Time, 3),
+		m:        m,
+		logger:   logger,
+	}
+
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload loads the files again if any of them changed since they were last loaded. On failure, the current
+// certificates are kept.
+func (r *Reloader) Reload() error {
+	changed, err := r.reload()
+
+	switch {
+	case err != nil:
+		r.m.IncCertificateReloadsFailed()
+		r.logger.Error("failed to reload certificates, keeping the current ones", slog.String("error", err.Error()))
+	case changed:
+		r.m.IncCertificateReloads()
+		r.logger.Info("reloaded certificates", slog.String("cert", r.files.Cert), slog.String("ca", r.files.CA))
+	}
+
+	return err
+}
+
+// Watch checks the files for changes every interval, reloading them when they change, until ctx is done.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// failures are logged and counted, and retried on the next tick
+			_ = r.Reload()
+		}
+	}
+}
+
+func (r *Reloader) reload() (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTimes := make(map[string]time.Time, 3)
+	changed := r.current.Load() == nil
+
+	for _, path := range []string{r.files.Cert, r.files.Key, r.files.CA} {
+		if path == "" {
+			continue
+		}
+
+		stat, err := os.Stat(path)
+		if err != nil {
+			return false, err
+		}
+
+		modTimes[path] = stat.ModTime()
+
+		if !stat.ModTime().Equal(r.modTimes[path]) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	b := &bundle{}
+
+	if r.files.Cert != "" || r.files.Key != "" {
+		cert, err := tls.LoadX509KeyPair(r.files.Cert, r.files.Key)
+		if err != nil {
+			return false, err
+		}
+
+		b.cert = &cert
+	}
+
+	if r.files.CA != "" {
+		pool, err := loadPool(r.files.CA)
+		if err != nil {
+			return false, err
+		}
+
+		b.pool = pool
+	}
+
+	r.current.Store(b)
+	r.modTimes = modTimes
+
+	return true, nil
+}
+
+func loadPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%w: %s", ErrNoCertificates, path)
+	}
+
+	return pool, nil
+}
+
+// ServerConfig returns a tls.Config for servers, presenting the current certificate. If a CA bundle is set, clients
+// are required to present a certificate issued by it, for mutual TLS.
+func (r *Reloader) ServerConfig(minVersion uint16) *tls.Config {
+	config := &tls.Config{
+		MinVersion: minVersion,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.current.Load().cert, nil
+		},
+	}
+
+	if r.files.CA == "" {
+		return config
+	}
+
+	// client certificates are verified against the current bundle, instead of a fixed ClientCAs pool, so that a
+	// reloaded bundle applies to new connections
+	config.ClientAuth = tls.RequireAnyClientCert
+	config.VerifyConnection = func(state tls.ConnectionState) error {
+		return verify(state, r.current.Load().pool, "", x509.ExtKeyUsageClientAuth)
+	}
+
+	return config
+}
+
+// ClientConfig returns a tls.Config for clients connecting to serverName, verifying the server against the current CA
+// bundle, or the system roots if none is set. The current certificate, if any, is presented to servers requesting
+// one.
+func (r *Reloader) ClientConfig(serverName string, minVersion uint16) *tls.Config {
+	return &tls.Config{
+		MinVersion: minVersion,
+		ServerName: serverName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if cert := r.current.Load().cert; cert != nil {
+				return cert, nil
+			}
+
+			return &tls.Certificate{}, nil
+		},
+		// the server is verified against the current bundle in VerifyConnection instead, so that a reloaded bundle
+		// applies to new connections
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			return verify(state, r.current.Load().pool, serverName, x509.ExtKeyUsageServerAuth)
+		},
+	}
+}
+
+// VerifyServer checks that the server's current certificate is accepted by the ClientConfig for serverName, so that
+// a client that cannot connect to the server fails upfront instead of on its first handshake.
+func (r *Reloader) VerifyServer(server *Reloader, serverName string) error {
+	return verifyCert(server.current.Load().cert, r.current.Load().pool, serverName, x509.ExtKeyUsageServerAuth)
+}
+
+// VerifyClient checks that the client's current certificate is accepted by the ServerConfig, if it requires client
+// certificates, so that a client that cannot connect to the server fails upfront instead of on its first handshake.
+func (r *Reloader) VerifyClient(client *Reloader) error {
+	if r.files.CA == "" {
+		return nil
+	}
+
+	return verifyCert(client.current.Load().cert, r.current.Load().pool, "", x509.ExtKeyUsageClientAuth)
+}
+
+// verifyCert checks a loaded certificate chain as a peer's, with verify.
+func verifyCert(cert *tls.Certificate, roots *x509.CertPool, dnsName string, usage x509.ExtKeyUsage) error {
+	if cert == nil {
+		return ErrNoPeerCert
+	}
+
+	chain := make([]*x509.Certificate, 0, len(cert.Certificate))
+
+	for _, der := range cert.Certificate {
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			return err
+		}
+
+		chain = append(chain, c)
+	}
+
+	if err := verifyChain(chain, roots, dnsName, usage); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrUntrustedCert, chain[0].Subject, err)
+	}
+
+	return nil
+}
+
+// verify checks the peer's certificate chain in state against roots, or the system roots if nil.
+func verify(state tls.ConnectionState, roots *x509.CertPool, dnsName string, usage x509.ExtKeyUsage) error {
+	return verifyChain(state.PeerCertificates, roots, dnsName, usage)
+}
+
+func verifyChain(chain []*x509.Certificate, roots *x509.CertPool, dnsName string, usage x509.ExtKeyUsage) error {
+	if len(chain) == 0 {
+		return ErrNoPeerCert
+	}
+
+	intermediates := x509.NewCertPool()
+
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSName:       dnsName,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+
+	return err
+}
@@ -0,0 +1,240 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/metrics"
+)
+
+// testCA is a throwaway certificate authority, issuing certificates for tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue writes a certificate for name with the input usage, along with its key, to dir, returning their paths.
+func (ca *testCA) issue(t *testing.T, dir, name string, usage x509.ExtKeyUsage) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPath, keyPath := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certPath, keyPath
+}
+
+func (ca *testCA) write(t *testing.T, path string) string {
+	require.NoError(t, os.WriteFile(path, ca.pem, 0o600))
+
+	return path
+}
+
+// handshake runs a TLS handshake between the server and client configs over an in-memory connection, returning the
+// client's error, or the server's if the client succeeds.
+func handshake(server, client *tls.Config) error {
+	serverConn, clientConn := net.Pipe()
+	errs := make(chan error, 1)
+
+	// the raw connections are closed instead of the TLS ones, which would block on sending a close_notify alert
+	go func() {
+		errs <- tls.Server(serverConn, server).Handshake()
+
+		_ = serverConn.Close()
+	}()
+
+	err := tls.Client(clientConn, client).Handshake()
+	_ = clientConn.Close()
+
+	if serverErr := <-errs; err == nil {
+		err = serverErr
+	}
+
+	return err
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caPath := ca.write(t, filepath.Join(dir, "ca.pem"))
+
+	serverCert, serverKey := ca.issue(t, dir, "localhost", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, dir, "client", x509.ExtKeyUsageClientAuth)
+
+	server, err := New(Files{Cert: serverCert, Key: serverKey, CA: caPath}, metrics.Noop{}, log.NoOp())
+	require.NoError(t, err)
+
+	client, err := New(Files{Cert: clientCert, Key: clientKey, CA: caPath}, metrics.Noop{}, log.NoOp())
+	require.NoError(t, err)
+
+	serverConfig := server.ServerConfig(tls.VersionTLS12)
+
+	t.Run("MutualTLS", func(t *testing.T) {
+		require.NoError(t, handshake(serverConfig, client.ClientConfig("localhost", tls.VersionTLS12)))
+	})
+
+	t.Run("NoClientCertificate", func(t *testing.T) {
+		anonymous, err := New(Files{CA: caPath}, metrics.Noop{}, log.NoOp())
+		require.NoError(t, err)
+
+		require.Error(t, handshake(serverConfig, anonymous.ClientConfig("localhost", tls.VersionTLS12)))
+	})
+
+	t.Run("WrongServerName", func(t *testing.T) {
+		require.Error(t, handshake(serverConfig, client.ClientConfig("example.com", tls.VersionTLS12)))
+	})
+
+	t.Run("MinVersion", func(t *testing.T) {
+		clientConfig := client.ClientConfig("localhost", tls.VersionTLS12)
+		clientConfig.MaxVersion = tls.VersionTLS12
+
+		require.Error(t, handshake(server.ServerConfig(tls.VersionTLS13), clientConfig))
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		// rotates the CA, along with the server certificate issued by it
+		rotated := newTestCA(t)
+		rotatedDir := t.TempDir()
+		rotatedCert, rotatedKey := rotated.issue(t, rotatedDir, "localhost", x509.ExtKeyUsageServerAuth)
+
+		rotatedClient, err := New(
+			Files{Cert: clientCert, Key: clientKey, CA: rotated.write(t, filepath.Join(rotatedDir, "ca.pem"))},
+			metrics.Noop{}, log.NoOp(),
+		)
+		require.NoError(t, err)
+
+		require.Error(t, handshake(serverConfig, rotatedClient.ClientConfig("localhost", tls.VersionTLS12)))
+
+		for src, dst := range map[string]string{rotatedCert: serverCert, rotatedKey: serverKey} {
+			data, err := os.ReadFile(src)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(dst, data, 0o600))
+
+			// file systems with coarse timestamps could otherwise leave the modification time unchanged
+			future := time.Now().Add(time.Minute)
+			require.NoError(t, os.Chtimes(dst, future, future))
+		}
+
+		require.NoError(t, server.Reload())
+		require.NoError(t, handshake(serverConfig, rotatedClient.ClientConfig("localhost", tls.VersionTLS12)))
+
+		t.Run("InvalidFiles", func(t *testing.T) {
+			require.NoError(t, os.WriteFile(serverKey, []byte("not a key"), 0o600))
+
+			future := time.Now().Add(2 * time.Minute)
+			require.NoError(t, os.Chtimes(serverKey, future, future))
+
+			// keeps serving the last certificates that loaded
+			require.Error(t, server.Reload())
+			require.NoError(t, handshake(serverConfig, rotatedClient.ClientConfig("localhost", tls.VersionTLS12)))
+		})
+	})
+}
+
+func TestReloader_Verify(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caPath := ca.write(t, filepath.Join(dir, "ca.pem"))
+
+	serverCert, serverKey := ca.issue(t, dir, "localhost", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, dir, "client", x509.ExtKeyUsageClientAuth)
+
+	server, err := New(Files{Cert: serverCert, Key: serverKey, CA: caPath}, metrics.Noop{}, log.NoOp())
+	require.NoError(t, err)
+
+	t.Run("PinnedServer", func(t *testing.T) {
+		// a client trusting the server's own certificate, regardless of its issuer
+		pinned, err := New(Files{CA: serverCert}, metrics.Noop{}, log.NoOp())
+		require.NoError(t, err)
+
+		require.NoError(t, pinned.VerifyServer(server, "localhost"))
+		require.ErrorIs(t, pinned.VerifyServer(server, "example.com"), ErrUntrustedCert)
+
+		plaintextServer, err := New(Files{Cert: serverCert, Key: serverKey}, metrics.Noop{}, log.NoOp())
+		require.NoError(t, err)
+
+		require.NoError(t, handshake(plaintextServer.ServerConfig(tls.VersionTLS12),
+			pinned.ClientConfig("localhost", tls.VersionTLS12)))
+	})
+
+	t.Run("Client", func(t *testing.T) {
+		client, err := New(Files{Cert: clientCert, Key: clientKey}, metrics.Noop{}, log.NoOp())
+		require.NoError(t, err)
+
+		require.NoError(t, server.VerifyClient(client))
+	})
+
+	t.Run("ServerCertificateAsClient", func(t *testing.T) {
+		// the server's certificate only allows server authentication
+		require.ErrorIs(t, server.VerifyClient(server), ErrUntrustedCert)
+	})
+
+	t.Run("NoClientCertificate", func(t *testing.T) {
+		anonymous, err := New(Files{CA: caPath}, metrics.Noop{}, log.NoOp())
+		require.NoError(t, err)
+
+		require.ErrorIs(t, server.VerifyClient(anonymous), ErrNoPeerCert)
+	})
+
+	t.Run("WithoutClientCA", func(t *testing.T) {
+		withoutCA, err := New(Files{Cert: serverCert, Key: serverKey}, metrics.Noop{}, log.NoOp())
+		require.NoError(t, err)
+
+		require.NoError(t, withoutCA.VerifyClient(withoutCA))
+	})
+}
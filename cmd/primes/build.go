@@ -14,6 +14,38 @@ func ExecBuild(ctx context.Context, logger *slog.Logger, args []string) (int, er
 		return 1, err
 	}
 
+	validation := database.Validation{
+		Lenient: c.Validation == config.ValidationLenient,
+		Min:     int(c.Min),
+		Max:     int(c.Max),
+		Report:  c.Report,
+	}
+
+	if c.Driver == config.DriverPostgres {
+		db, err := database.OpenPostgres(c.Output, logger)
+		if err != nil {
+			return 1, err
+		}
+
+		if err = database.PartitionPostgres(ctx, db, int(c.BlockSize), c.Input, validation, logger); err != nil {
+			return 1, err
+		}
+
+		if err = db.Close(); err != nil {
+			return 1, err
+		}
+
+		return 0, nil
+	}
+
+	if c.Format == config.FormatPacked {
+		if err = database.Pack(ctx, database.PackedBlockSize, c.Input, c.Output, validation, logger); err != nil {
+			return 1, err
+		}
+
+		return 0, nil
+	}
+
 	if !c.Partitioned {
 		logger.InfoContext(ctx, "validating output URI")
 		db, err := database.OpenSQLite(c.Output, database.ReadWritePragmas(), logger)
@@ -21,7 +53,7 @@ func ExecBuild(ctx context.Context, logger *slog.Logger, args []string) (int, er
 			return 1, err
 		}
 
-		if err = database.MigrateSQLite(ctx, db, c.Input, logger); err != nil {
+		if err = database.MigrateSQLite(ctx, db, c.Input, validation, logger); err != nil {
 			return 1, err
 		}
 
@@ -34,9 +66,29 @@ func ExecBuild(ctx context.Context, logger *slog.Logger, args []string) (int, er
 			nil
 	}
 
-	if err := database.Partition(ctx, int(c.BlockSize), c.Input, c.Output, logger); err != nil {
+	layout, err := newLayout(c.Strategy, c.BlockSize, c.Boundaries)
+	if err != nil {
+		return 1, err
+	}
+
+	if err = database.Partition(ctx, layout, c.Workers, c.Input, c.Output, validation, logger); err != nil {
 		return 1, err
 	}
 
 	return 0, nil
 }
+
+// newLayout returns the database.Layout for the input strategy, reading the boundaries file if it is required.
+func newLayout(strategy config.Strategy, blockSize config.BlockSize, boundaries string) (database.Layout, error) {
+	layout := database.Layout{Strategy: database.Strategy(strategy), BlockSize: int(blockSize)}
+
+	if strategy != config.StrategyFile {
+		return layout, nil
+	}
+
+	var err error
+
+	layout.Boundaries, err = database.ReadBoundaries(boundaries)
+
+	return layout, err
+}
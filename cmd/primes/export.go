@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/zalgonoise/tendigitprimes/config"
+	"github.com/zalgonoise/tendigitprimes/database"
+)
+
+func ExecExport(ctx context.Context, logger *slog.Logger, args []string) (int, error) {
+	c, err := config.NewExport(args)
+	if err != nil {
+		return 1, err
+	}
+
+	logger.InfoContext(ctx, "exporting dataset",
+		slog.String("input", c.Input),
+		slog.String("output", c.Output),
+		slog.String("format", string(c.Format)),
+		slog.String("compression", string(c.Compression)),
+		slog.Int64("min", int64(c.Min)),
+		slog.Int64("max", int64(c.Max)),
+	)
+
+	if _, err = database.Export(ctx, c.Input, c.Output, database.ExportOptions{
+		Format:      database.ExportFormat(c.Format),
+		Compression: database.Compression(c.Compression),
+		Min:         int(c.Min),
+		Max:         int(c.Max),
+	}, logger); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/zalgonoise/tendigitprimes/config"
+	"github.com/zalgonoise/tendigitprimes/database"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+func ExecGenerate(ctx context.Context, logger *slog.Logger, args []string) (int, error) {
+	c, err := config.NewGenerate(args)
+	if err != nil {
+		return 1, err
+	}
+
+	start := time.Now()
+
+	logger.InfoContext(ctx, "generating primes",
+		slog.Int64("limit", int64(c.Limit)),
+		slog.Int("workers", c.Workers),
+		slog.Bool("partitioned", c.Partitioned),
+	)
+
+	if c.Partitioned {
+		layout := database.WidthLayout(int(c.BlockSize))
+
+		if err = database.PartitionSieve(ctx, int(c.Limit), layout, c.Workers, c.Output, logger); err != nil {
+			return 1, err
+		}
+
+		return 0, nil
+	}
+
+	w, err := database.NewShardWriter(c.Output, database.ShardSize)
+	if err != nil {
+		return 1, err
+	}
+
+	var total int
+
+	if err = sieve.Generate(ctx, int64(c.Limit), c.Workers, sieve.DefaultSegmentSize, func(primes []int64) error {
+		total += len(primes)
+
+		return w.Write(primes...)
+	}); err != nil {
+		_ = w.Close()
+
+		return 1, err
+	}
+
+	if err = w.Close(); err != nil {
+		return 1, err
+	}
+
+	logger.InfoContext(ctx, "operation completed",
+		slog.Int("num_primes", total),
+		slog.Duration("time_elapsed", time.Since(start)),
+	)
+
+	return 0, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/zalgonoise/tendigitprimes/apikeys"
+	"github.com/zalgonoise/tendigitprimes/config"
+	"github.com/zalgonoise/tendigitprimes/database"
+)
+
+func ExecKeys(ctx context.Context, logger *slog.Logger, args []string) (int, error) {
+	c, err := config.NewKeys(args)
+	if err != nil {
+		return 1, err
+	}
+
+	db, err := database.OpenSQLite(c.DB, database.ReadWritePragmas(), logger)
+	if err != nil {
+		return 1, err
+	}
+
+	store := apikeys.NewStore(db)
+
+	if err = store.Migrate(ctx); err != nil {
+		return 1, errors.Join(err, db.Close())
+	}
+
+	switch c.Action {
+	case config.KeysCreate:
+		err = createKey(ctx, logger, store, c.Name)
+	case config.KeysList:
+		err = listKeys(ctx, store)
+	case config.KeysRevoke:
+		if err = store.Revoke(ctx, c.ID); err == nil {
+			logger.InfoContext(ctx, "revoked API key", slog.String("api_key_id", c.ID))
+		}
+	}
+
+	if err = errors.Join(err, db.Close()); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// createKey prints the new API key to stdout, as it is the only time it is available.
+func createKey(ctx context.Context, logger *slog.Logger, store *apikeys.Store, name string) error {
+	key, apiKey, err := store.Create(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "created API key",
+		slog.String("api_key_id", key.ID),
+		slog.String("api_key_name", key.Name),
+	)
+
+	_, err = fmt.Fprintln(os.Stdout, apiKey)
+
+	return err
+}
+
+func listKeys(ctx context.Context, store *apikeys.Store) error {
+	keys, err := store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "ID\tNAME\tCREATED\tREVOKED")
+
+	for _, key := range keys {
+		revoked := "-"
+		if key.Revoked() {
+			revoked = key.RevokedAt.Format(time.RFC3339)
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", key.ID, key.Name, key.CreatedAt.Format(time.RFC3339), revoked)
+	}
+
+	return w.Flush()
+}
@@ -4,14 +4,19 @@ import (
 	"github.com/zalgonoise/x/cli"
 )
 
-var modes = []string{"serve", "build"}
+var modes = []string{"serve", "build", "generate", "verify", "repartition", "export", "keys"}
 
 func main() {
 	runner := cli.NewRunner("primes",
 		cli.WithOneOf(modes...),
 		cli.WithExecutors(map[string]cli.Executor{
-			"serve": cli.Executable(ExecServe),
-			"build": cli.Executable(ExecBuild),
+			"serve":       cli.Executable(ExecServe),
+			"build":       cli.Executable(ExecBuild),
+			"generate":    cli.Executable(ExecGenerate),
+			"verify":      cli.Executable(ExecVerify),
+			"repartition": cli.Executable(ExecRepartition),
+			"export":      cli.Executable(ExecExport),
+			"keys":        cli.Executable(ExecKeys),
 		}),
 	)
 
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/zalgonoise/tendigitprimes/config"
+	"github.com/zalgonoise/tendigitprimes/database"
+)
+
+func ExecRepartition(ctx context.Context, logger *slog.Logger, args []string) (int, error) {
+	c, err := config.NewRepartition(args)
+	if err != nil {
+		return 1, err
+	}
+
+	if !c.Partitioned {
+		logger.InfoContext(ctx, "merging dataset", slog.String("input", c.Input), slog.String("output", c.Output))
+
+		if err = database.Merge(ctx, c.Input, c.Output, logger); err != nil {
+			return 1, err
+		}
+
+		return 0, nil
+	}
+
+	layout, err := newLayout(c.Strategy, c.BlockSize, c.Boundaries)
+	if err != nil {
+		return 1, err
+	}
+
+	logger.InfoContext(ctx, "repartitioning dataset",
+		slog.String("input", c.Input),
+		slog.String("output", c.Output),
+		slog.String("strategy", string(c.Strategy)),
+		slog.Int("block_size", int(c.BlockSize)),
+	)
+
+	if err = database.Repartition(ctx, c.Input, layout, c.Workers, c.Output, logger); err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
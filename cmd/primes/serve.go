@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -10,12 +11,18 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	grpcauth "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/auth"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/zalgonoise/tendigitprimes/apikeys"
+	"github.com/zalgonoise/tendigitprimes/certs"
 	"github.com/zalgonoise/tendigitprimes/config"
 	"github.com/zalgonoise/tendigitprimes/database"
 	"github.com/zalgonoise/tendigitprimes/grpcserver"
@@ -24,15 +31,37 @@ import (
 	"github.com/zalgonoise/tendigitprimes/metrics"
 	pb "github.com/zalgonoise/tendigitprimes/pb/primes/v1"
 	"github.com/zalgonoise/tendigitprimes/primes"
+	"github.com/zalgonoise/tendigitprimes/ratelimit"
 	"github.com/zalgonoise/tendigitprimes/repository"
+	"github.com/zalgonoise/tendigitprimes/repository/cache"
+	"github.com/zalgonoise/tendigitprimes/repository/composite"
+	"github.com/zalgonoise/tendigitprimes/repository/packed"
+	"github.com/zalgonoise/tendigitprimes/repository/pool"
+	"github.com/zalgonoise/tendigitprimes/repository/postgres"
+	"github.com/zalgonoise/tendigitprimes/repository/reload"
+	"github.com/zalgonoise/tendigitprimes/repository/sieve"
 	"github.com/zalgonoise/tendigitprimes/repository/sqlite"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
 // shutdownTimeout sets a duration for servers to terminate gracefully
 const shutdownTimeout = 1 * time.Minute
 
+var ErrEmptyURIFile = errors.New("database URI file is empty")
+
+// gatewayRoutes maps the gateway's URL paths to the gRPC methods they call, to rate limit them by method
+var gatewayRoutes = map[string]string{
+	"/v1/primes/rand":     pb.Primes_Random_FullMethodName,
+	"/v1/primes":          pb.Primes_List_FullMethodName,
+	"/v1/primes/is-prime": pb.Primes_IsPrime_FullMethodName,
+	"/v1/primes/next":     pb.Primes_Next_FullMethodName,
+	"/v1/primes/previous": pb.Primes_Previous_FullMethodName,
+	"/v1/primes/count":    pb.Primes_Count_FullMethodName,
+	"/v1/primes/nth":      pb.Primes_Nth_FullMethodName,
+}
+
 type Repository interface {
 	Random(ctx context.Context, min, max int64) (int64, error)
 	List(ctx context.Context, min, max, limit int64) ([]int64, error)
@@ -45,59 +74,238 @@ func ExecServe(ctx context.Context, logger *slog.Logger, args []string) (int, er
 		return 1, err
 	}
 
-	var (
-		db   *sql.DB
-		repo Repository
-	)
+	m := metrics.NewMetrics()
+
+	logger = log.From(c.LogLevel, logger.Handler())
+
+	reloader, repo, lookups, err := newRepositories(ctx, c, m, logger)
+	if err != nil {
+		return 1, err
+	}
+
+	// exports the stats of the current dataset's database, if any
+	m.RegisterCollector(reloader)
+
+	m.InitRequestsMetrics("2", "9999999999")
+
+	service := primes.NewService(repo, lookups, logger, m)
+
+	serverTLS, gatewayTLS, err := newTLSConfigs(ctx, &c.Server.TLS, m, logger)
+	if err != nil {
+		return 1, err
+	}
+
+	auth, closeAuth, err := newAuth(ctx, &c.Auth, m, logger)
+	if err != nil {
+		return 1, err
+	}
 
-	switch c.Database.Partitioned {
-	case true:
-		db, err = database.AttachSQLite(c.Database.URI, database.ReadOnlyPragmas(), logger)
+	defer closeAuth()
+
+	limiter, err := newLimiter(ctx, &c.RateLimit, m, logger)
+	if err != nil {
+		return 1, err
+	}
+
+	// requests are authenticated first, to be limited by their API key
+	var middleware []func(http.Handler) http.Handler
+	if auth != nil {
+		middleware = append(middleware, apikeys.Middleware(auth, m, logger, "/ready", "/metrics"))
+	}
+
+	if limiter != nil {
+		middleware = append(middleware, limiter.Middleware(gatewayRoutes))
+	}
+
+	server, err := httpserver.NewServer(fmt.Sprintf(":%d", c.Server.HTTPPort), serverTLS, middleware...)
+	if err != nil {
+		return 1, err
+	}
+
+	if err := registerMetrics(server, m); err != nil {
+		return 1, err
+	}
+
+	grpcServer, err := runGRPCServer(ctx, logger, &c.Server, service, server, m, auth, limiter, serverTLS, gatewayTLS)
+	if err != nil {
+		return 1, err
+	}
+
+	go runHTTPServer(ctx, logger, &c.Server, server)
+
+	return shutdown(logger, server, grpcServer, repo, reloader)
+}
+
+// newRepositories opens the configured dataset behind a Reloader, returning it along with the repository serving Random
+// and List calls, wrapped with the fallback and pool if configured, and the cache serving lookups.
+func newRepositories(
+	ctx context.Context, c *config.Primes, m *metrics.Metrics, logger *slog.Logger,
+) (*reload.Reloader, Repository, *cache.Cache, error) {
+	reloader, err := reload.New(ctx, func(context.Context) (reload.Dataset, error) {
+		return openDataset(c, m, logger)
+	}, m, logger)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var repo Repository = reloader
+
+	// lookups are cached for the current dataset, and dropped once another one is swapped in
+	lookups := cache.New(reloader, strconv.FormatUint(reloader.Version(), 10), c.Cache.MaxEntries, m)
+
+	reloader.OnSwap(func(_ context.Context, version uint64) {
+		lookups.SetVersion(strconv.FormatUint(version, 10))
+	})
+
+	if c.Database.Fallback == config.FormatSieve {
+		fallback, err := sieve.NewRepository()
 		if err != nil {
-			return 1, err
+			return nil, nil, nil, err
 		}
 
-		repo, err = sqlite.NewPartitionSet(db)
-		if err != nil {
-			return 1, err
+		primary := string(c.Database.Format)
+		if c.Database.Driver == config.DriverPostgres {
+			primary = string(c.Database.Driver)
 		}
-	default:
-		db, err = database.OpenSQLite(c.Database.URI, database.ReadOnlyPragmas(), logger)
+
+		repo, err = composite.New([]composite.Backend{
+			{Name: primary, Repo: repo},
+			{Name: string(c.Database.Fallback), Repo: fallback},
+		}, c.Database.HedgeDelay, m, logger)
 		if err != nil {
-			return 1, err
+			return nil, nil, nil, err
 		}
+	}
 
-		repo, err = sqlite.NewRepository(db)
-		if err != nil {
-			return 1, err
+	if len(c.Pool.Ranges) > 0 {
+		ranges := make([]pool.Range, 0, len(c.Pool.Ranges))
+
+		for _, r := range c.Pool.Ranges {
+			ranges = append(ranges, pool.Range{Min: r.Min, Max: r.Max, Size: r.Size})
 		}
+
+		p := pool.New(repo, ranges, m, logger)
+
+		// primes sampled from the previous dataset are dropped, for the buffers to be refilled from the new one
+		reloader.OnSwap(func(context.Context, uint64) {
+			p.Flush()
+		})
+
+		repo = p
 	}
 
-	logger = log.From(c.LogLevel, logger.Handler())
-	m := metrics.NewMetrics()
-	m.RegisterCollector(collectors.NewDBStatsCollector(db, "primes"))
-	m.RegisterCollector(repository.NewPingCollector(db, "primes"))
-	m.InitRequestsMetrics("2", "9999999999")
+	return reloader, repo, lookups, nil
+}
+
+// openDataset opens the repository for the configured dataset. It is called on startup, and again on each reload,
+// picking up any changes to the dataset under the configured URI, or to the URI in the configured URI file.
+func openDataset(c *config.Primes, m *metrics.Metrics, logger *slog.Logger) (reload.Dataset, error) {
+	uri, err := datasetURI(c.Database)
+	if err != nil {
+		return reload.Dataset{}, err
+	}
+
+	var (
+		db         *sql.DB
+		partitions map[string]*sql.DB
+		repo       Repository
+	)
+
+	// partitions are verified once against the manifest, with the configured hashing
+	verification := database.VerifySampled
+	if c.Database.Verify == config.VerifyFull {
+		verification = database.VerifyFull
+	}
 
-	service := primes.NewService(repo, logger, m)
+	switch {
+	case c.Database.Driver == config.DriverPostgres:
+		if db, err = database.OpenPostgres(uri, logger); err != nil {
+			return reload.Dataset{}, err
+		}
+
+		repo, err = postgres.NewRepository(db)
+	case c.Database.Format == config.FormatPacked:
+		repo, err = packed.NewRepository(uri)
+	case c.Database.Format == config.FormatSieve:
+		repo, err = sieve.NewRepository()
+	case c.Database.Partitioned && c.Database.MaxOpen > 0:
+		// partitions are opened on demand, so they are verified upfront
+		if err = database.VerifyManifest(uri, verification, logger); err != nil {
+			return reload.Dataset{}, err
+		}
+
+		if db, err = database.OpenIndex(uri, database.ReadOnlyPragmas(), logger); err != nil {
+			return reload.Dataset{}, err
+		}
+
+		repo, err = sqlite.NewLazyPartitionPool(db, func(id string) (*sql.DB, error) {
+			return database.OpenPartition(uri, id, database.ReadOnlyPragmas())
+		}, c.Database.MaxOpen, m)
+	case c.Database.Partitioned && c.Database.Detached:
+		if db, partitions, err = database.OpenPartitions(
+			uri, database.ReadOnlyPragmas(), verification, logger); err != nil {
+			return reload.Dataset{}, err
+		}
+
+		repo, err = sqlite.NewPartitionPool(db, partitions)
+	case c.Database.Partitioned:
+		if db, err = database.AttachSQLite(
+			uri, database.ReadOnlyPragmas(), verification, logger); err != nil {
+			return reload.Dataset{}, err
+		}
+
+		repo, err = sqlite.NewPartitionSet(db)
+	default:
+		if db, err = database.OpenSQLite(uri, database.ReadOnlyPragmas(), logger); err != nil {
+			return reload.Dataset{}, err
+		}
+
+		repo, err = sqlite.NewRepository(db)
+	}
 
-	server, err := httpserver.NewServer(fmt.Sprintf(":%d", c.Server.HTTPPort))
 	if err != nil {
-		return 1, err
+		if db != nil {
+			err = errors.Join(err, db.Close())
+		}
+
+		for _, partition := range partitions {
+			err = errors.Join(err, partition.Close())
+		}
+
+		return reload.Dataset{}, err
 	}
 
-	if err := registerMetrics(server, m); err != nil {
-		return 1, err
+	if db == nil {
+		return reload.Dataset{Repo: repo}, nil
+	}
+
+	return reload.Dataset{
+		Repo: repo,
+		Collectors: []prometheus.Collector{
+			collectors.NewDBStatsCollector(db, "primes"),
+			repository.NewPingCollector(db, "primes"),
+		},
+	}, nil
+}
+
+// datasetURI returns the URI of the dataset to open, reading it from the URI file if one is configured, so that
+// reloads can attach a different dataset.
+func datasetURI(c config.Database) (string, error) {
+	if c.URIFile == "" {
+		return c.URI, nil
 	}
 
-	grpcServer, err := runGRPCServer(ctx, logger, &c.Server, service, server, m)
+	data, err := os.ReadFile(c.URIFile)
 	if err != nil {
-		return 1, err
+		return "", err
 	}
 
-	go runHTTPServer(ctx, logger, &c.Server, server)
+	uri := strings.TrimSpace(string(data))
+	if uri == "" {
+		return "", fmt.Errorf("%w: %s", ErrEmptyURIFile, c.URIFile)
+	}
 
-	return shutdown(server, grpcServer, repo)
+	return uri, nil
 }
 
 func registerMetrics(
@@ -129,16 +337,35 @@ func runGRPCServer(
 	primes pb.PrimesServer,
 	httpServer *httpserver.Server,
 	m *metrics.Metrics,
+	auth *apikeys.Store,
+	limiter *ratelimit.Limiter,
+	serverTLS, gatewayTLS *tls.Config,
 ) (*grpcserver.Server, error) {
 	loggingOpts := []logging.Option{
 		logging.WithLogOnEvents(logging.StartCall, logging.FinishCall),
 	}
 
-	grpcSrv := grpcserver.NewServer(m, []grpc.UnaryServerInterceptor{
-		logging.UnaryServerInterceptor(log.InterceptorLogger(logger), loggingOpts...),
-	}, []grpc.StreamServerInterceptor{
-		logging.StreamServerInterceptor(log.InterceptorLogger(logger), loggingOpts...),
-	})
+	unary := make([]grpc.UnaryServerInterceptor, 0, 3)
+	stream := make([]grpc.StreamServerInterceptor, 0, 3)
+
+	// authenticating first places the caller's identity in the context of the calls' logs
+	if auth != nil {
+		authFunc := apikeys.AuthFunc(auth, m, logger)
+
+		unary = append(unary, grpcauth.UnaryServerInterceptor(authFunc))
+		stream = append(stream, grpcauth.StreamServerInterceptor(authFunc))
+	}
+
+	unary = append(unary, logging.UnaryServerInterceptor(log.InterceptorLogger(logger), loggingOpts...))
+	stream = append(stream, logging.StreamServerInterceptor(log.InterceptorLogger(logger), loggingOpts...))
+
+	// limiting after logging keeps a record of the rejected calls
+	if limiter != nil {
+		unary = append(unary, limiter.UnaryServerInterceptor())
+		stream = append(stream, limiter.StreamServerInterceptor())
+	}
+
+	grpcSrv := grpcserver.NewServer(m, serverTLS, unary, stream)
 
 	grpcSrv.RegisterPrimesServer(primes)
 	logger.InfoContext(ctx, "listening on gRPC", slog.Int("port", cfg.GRPCPort))
@@ -159,10 +386,19 @@ func runGRPCServer(
 		}
 	}()
 
-	grpcClient, err := grpc.Dial(
-		fmt.Sprintf("localhost:%d", cfg.GRPCPort),
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	creds := insecure.NewCredentials()
+	if gatewayTLS != nil {
+		creds = credentials.NewTLS(gatewayTLS)
+	}
+
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+
+	// the gateway's calls are limited by the HTTP middleware, for their original clients
+	if limiter != nil {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(limiter.GatewayCredentials()))
+	}
+
+	grpcClient, err := grpc.Dial(fmt.Sprintf("localhost:%d", cfg.GRPCPort), dialOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -177,6 +413,115 @@ func runGRPCServer(
 	return grpcSrv, nil
 }
 
+// newTLSConfigs returns the TLS configs for the servers, and for the gateway's connection to the gRPC server, or nil
+// configs if TLS is disabled. Their certificates are reloaded whenever their files change on disk.
+func newTLSConfigs(
+	ctx context.Context,
+	cfg *config.TLS,
+	m *metrics.Metrics,
+	logger *slog.Logger,
+) (serverTLS, gatewayTLS *tls.Config, err error) {
+	if !cfg.Enabled() {
+		return nil, nil, nil
+	}
+
+	server, err := certs.New(certs.Files{Cert: cfg.Cert, Key: cfg.Key, CA: cfg.ClientCA}, m, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gateway, err := certs.New(certs.Files{Cert: cfg.GatewayCert, Key: cfg.GatewayKey, CA: cfg.GatewayCA}, m, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the gateway's connection is lazy, so a mismatch would otherwise only surface as failed HTTP requests
+	if err = gateway.VerifyServer(server, cfg.GatewayServerName); err != nil {
+		return nil, nil, fmt.Errorf("verifying the gRPC server's certificate for the gateway: %w", err)
+	}
+
+	if err = server.VerifyClient(gateway); err != nil {
+		return nil, nil, fmt.Errorf("verifying the gateway's client certificate for the gRPC server: %w", err)
+	}
+
+	go server.Watch(ctx, cfg.ReloadInterval)
+	go gateway.Watch(ctx, cfg.ReloadInterval)
+
+	logger.InfoContext(ctx, "serving TLS",
+		slog.Bool("mutual_tls", cfg.ClientCA != ""),
+		slog.Duration("reload_interval", cfg.ReloadInterval),
+	)
+
+	return server.ServerConfig(uint16(cfg.MinVersion)),
+		gateway.ClientConfig(cfg.GatewayServerName, uint16(cfg.MinVersion)),
+		nil
+}
+
+// newAuth opens the API keys store, or returns a nil store if authentication is disabled. The keys database must
+// already exist, as created by the keys command, so that a mistyped path does not leave the servers without keys.
+func newAuth(
+	ctx context.Context,
+	cfg *config.Auth,
+	m *metrics.Metrics,
+	logger *slog.Logger,
+) (*apikeys.Store, func(), error) {
+	if cfg.Keys == "" {
+		return nil, func() {}, nil
+	}
+
+	if _, err := os.Stat(cfg.Keys); err != nil {
+		return nil, nil, fmt.Errorf("opening API keys database: %w", err)
+	}
+
+	db, err := database.OpenSQLite(cfg.Keys, database.ReadWritePragmas(), logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store := apikeys.NewStore(db)
+
+	if err = store.Migrate(ctx); err != nil {
+		return nil, nil, errors.Join(err, db.Close())
+	}
+
+	// exports the keys database's stats alongside the dataset's
+	m.RegisterCollector(collectors.NewDBStatsCollector(db, "api_keys"))
+
+	logger.InfoContext(ctx, "authenticating with API keys", slog.String("keys", cfg.Keys))
+
+	return store, func() {
+		if err := db.Close(); err != nil {
+			logger.WarnContext(ctx, "failed to close API keys database", slog.String("error", err.Error()))
+		}
+	}, nil
+}
+
+// newLimiter returns a rate limiter for the configured limits, or nil if rate limiting is disabled.
+func newLimiter(
+	ctx context.Context,
+	cfg *config.RateLimit,
+	m *metrics.Metrics,
+	logger *slog.Logger,
+) (*ratelimit.Limiter, error) {
+	if len(cfg.Limits) == 0 {
+		return nil, nil
+	}
+
+	limits := make(ratelimit.Limits, len(cfg.Limits))
+
+	for method, limit := range cfg.Limits {
+		limits[method] = ratelimit.Limit{Rate: limit.Rate, Burst: limit.Burst}
+
+		logger.InfoContext(ctx, "rate limiting calls",
+			slog.String("method", method),
+			slog.Float64("rate", limit.Rate),
+			slog.Int("burst", limit.Burst),
+		)
+	}
+
+	return ratelimit.New(limits, m, logger)
+}
+
 func runHTTPServer(
 	ctx context.Context, logger *slog.Logger,
 	cfg *config.Server,
@@ -195,14 +540,25 @@ func runHTTPServer(
 }
 
 func shutdown(
+	logger *slog.Logger,
 	httpServer *httpserver.Server,
 	gRPCServer *grpcserver.Server,
 	repo primes.Repository,
+	reloader *reload.Reloader,
 ) (int, error) {
 	signalChannel := make(chan os.Signal, 1)
-	signal.Notify(signalChannel, os.Interrupt, syscall.SIGTERM)
+	signal.Notify(signalChannel, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	for sig := range signalChannel {
+		if sig != syscall.SIGHUP {
+			break
+		}
 
-	<-signalChannel
+		logger.Info("received SIGHUP, reloading dataset")
+
+		// failures are logged by the reloader, which keeps serving the current dataset
+		_ = reloader.Reload(context.Background())
+	}
 
 	shutdownContext, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
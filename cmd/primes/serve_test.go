@@ -0,0 +1,415 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/apikeys"
+	"github.com/zalgonoise/tendigitprimes/certs"
+	"github.com/zalgonoise/tendigitprimes/config"
+	"github.com/zalgonoise/tendigitprimes/database"
+	"github.com/zalgonoise/tendigitprimes/httpserver"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/metrics"
+	pb "github.com/zalgonoise/tendigitprimes/pb/primes/v1"
+	"github.com/zalgonoise/tendigitprimes/primes"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testRepository struct{}
+
+func (testRepository) Random(context.Context, int64, int64) (int64, error) { return 7, nil }
+
+func (testRepository) List(context.Context, int64, int64, int64) ([]int64, error) {
+	return []int64{7}, nil
+}
+
+func (testRepository) Close() error { return nil }
+
+// testCA is a throwaway certificate authority, issuing certificates for tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	path string
+}
+
+func newTestCA(t *testing.T, dir string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+
+	return &testCA{cert: cert, key: key, path: path}
+}
+
+// issue writes a certificate for localhost with the input usage, along with its key, to dir, returning their paths.
+func (ca *testCA) issue(t *testing.T, dir, name string, usage x509.ExtKeyUsage) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPath, keyPath := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return certPath, keyPath
+}
+
+func freePort(t *testing.T) int {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	port := lis.Addr().(*net.TCPAddr).Port
+	require.NoError(t, lis.Close())
+
+	return port
+}
+
+// serveGateway starts the gRPC server and the HTTP gateway with the TLS flags in args, returning the gateway's URL.
+func serveGateway(t *testing.T, args ...string) (string, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	httpPort, grpcPort := freePort(t), freePort(t)
+
+	c, err := config.NewPrimes(append([]string{
+		"-server.http-port", strconv.Itoa(httpPort),
+		"-server.grpc-port", strconv.Itoa(grpcPort),
+	}, args...))
+	require.NoError(t, err)
+
+	m := metrics.NewMetrics()
+	logger := log.NoOp()
+
+	serverTLS, gatewayTLS, err := newTLSConfigs(ctx, &c.Server.TLS, m, logger)
+	if err != nil {
+		return "", err
+	}
+
+	server, err := httpserver.NewServer(fmt.Sprintf(":%d", c.Server.HTTPPort), serverTLS)
+	require.NoError(t, err)
+
+	grpcServer, err := runGRPCServer(ctx, logger, &c.Server, primes.NewService(testRepository{}, nil, logger, m),
+		server, m, nil, nil, serverTLS, gatewayTLS)
+	require.NoError(t, err)
+
+	go runHTTPServer(ctx, logger, &c.Server, server)
+
+	t.Cleanup(func() {
+		require.NoError(t, server.Shutdown(context.Background()))
+		grpcServer.Shutdown()
+	})
+
+	return fmt.Sprintf("https://localhost:%d", c.Server.HTTPPort), nil
+}
+
+// getRandom calls the Random RPC through the gateway, returning the response's status code.
+func getRandom(t *testing.T, client *http.Client, url string) (int, error) {
+	var (
+		res *http.Response
+		err error
+	)
+
+	// the HTTP server starts listening in the background
+	require.Eventually(t, func() bool {
+		res, err = client.Get(url + "/v1/primes/rand?min=2&max=10")
+
+		var opErr *net.OpError
+
+		return !errors.As(err, &opErr) || opErr.Op != "dial"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer res.Body.Close()
+
+	_, err = io.Copy(io.Discard, res.Body)
+
+	return res.StatusCode, err
+}
+
+func TestGatewayTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, dir)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	// the server's certificate is issued by a private CA, and only allows server authentication
+	serverCert, serverKey := ca.issue(t, dir, "server", x509.ExtKeyUsageServerAuth)
+	gatewayCert, gatewayKey := ca.issue(t, dir, "gateway", x509.ExtKeyUsageClientAuth)
+	clientCert, clientKey := ca.issue(t, dir, "client", x509.ExtKeyUsageClientAuth)
+
+	clientPair, err := tls.LoadX509KeyPair(clientCert, clientKey)
+	require.NoError(t, err)
+
+	t.Run("TLS", func(t *testing.T) {
+		url, err := serveGateway(t, "-tls.cert", serverCert, "-tls.key", serverKey)
+		require.NoError(t, err)
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+
+		code, err := getRandom(t, client, url)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("MutualTLS", func(t *testing.T) {
+		url, err := serveGateway(t,
+			"-tls.cert", serverCert, "-tls.key", serverKey, "-tls.client-ca", ca.path,
+			"-tls.gateway-cert", gatewayCert, "-tls.gateway-key", gatewayKey,
+		)
+		require.NoError(t, err)
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      pool,
+			Certificates: []tls.Certificate{clientPair},
+		}}}
+
+		code, err := getRandom(t, client, url)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, code)
+
+		anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+
+		_, err = getRandom(t, anonymous, url)
+		require.Error(t, err)
+	})
+
+	t.Run("MutualTLSWithServerCertificate", func(t *testing.T) {
+		// the gateway would present the server's certificate, which the gRPC server rejects as a client certificate
+		_, err := serveGateway(t, "-tls.cert", serverCert, "-tls.key", serverKey, "-tls.client-ca", ca.path)
+		require.ErrorIs(t, err, certs.ErrUntrustedCert)
+	})
+}
+
+func TestGatewayAPIKeys(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	keys := filepath.Join(t.TempDir(), "keys.db")
+
+	db, err := database.OpenSQLite(keys, database.ReadWritePragmas(), log.NoOp())
+	require.NoError(t, err)
+
+	store := apikeys.NewStore(db)
+	require.NoError(t, store.Migrate(ctx))
+
+	_, apiKey, err := store.Create(ctx, "test")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	httpPort := freePort(t)
+
+	c, err := config.NewPrimes([]string{
+		"-server.http-port", strconv.Itoa(httpPort),
+		"-server.grpc-port", strconv.Itoa(freePort(t)),
+		"-auth.keys", keys,
+	})
+	require.NoError(t, err)
+
+	m := metrics.NewMetrics()
+	logger := log.NoOp()
+
+	auth, closeAuth, err := newAuth(ctx, &c.Auth, m, logger)
+	require.NoError(t, err)
+
+	defer closeAuth()
+
+	server, err := httpserver.NewServer(fmt.Sprintf(":%d", c.Server.HTTPPort), nil,
+		apikeys.Middleware(auth, m, logger, "/ready"))
+	require.NoError(t, err)
+
+	// the gRPC server authenticates the gateway's calls again, from the API key the gateway forwards to it
+	grpcServer, err := runGRPCServer(ctx, logger, &c.Server, primes.NewService(testRepository{}, nil, logger, m),
+		server, m, auth, nil, nil, nil)
+	require.NoError(t, err)
+
+	go runHTTPServer(ctx, logger, &c.Server, server)
+
+	defer func() {
+		require.NoError(t, server.Shutdown(context.Background()))
+		grpcServer.Shutdown()
+	}()
+
+	get := func(header, value string) int {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+			fmt.Sprintf("http://localhost:%d/v1/primes/rand?min=2&max=10", httpPort), http.NoBody)
+		require.NoError(t, err)
+
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+
+		var res *http.Response
+
+		// the HTTP server starts listening in the background
+		require.Eventually(t, func() bool {
+			res, err = http.DefaultClient.Do(req)
+
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+
+		defer res.Body.Close()
+
+		_, err = io.Copy(io.Discard, res.Body)
+		require.NoError(t, err)
+
+		return res.StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, get(apikeys.Header, apiKey))
+	require.Equal(t, http.StatusOK, get("x-api-key", apiKey))
+	require.Equal(t, http.StatusOK, get("Authorization", "Bearer "+apiKey))
+	require.Equal(t, http.StatusUnauthorized, get(apikeys.Header, "pk_invalid"))
+	require.Equal(t, http.StatusUnauthorized, get("", ""))
+}
+
+func TestLookups(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Sieve", func(t *testing.T) {
+		c, err := config.NewPrimes([]string{"-db.format", config.FormatSieve})
+		require.NoError(t, err)
+
+		m := metrics.NewMetrics()
+
+		reloader, repo, lookups, err := newRepositories(ctx, c, m, log.NoOp())
+		require.NoError(t, err)
+
+		defer reloader.Close()
+
+		service := primes.NewService(repo, lookups, log.NoOp(), m)
+
+		res, err := service.IsPrime(ctx, &pb.IsPrimeRequest{Number: 97})
+		require.NoError(t, err)
+		require.True(t, res.GetPrime())
+
+		res, err = service.IsPrime(ctx, &pb.IsPrimeRequest{Number: 91})
+		require.NoError(t, err)
+		require.False(t, res.GetPrime())
+
+		// the sieve format only supports IsPrime
+		_, err = service.Next(ctx, &pb.NextRequest{Number: 97})
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
+
+// newTestDataset partitions the primes up to max into dir, returning how many there are.
+func newTestDataset(t *testing.T, dir string, max int64) int64 {
+	ns := sieve.BasePrimes(max)
+
+	data := make([]int, len(ns))
+	for i := range ns {
+		data[i] = int(ns[i])
+	}
+
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	require.NoError(t, database.PartitionData(
+		context.Background(), data, database.WidthLayout(100_000), 2, dir, log.NoOp()))
+
+	return int64(len(ns))
+}
+
+func TestReload(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	v1 := filepath.Join(dir, "partitions-v1")
+	v2 := filepath.Join(dir, "partitions-v2")
+	total1 := newTestDataset(t, v1, 200_000)
+	total2 := newTestDataset(t, v2, 300_000)
+
+	uriFile := filepath.Join(dir, "current")
+	require.NoError(t, os.WriteFile(uriFile, []byte(v1+"\n"), 0o600))
+
+	c, err := config.NewPrimes([]string{"-db.uri-file", uriFile, "-db.partitioned", "-db.detached"})
+	require.NoError(t, err)
+
+	m := metrics.NewMetrics()
+
+	reloader, repo, lookups, err := newRepositories(ctx, c, m, log.NoOp())
+	require.NoError(t, err)
+
+	defer reloader.Close()
+
+	service := primes.NewService(repo, lookups, log.NoOp(), m)
+
+	count := func() int64 {
+		res, err := service.Count(ctx, &pb.CountRequest{Min: 2, Max: 300_000})
+		require.NoError(t, err)
+
+		return res.GetCount()
+	}
+
+	require.Equal(t, total1, count())
+
+	// the URI file is read again on reload, attaching the new partitions directory
+	require.NoError(t, os.WriteFile(uriFile, []byte(v2+"\n"), 0o600))
+	require.NoError(t, reloader.Reload(ctx))
+	require.Equal(t, total2, count())
+
+	// a missing dataset fails to open, and the current one keeps serving
+	require.NoError(t, os.WriteFile(uriFile, []byte(filepath.Join(dir, "partitions-v3")), 0o600))
+	require.Error(t, reloader.Reload(ctx))
+	require.Equal(t, total2, count())
+
+	require.NoError(t, os.WriteFile(uriFile, nil, 0o600))
+	require.ErrorIs(t, reloader.Reload(ctx), ErrEmptyURIFile)
+	require.Equal(t, total2, count())
+}
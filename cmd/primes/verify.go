@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+
+	"github.com/zalgonoise/tendigitprimes/config"
+	"github.com/zalgonoise/tendigitprimes/verify"
+)
+
+func ExecVerify(ctx context.Context, logger *slog.Logger, args []string) (int, error) {
+	c, err := config.NewVerify(args)
+	if err != nil {
+		return 1, err
+	}
+
+	logger.InfoContext(ctx, "verifying dataset",
+		slog.String("input", c.Input),
+		slog.Float64("sample_rate", float64(c.SampleRate)),
+		slog.Bool("sieve", !c.SkipSieve),
+		slog.Int("workers", c.Workers),
+		slog.Int64("max", c.Max),
+	)
+
+	report, err := verify.Dataset(ctx, c.Input, verify.Options{
+		SampleRate: float64(c.SampleRate),
+		Sieve:      !c.SkipSieve,
+		Workers:    c.Workers,
+		Max:        c.Max,
+	}, logger)
+	if err != nil {
+		return 1, err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if err = enc.Encode(report); err != nil {
+		return 1, err
+	}
+
+	if !report.OK {
+		logger.ErrorContext(ctx, "dataset verification failed", slog.String("input", c.Input))
+
+		return 1, nil
+	}
+
+	logger.InfoContext(ctx, "dataset verified",
+		slog.String("input", c.Input),
+		slog.Int64("num_primes", report.Rows),
+		slog.String("time_elapsed", report.Elapsed),
+	)
+
+	return 0, nil
+}
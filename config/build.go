@@ -4,21 +4,60 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"runtime"
 	"strconv"
 	"strings"
 
 	"github.com/kelseyhightower/envconfig"
 )
 
-const minBlockSize = 100_000_000
+const (
+	minBlockSize     = 1_000_000
+	defaultBlockSize = 100_000_000
+	// defaultBlockCount is the default number of primes in each partition with the count strategy, splitting the
+	// ten-digit primes in about as many partitions as the default block size does with the width strategy
+	defaultBlockCount = 5_000_000
+)
+
+const (
+	FormatSQLite = "sqlite"
+	FormatPacked = "packed"
+	FormatSieve  = "sieve"
+
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+
+	StrategyWidth = "width"
+	StrategyCount = "count"
+	StrategyFile  = "file"
 
-var ErrBlockSizeTooLow = errors.New("block size value is too low")
+	ValidationStrict  = "strict"
+	ValidationLenient = "lenient"
+)
+
+var (
+	ErrBlockSizeTooLow = errors.New("block size value is too low")
+	ErrInvalidFormat   = errors.New("invalid dataset format")
+	ErrInvalidDriver   = errors.New("invalid database driver")
+	ErrInvalidStrategy = errors.New("invalid partitioning strategy")
+	ErrNoBoundaries    = errors.New("no partition boundaries file")
+	ErrInvalidMode     = errors.New("invalid validation mode")
+)
 
 type Build struct {
 	Input       string    `envconfig:"PRIMES_BUILD_INPUT"`
 	Output      string    `envconfig:"PRIMES_BUILD_OUTPUT"`
 	Partitioned bool      `envconfig:"PRIMES_BUILD_IS_PARTITIONED"`
 	BlockSize   BlockSize `envconfig:"PRIMES_BUILD_BLOCK_SIZE" `
+	Format      Format    `envconfig:"PRIMES_BUILD_FORMAT"`
+	Driver      Driver    `envconfig:"PRIMES_BUILD_DRIVER"`
+	Workers     int       `envconfig:"PRIMES_BUILD_WORKERS"`
+	Strategy    Strategy  `envconfig:"PRIMES_BUILD_STRATEGY"`
+	Boundaries  string    `envconfig:"PRIMES_BUILD_BOUNDARIES"`
+	Validation  Mode      `envconfig:"PRIMES_BUILD_VALIDATION"`
+	Min         Bound     `envconfig:"PRIMES_BUILD_MIN"`
+	Max         Bound     `envconfig:"PRIMES_BUILD_MAX"`
+	Report      string    `envconfig:"PRIMES_BUILD_REPORT"`
 }
 
 type BlockSize int
@@ -38,6 +77,60 @@ func (b *BlockSize) Decode(value string) error {
 	return nil
 }
 
+type Format string
+
+func (f *Format) Decode(value string) error {
+	switch v := strings.ToLower(value); v {
+	case FormatSQLite, FormatPacked, FormatSieve:
+		*f = Format(v)
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidFormat, value)
+	}
+}
+
+type Driver string
+
+func (d *Driver) Decode(value string) error {
+	switch v := strings.ToLower(value); v {
+	case DriverSQLite, DriverPostgres:
+		*d = Driver(v)
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidDriver, value)
+	}
+}
+
+type Strategy string
+
+func (s *Strategy) Decode(value string) error {
+	switch v := strings.ToLower(value); v {
+	case StrategyWidth, StrategyCount, StrategyFile:
+		*s = Strategy(v)
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidStrategy, value)
+	}
+}
+
+// Mode sets how invalid lines in the build input are handled: a strict validation fails the build, while a lenient one
+// skips them.
+type Mode string
+
+func (m *Mode) Decode(value string) error {
+	switch v := strings.ToLower(value); v {
+	case ValidationStrict, ValidationLenient:
+		*m = Mode(v)
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidMode, value)
+	}
+}
+
 func NewBuild(args []string) (*Build, error) {
 	flagsConfig, err := flagsBuild(args)
 	if err != nil {
@@ -49,16 +142,73 @@ func NewBuild(args []string) (*Build, error) {
 		return nil, err
 	}
 
-	return applyBuildDefaults(mergeBuild(flagsConfig, envConfig)), nil
+	config := applyBuildDefaults(mergeBuild(flagsConfig, envConfig))
+
+	// the sieve format computes primes on demand, there is nothing to build
+	if config.Format == FormatSieve {
+		return nil, fmt.Errorf("%w: %q cannot be built", ErrInvalidFormat, config.Format)
+	}
+
+	if config.Driver == DriverPostgres && config.Format != FormatSQLite {
+		return nil, fmt.Errorf("%w: %q does not support the %q format", ErrInvalidDriver, config.Driver, config.Format)
+	}
+
+	// only partitioned SQLite builds can be split other than by equal value ranges
+	partitioned := config.Partitioned && config.Driver == DriverSQLite && config.Format == FormatSQLite
+
+	if err = validateStrategy(config.Strategy, config.Boundaries, partitioned); err != nil {
+		return nil, err
+	}
+
+	if config.Min > config.Max {
+		return nil, fmt.Errorf("%w: min %d is greater than max %d", ErrInvalidRange, config.Min, config.Max)
+	}
+
+	return config, nil
+}
+
+func validateStrategy(strategy Strategy, boundaries string, partitioned bool) error {
+	if strategy != StrategyWidth && !partitioned {
+		return fmt.Errorf("%w: %q only applies to partitioned sqlite datasets", ErrInvalidStrategy, strategy)
+	}
+
+	if strategy == StrategyFile && boundaries == "" {
+		return fmt.Errorf("%w: the %q strategy requires a boundaries file", ErrNoBoundaries, strategy)
+	}
+
+	return nil
+}
+
+// defaultBlockSizeFor returns the default block size for strategy.
+func defaultBlockSizeFor(strategy Strategy) BlockSize {
+	if strategy == StrategyCount {
+		return defaultBlockCount
+	}
+
+	return defaultBlockSize
 }
 
 func flagsBuild(args []string) (*Build, error) {
 	fs := flag.NewFlagSet("build", flag.ExitOnError)
 
-	input := fs.String("input", "", "path to the input data to consume. Default is './input'")
-	output := fs.String("output", "", "path to place the sqlite file in. Default is './sqlite/primes.db'")
+	input := fs.String("input", "", "path to the input data to consume: a directory of shards, a shard, a tar archive "+
+		"of shards, or '-' for stdin; optionally gzip or zstd compressed. Default is './raw'")
+	output := fs.String("output", "", "path to place the sqlite file in, or the postgres URI. Default is './sqlite/primes.db'")
 	partitioned := fs.Bool("partitioned", false, "partition database in multiple files")
-	blockSize := fs.Int("block-size", 0, "value range to set for each partition")
+	blockSize := fs.Int("block-size", 0, "value range to set for each partition with the 'width' strategy, or number "+
+		"of primes in each partition with the 'count' strategy. Default is 100_000_000 and 5_000_000 respectively")
+	format := fs.String("format", "", "output format for the dataset [one of: 'sqlite', 'packed']")
+	driver := fs.String("driver", "", "database driver to build the dataset with [one of: 'sqlite', 'postgres']")
+	workers := fs.Int("workers", 0, "number of partitions built concurrently. Default is the number of CPUs")
+	strategy := fs.String("strategy", "", "how values are split into partitions [one of: 'width', 'count', 'file']. "+
+		"Default is 'width'")
+	boundaries := fs.String("boundaries", "", "path to a JSON or CSV file with the first value of each partition "+
+		"but the first one, for the 'file' strategy")
+	strict := fs.Bool("strict", false, "fail the build on the first invalid line in the input. This is the default")
+	lenient := fs.Bool("lenient", false, "skip invalid lines in the input, and continue the build")
+	minimum := fs.String("min", "", "the minimum value accepted in the input. Default is '2'")
+	maximum := fs.String("max", "", "the maximum value accepted in the input. Default is '9999999999'")
+	report := fs.String("report", "", "path to write a JSON report of the input validation to")
 
 	if err := fs.Parse(args); err != nil {
 		return nil, err
@@ -78,8 +228,61 @@ func flagsBuild(args []string) (*Build, error) {
 		config.Partitioned = *partitioned
 	}
 
-	if *blockSize >= minBlockSize {
-		config.BlockSize = BlockSize(*blockSize)
+	if *blockSize != 0 {
+		if err := config.BlockSize.Decode(strconv.Itoa(*blockSize)); err != nil {
+			return nil, err
+		}
+	}
+
+	if *format != "" {
+		if err := config.Format.Decode(*format); err != nil {
+			return nil, err
+		}
+	}
+
+	if *driver != "" {
+		if err := config.Driver.Decode(*driver); err != nil {
+			return nil, err
+		}
+	}
+
+	if *workers > 0 {
+		config.Workers = *workers
+	}
+
+	if *strategy != "" {
+		if err := config.Strategy.Decode(*strategy); err != nil {
+			return nil, err
+		}
+	}
+
+	if *boundaries != "" {
+		config.Boundaries = *boundaries
+	}
+
+	switch {
+	case *strict && *lenient:
+		return nil, fmt.Errorf("%w: -strict and -lenient are mutually exclusive", ErrInvalidMode)
+	case *strict:
+		config.Validation = ValidationStrict
+	case *lenient:
+		config.Validation = ValidationLenient
+	}
+
+	if *minimum != "" {
+		if err := config.Min.Decode(*minimum); err != nil {
+			return nil, err
+		}
+	}
+
+	if *maximum != "" {
+		if err := config.Max.Decode(*maximum); err != nil {
+			return nil, err
+		}
+	}
+
+	if *report != "" {
+		config.Report = *report
 	}
 
 	return config, nil
@@ -109,6 +312,46 @@ func mergeBuild(base, next *Build) *Build {
 		base.Partitioned = true
 	}
 
+	if next.BlockSize > 0 {
+		base.BlockSize = next.BlockSize
+	}
+
+	if next.Format != "" {
+		base.Format = next.Format
+	}
+
+	if next.Driver != "" {
+		base.Driver = next.Driver
+	}
+
+	if next.Workers > 0 {
+		base.Workers = next.Workers
+	}
+
+	if next.Strategy != "" {
+		base.Strategy = next.Strategy
+	}
+
+	if next.Boundaries != "" {
+		base.Boundaries = next.Boundaries
+	}
+
+	if next.Validation != "" {
+		base.Validation = next.Validation
+	}
+
+	if next.Min > 0 {
+		base.Min = next.Min
+	}
+
+	if next.Max > 0 {
+		base.Max = next.Max
+	}
+
+	if next.Report != "" {
+		base.Report = next.Report
+	}
+
 	return base
 }
 
@@ -121,8 +364,36 @@ func applyBuildDefaults(config *Build) *Build {
 		config.Output = "./sqlite/primes.db"
 	}
 
-	if config.BlockSize < minBlockSize {
-		config.BlockSize = minBlockSize
+	if config.Strategy == "" {
+		config.Strategy = StrategyWidth
+	}
+
+	if config.BlockSize == 0 {
+		config.BlockSize = defaultBlockSizeFor(config.Strategy)
+	}
+
+	if config.Format == "" {
+		config.Format = FormatSQLite
+	}
+
+	if config.Driver == "" {
+		config.Driver = DriverSQLite
+	}
+
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+
+	if config.Validation == "" {
+		config.Validation = ValidationStrict
+	}
+
+	if config.Min == 0 {
+		config.Min = 2
+	}
+
+	if config.Max == 0 {
+		config.Max = maxLimit
 	}
 
 	return config
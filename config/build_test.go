@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockSize(t *testing.T) {
+	for _, testcase := range []struct {
+		name string
+		new  func(args []string) error
+	}{
+		{
+			name: "Build",
+			new: func(args []string) error {
+				_, err := NewBuild(append(args, "-partitioned", "-strategy", "count"))
+
+				return err
+			},
+		},
+		{
+			name: "Generate",
+			new: func(args []string) error {
+				_, err := NewGenerate(args)
+
+				return err
+			},
+		},
+		{
+			name: "Repartition",
+			new: func(args []string) error {
+				_, err := NewRepartition(append(args, "-input", "primes.db", "-output", "parts"))
+
+				return err
+			},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			require.NoError(t, testcase.new([]string{"-block-size", "1000000"}))
+			require.ErrorIs(t, testcase.new([]string{"-block-size", "500000"}), ErrBlockSizeTooLow)
+			require.ErrorIs(t, testcase.new([]string{"-block-size", "-1"}), ErrBlockSizeTooLow)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const (
+	ExportRaw    = "raw"
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+	ExportBinary = "binary"
+
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+var (
+	ErrInvalidExportFormat = errors.New("invalid export format")
+	ErrInvalidCompression  = errors.New("invalid compression")
+	ErrInvalidRange        = errors.New("invalid export range")
+)
+
+type Export struct {
+	Input       string       `envconfig:"PRIMES_EXPORT_INPUT"`
+	Output      string       `envconfig:"PRIMES_EXPORT_OUTPUT"`
+	Format      ExportFormat `envconfig:"PRIMES_EXPORT_FORMAT"`
+	Compression Compression  `envconfig:"PRIMES_EXPORT_COMPRESSION"`
+	Min         Bound        `envconfig:"PRIMES_EXPORT_MIN"`
+	Max         Bound        `envconfig:"PRIMES_EXPORT_MAX"`
+}
+
+type ExportFormat string
+
+func (f *ExportFormat) Decode(value string) error {
+	switch v := strings.ToLower(value); v {
+	case ExportRaw, ExportCSV, ExportNDJSON, ExportBinary:
+		*f = ExportFormat(v)
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidExportFormat, value)
+	}
+}
+
+type Compression string
+
+func (c *Compression) Decode(value string) error {
+	switch v := strings.ToLower(value); v {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		*c = Compression(v)
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidCompression, value)
+	}
+}
+
+// Bound is an edge of a range of primes, to export or to accept in a build input.
+type Bound int64
+
+func (b *Bound) Decode(value string) error {
+	n, err := strconv.ParseInt(strings.ReplaceAll(value, "_", ""), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	if n < 0 || n > maxLimit {
+		return fmt.Errorf("%w: %d", ErrInvalidRange, n)
+	}
+
+	*b = Bound(n)
+
+	return nil
+}
+
+func NewExport(args []string) (*Export, error) {
+	flagsConfig, err := flagsExport(args)
+	if err != nil {
+		return nil, err
+	}
+
+	envConfig, err := envExport()
+	if err != nil {
+		return nil, err
+	}
+
+	config := applyExportDefaults(mergeExport(flagsConfig, envConfig))
+
+	if config.Output == "" {
+		return nil, ErrNoOutput
+	}
+
+	if config.Min > config.Max {
+		return nil, fmt.Errorf("%w: min %d is greater than max %d", ErrInvalidRange, config.Min, config.Max)
+	}
+
+	return config, nil
+}
+
+func flagsExport(args []string) (*Export, error) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+
+	input := fs.String("input", "", "path to the sqlite dataset to export: a single database file, or a partitioned "+
+		"dataset's directory. Default is './sqlite/primes.db'")
+	output := fs.String("output", "", "path to write the export to: a directory for the 'raw' format, or a file "+
+		"otherwise, or '-' for stdout")
+	format := fs.String("format", "", "output format [one of: 'raw', 'csv', 'ndjson', 'binary']. Default is 'raw'")
+	compression := fs.String("compression", "", "output compression [one of: 'none', 'gzip', 'zstd']. "+
+		"Default is 'none'")
+	minimum := fs.String("min", "", "the minimum value to export. Default is '0'")
+	maximum := fs.String("max", "", "the maximum value to export. Default is '9999999999'")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config := &Export{}
+
+	if *input != "" {
+		config.Input = *input
+	}
+
+	if *output != "" {
+		config.Output = *output
+	}
+
+	if *format != "" {
+		if err := config.Format.Decode(*format); err != nil {
+			return nil, err
+		}
+	}
+
+	if *compression != "" {
+		if err := config.Compression.Decode(*compression); err != nil {
+			return nil, err
+		}
+	}
+
+	if *minimum != "" {
+		if err := config.Min.Decode(*minimum); err != nil {
+			return nil, err
+		}
+	}
+
+	if *maximum != "" {
+		if err := config.Max.Decode(*maximum); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+func envExport() (*Export, error) {
+	config := &Export{}
+
+	err := envconfig.Process("", config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func mergeExport(base, next *Export) *Export {
+	if next.Input != "" {
+		base.Input = next.Input
+	}
+
+	if next.Output != "" {
+		base.Output = next.Output
+	}
+
+	if next.Format != "" {
+		base.Format = next.Format
+	}
+
+	if next.Compression != "" {
+		base.Compression = next.Compression
+	}
+
+	if next.Min > 0 {
+		base.Min = next.Min
+	}
+
+	if next.Max > 0 {
+		base.Max = next.Max
+	}
+
+	return base
+}
+
+func applyExportDefaults(config *Export) *Export {
+	if config.Input == "" {
+		config.Input = "./sqlite/primes.db"
+	}
+
+	if config.Format == "" {
+		config.Format = ExportRaw
+	}
+
+	if config.Compression == "" {
+		config.Compression = CompressionNone
+	}
+
+	if config.Max == 0 {
+		config.Max = maxLimit
+	}
+
+	return config
+}
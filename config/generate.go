@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const maxLimit = 9_999_999_999
+
+var ErrLimitOutOfBounds = errors.New("limit value is out of bounds")
+
+type Generate struct {
+	Limit       Limit     `envconfig:"PRIMES_GENERATE_LIMIT"`
+	Output      string    `envconfig:"PRIMES_GENERATE_OUTPUT"`
+	Workers     int       `envconfig:"PRIMES_GENERATE_WORKERS"`
+	Partitioned bool      `envconfig:"PRIMES_GENERATE_IS_PARTITIONED"`
+	BlockSize   BlockSize `envconfig:"PRIMES_GENERATE_BLOCK_SIZE"`
+}
+
+type Limit int64
+
+func (l *Limit) Decode(value string) error {
+	n, err := strconv.ParseInt(strings.ReplaceAll(value, "_", ""), 10, 64)
+	if err != nil {
+		return err
+	}
+
+	if n < 2 || n > maxLimit {
+		return fmt.Errorf("%w: %d", ErrLimitOutOfBounds, n)
+	}
+
+	*l = Limit(n)
+
+	return nil
+}
+
+func NewGenerate(args []string) (*Generate, error) {
+	flagsConfig, err := flagsGenerate(args)
+	if err != nil {
+		return nil, err
+	}
+
+	envConfig, err := envGenerate()
+	if err != nil {
+		return nil, err
+	}
+
+	return applyGenerateDefaults(mergeGenerate(flagsConfig, envConfig)), nil
+}
+
+func flagsGenerate(args []string) (*Generate, error) {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+
+	limit := fs.String("limit", "", "the maximum value to sieve primes up to. Default is '9999999999'")
+	output := fs.String("output", "", "path to place the raw text files, or the partitions in. Default is './raw'")
+	workers := fs.Int("workers", 0, "number of concurrent sieve workers. Default is the number of CPUs")
+	partitioned := fs.Bool("partitioned", false, "write partitioned SQLite databases instead of raw text files")
+	blockSize := fs.Int("block-size", 0, "value range to set for each partition")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config := &Generate{}
+
+	if *limit != "" {
+		if err := config.Limit.Decode(*limit); err != nil {
+			return nil, err
+		}
+	}
+
+	if *output != "" {
+		config.Output = *output
+	}
+
+	if *workers > 0 {
+		config.Workers = *workers
+	}
+
+	if *partitioned {
+		config.Partitioned = *partitioned
+	}
+
+	if *blockSize != 0 {
+		if err := config.BlockSize.Decode(strconv.Itoa(*blockSize)); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+func envGenerate() (*Generate, error) {
+	config := &Generate{}
+
+	err := envconfig.Process("", config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func mergeGenerate(base, next *Generate) *Generate {
+	if next.Limit > 0 {
+		base.Limit = next.Limit
+	}
+
+	if next.Output != "" {
+		base.Output = next.Output
+	}
+
+	if next.Workers > 0 {
+		base.Workers = next.Workers
+	}
+
+	if next.Partitioned {
+		base.Partitioned = true
+	}
+
+	if next.BlockSize > 0 {
+		base.BlockSize = next.BlockSize
+	}
+
+	return base
+}
+
+func applyGenerateDefaults(config *Generate) *Generate {
+	if config.Limit == 0 {
+		config.Limit = maxLimit
+	}
+
+	if config.Output == "" {
+		config.Output = "./raw"
+	}
+
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+
+	if config.BlockSize == 0 {
+		config.BlockSize = defaultBlockSize
+	}
+
+	return config
+}
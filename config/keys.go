@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+const (
+	KeysCreate = "create"
+	KeysList   = "list"
+	KeysRevoke = "revoke"
+)
+
+var (
+	ErrInvalidKeysAction = errors.New("invalid keys action")
+	ErrNoKeyName         = errors.New("no API key name")
+	ErrNoKeyID           = errors.New("no API key ID")
+)
+
+type Keys struct {
+	DB     string `envconfig:"PRIMES_AUTH_KEYS"`
+	Action string `ignored:"true"`
+	Name   string `ignored:"true"`
+	ID     string `ignored:"true"`
+}
+
+// NewKeys parses the configuration for the keys command, from its action, as the first argument, and the flags
+// following it.
+func NewKeys(args []string) (*Keys, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: one of %q, %q or %q is required", ErrInvalidKeysAction, KeysCreate, KeysList,
+			KeysRevoke)
+	}
+
+	flagsConfig, err := flagsKeys(args[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	envConfig, err := envKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	config := applyKeysDefaults(mergeKeys(flagsConfig, envConfig))
+	config.Action = args[0]
+
+	switch {
+	case config.Action == KeysCreate && config.Name == "":
+		return nil, ErrNoKeyName
+	case config.Action == KeysRevoke && config.ID == "":
+		return nil, ErrNoKeyID
+	case config.Action != KeysCreate && config.Action != KeysList && config.Action != KeysRevoke:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidKeysAction, config.Action)
+	}
+
+	return config, nil
+}
+
+func flagsKeys(args []string) (*Keys, error) {
+	fs := flag.NewFlagSet("keys", flag.ExitOnError)
+
+	db := fs.String("db", "", "path to the API keys database. Default is './keys.db'")
+	name := fs.String("name", "", "the name of the API key to create, to identify it in logs and metrics")
+	id := fs.String("id", "", "the ID of the API key to revoke")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	return &Keys{DB: *db, Name: *name, ID: *id}, nil
+}
+
+func envKeys() (*Keys, error) {
+	config := &Keys{}
+
+	err := envconfig.Process("", config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func mergeKeys(base, next *Keys) *Keys {
+	if next.DB != "" {
+		base.DB = next.DB
+	}
+
+	return base
+}
+
+func applyKeysDefaults(config *Keys) *Keys {
+	if config.DB == "" {
+		config.DB = "./keys.db"
+	}
+
+	return config
+}
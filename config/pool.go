@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPoolRange = errors.New("invalid pool range")
+
+type Pool struct {
+	Ranges PoolRanges `envconfig:"PRIMES_POOL_RANGES"`
+}
+
+// PoolRange sets the bounds and the buffer size of a range of pre-sampled primes.
+type PoolRange struct {
+	Min  int64
+	Max  int64
+	Size int
+}
+
+// PoolRanges is a comma-separated list of ranges in a `min:max[:size]` format, e.g.
+// `1000000000:5000000000:2048,2:9999999999`.
+type PoolRanges []PoolRange
+
+func (p *PoolRanges) Decode(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	items := strings.Split(value, ",")
+	ranges := make([]PoolRange, 0, len(items))
+
+	for i := range items {
+		fields := strings.Split(strings.TrimSpace(items[i]), ":")
+		if len(fields) < 2 || len(fields) > 3 {
+			return fmt.Errorf("%w: %q", ErrInvalidPoolRange, items[i])
+		}
+
+		values := make([]int64, len(fields))
+
+		for idx := range fields {
+			n, err := strconv.ParseInt(strings.ReplaceAll(fields[idx], "_", ""), 10, 64)
+			if err != nil {
+				return fmt.Errorf("%w: %q: %w", ErrInvalidPoolRange, items[i], err)
+			}
+
+			values[idx] = n
+		}
+
+		r := PoolRange{Min: values[0], Max: values[1]}
+
+		if len(values) == 3 {
+			r.Size = int(values[2])
+		}
+
+		if r.Min > r.Max || r.Size < 0 {
+			return fmt.Errorf("%w: %q", ErrInvalidPoolRange, items[i])
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	*p = ranges
+
+	return nil
+}
@@ -1,27 +1,79 @@
 package config
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 )
 
+const (
+	VerifySampled = "sampled"
+	VerifyFull    = "full"
+)
+
+var (
+	ErrInvalidVerification = errors.New("invalid manifest verification mode")
+	ErrConflictingURI      = errors.New("the database URI and URI file cannot both be set")
+)
+
 type Primes struct {
 	LogLevel string `envconfig:"PRIMES_LOG_LEVEL"`
 
-	Database Database
-	Server   Server
+	Database  Database
+	Server    Server
+	Pool      Pool
+	Cache     Cache
+	Auth      Auth
+	RateLimit RateLimit
+}
+
+// Cache configures the cache for lookups, such as IsPrime or Nth.
+type Cache struct {
+	MaxEntries int `envconfig:"PRIMES_CACHE_MAX_ENTRIES"`
+}
+
+// Auth configures API key authentication, which is disabled if no keys database is set.
+type Auth struct {
+	Keys string `envconfig:"PRIMES_AUTH_KEYS"`
 }
 
 type Database struct {
 	URI         string `envconfig:"PRIMES_DB_URI"`
+	URIFile     string `envconfig:"PRIMES_DB_URI_FILE"`
 	Partitioned bool   `envconfig:"PRIMES_DB_IS_PARTITIONED"`
+	Detached    bool   `envconfig:"PRIMES_DB_IS_DETACHED"`
+	MaxOpen     int    `envconfig:"PRIMES_DB_MAX_OPEN_PARTITIONS"`
+	Format      Format `envconfig:"PRIMES_DB_FORMAT"`
+	Driver      Driver `envconfig:"PRIMES_DB_DRIVER"`
+
+	Fallback   Format        `envconfig:"PRIMES_DB_FALLBACK"`
+	HedgeDelay time.Duration `envconfig:"PRIMES_DB_HEDGE_DELAY"`
+
+	Verify Verification `envconfig:"PRIMES_DB_VERIFY"`
+}
+
+type Verification string
+
+func (v *Verification) Decode(value string) error {
+	switch s := strings.ToLower(value); s {
+	case VerifySampled, VerifyFull:
+		*v = Verification(s)
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidVerification, value)
+	}
 }
 
 type Server struct {
 	HTTPPort int `envconfig:"PRIMES_HTTP_PORT"`
 	GRPCPort int `envconfig:"PRIMES_GRPC_PORT"`
+
+	TLS TLS
 }
 
 func NewPrimes(args []string) (*Primes, error) {
@@ -35,7 +87,23 @@ func NewPrimes(args []string) (*Primes, error) {
 		return nil, err
 	}
 
-	return applyPrimesDefaults(mergePrimes(flagsConfig, envConfig)), nil
+	config := applyPrimesDefaults(mergePrimes(flagsConfig, envConfig))
+
+	// only the sieve format can serve as a fallback, as it does not require a dataset of its own
+	if config.Database.Fallback != "" &&
+		(config.Database.Fallback != FormatSieve || config.Database.Format == FormatSieve) {
+		return nil, fmt.Errorf("%w: %q cannot be used as a fallback", ErrInvalidFormat, config.Database.Fallback)
+	}
+
+	if config.Database.URI != "" && config.Database.URIFile != "" {
+		return nil, ErrConflictingURI
+	}
+
+	if err = validateTLS(config.Server.TLS); err != nil {
+		return nil, err
+	}
+
+	return config, nil
 }
 
 func flagsPrimes(args []string) (*Primes, error) {
@@ -43,11 +111,36 @@ func flagsPrimes(args []string) (*Primes, error) {
 
 	logLevel := fs.String("log-level", "", "defines the minimum log level when registering events [one of: 'debug', 'info', 'warn', 'error']")
 
-	dbURI := fs.String("db.uri", "", "the URI for the database file or partitions directory")
+	dbURI := fs.String("db.uri", "", "the URI for the database file, partitions directory or postgres instance")
+	dbURIFile := fs.String("db.uri-file", "", "path to a file holding the database URI, which is read again on each reload")
 	dbIsPartitioned := fs.Bool("db.partitioned", false, "setup SQLite with partitioned database files")
+	dbIsDetached := fs.Bool("db.detached", false, "open each SQLite partition as a separate database, instead of attaching them")
+	dbMaxOpen := fs.Int("db.max-open-partitions", 0, "open SQLite partitions on demand, keeping at most this many open")
+	dbFormat := fs.String("db.format", "", "the format of the dataset [one of: 'sqlite', 'packed', 'sieve']")
+	dbDriver := fs.String("db.driver", "", "the database driver to serve from [one of: 'sqlite', 'postgres']")
+	dbFallback := fs.String("db.fallback", "", "the format to fall back to when the database fails [one of: 'sieve']")
+	dbVerify := fs.String("db.verify", "", "how to verify partitions against the dataset manifest [one of: 'sampled', 'full']")
+	dbHedgeDelay := fs.Duration("db.hedge-delay", 0, "also fire requests on the fallback when the database takes longer than this")
 
 	serverHTTPPort := fs.Int("server.http-port", 0, "web server's HTTP port")
 	serverGRPCPort := fs.Int("server.grpc-port", 0, "web server's gRPC port")
+	tlsCert := fs.String("tls.cert", "", "path to the PEM certificate chain to serve TLS with, on both ports")
+	tlsKey := fs.String("tls.key", "", "path to the PEM private key for the TLS certificate")
+	tlsClientCA := fs.String("tls.client-ca", "", "path to a PEM CA bundle to require and verify client certificates with")
+	tlsMinVersion := fs.String("tls.min-version", "", "the minimum TLS version to accept [one of: '1.2', '1.3']")
+	tlsReloadInterval := fs.Duration("tls.reload-interval", 0, "how often to check the TLS files for changes. Default is 30s")
+	tlsGatewayCert := fs.String("tls.gateway-cert", "", "path to the PEM certificate the gateway presents to gRPC. Default is the server's")
+	tlsGatewayKey := fs.String("tls.gateway-key", "", "path to the PEM private key for the gateway's certificate")
+	tlsGatewayCA := fs.String("tls.gateway-ca", "", "path to a PEM CA bundle for the gateway to verify gRPC with. Default is the server's certificate")
+	tlsGatewayServerName := fs.String("tls.gateway-server-name", "", "the name the gateway verifies the gRPC certificate for. Default is 'localhost'")
+
+	authKeys := fs.String("auth.keys", "", "path to the API keys database, as managed with 'primes keys', to require API keys with")
+
+	rateLimits := fs.String("ratelimit.limits", "", "per-client rate limits, as a comma-separated list of 'method=rate[:burst]' values, with '*' as the default for other methods")
+
+	cacheMaxEntries := fs.Int("cache.max-entries", 0, "how many lookup results to cache until the dataset is reloaded. Default is 65536")
+
+	poolRanges := fs.String("pool.ranges", "", "ranges to keep pre-sampled primes for, as a comma-separated list of 'min:max[:size]' values")
 
 	if err := fs.Parse(args); err != nil {
 		return nil, err
@@ -66,18 +159,91 @@ func flagsPrimes(args []string) (*Primes, error) {
 		config.Database.URI = *dbURI
 	}
 
+	if *dbURIFile != "" {
+		config.Database.URIFile = *dbURIFile
+	}
+
 	if *dbIsPartitioned {
 		config.Database.Partitioned = true
 	}
 
+	if *dbIsDetached {
+		config.Database.Detached = true
+	}
+
+	if *dbMaxOpen > 0 {
+		config.Database.MaxOpen = *dbMaxOpen
+	}
+
+	if *dbFormat != "" {
+		if err := config.Database.Format.Decode(*dbFormat); err != nil {
+			return nil, err
+		}
+	}
+
+	if *dbDriver != "" {
+		if err := config.Database.Driver.Decode(*dbDriver); err != nil {
+			return nil, err
+		}
+	}
+
+	if *dbFallback != "" {
+		if err := config.Database.Fallback.Decode(*dbFallback); err != nil {
+			return nil, err
+		}
+	}
+
+	if *dbVerify != "" {
+		if err := config.Database.Verify.Decode(*dbVerify); err != nil {
+			return nil, err
+		}
+	}
+
+	if *dbHedgeDelay > 0 {
+		config.Database.HedgeDelay = *dbHedgeDelay
+	}
+
 	if *serverHTTPPort > 0 {
 		config.Server.HTTPPort = *serverHTTPPort
 	}
 
+	if err := config.Pool.Ranges.Decode(*poolRanges); err != nil {
+		return nil, err
+	}
+
+	if *cacheMaxEntries > 0 {
+		config.Cache.MaxEntries = *cacheMaxEntries
+	}
+
+	if *authKeys != "" {
+		config.Auth.Keys = *authKeys
+	}
+
+	if err := config.RateLimit.Limits.Decode(*rateLimits); err != nil {
+		return nil, err
+	}
+
 	if *serverGRPCPort > 0 {
 		config.Server.GRPCPort = *serverGRPCPort
 	}
 
+	config.Server.TLS = TLS{
+		Cert:              *tlsCert,
+		Key:               *tlsKey,
+		ClientCA:          *tlsClientCA,
+		ReloadInterval:    *tlsReloadInterval,
+		GatewayCert:       *tlsGatewayCert,
+		GatewayKey:        *tlsGatewayKey,
+		GatewayCA:         *tlsGatewayCA,
+		GatewayServerName: *tlsGatewayServerName,
+	}
+
+	if *tlsMinVersion != "" {
+		if err := config.Server.TLS.MinVersion.Decode(*tlsMinVersion); err != nil {
+			return nil, err
+		}
+	}
+
 	return config, nil
 }
 
@@ -97,10 +263,7 @@ func mergePrimes(base, next *Primes) *Primes {
 		base.LogLevel = next.LogLevel
 	}
 
-	// Database configuration (path + is_partitioned) is coupled tightly
-	if next.Database.URI != "" {
-		base.Database = next.Database
-	}
+	base.Database = mergeDatabase(base.Database, next.Database)
 
 	if next.Server.HTTPPort > 0 {
 		base.Server.HTTPPort = next.Server.HTTPPort
@@ -110,6 +273,68 @@ func mergePrimes(base, next *Primes) *Primes {
 		base.Server.GRPCPort = next.Server.GRPCPort
 	}
 
+	base.Server.TLS = mergeTLS(base.Server.TLS, next.Server.TLS)
+
+	if len(next.Pool.Ranges) > 0 {
+		base.Pool.Ranges = next.Pool.Ranges
+	}
+
+	if next.Cache.MaxEntries > 0 {
+		base.Cache.MaxEntries = next.Cache.MaxEntries
+	}
+
+	if next.Auth.Keys != "" {
+		base.Auth.Keys = next.Auth.Keys
+	}
+
+	if len(next.RateLimit.Limits) > 0 {
+		base.RateLimit.Limits = next.RateLimit.Limits
+	}
+
+	return base
+}
+
+func mergeDatabase(base, next Database) Database {
+	if next.URI != "" {
+		base.URI = next.URI
+	}
+
+	if next.URIFile != "" {
+		base.URIFile = next.URIFile
+	}
+
+	if next.Partitioned {
+		base.Partitioned = true
+	}
+
+	if next.Detached {
+		base.Detached = true
+	}
+
+	if next.MaxOpen > 0 {
+		base.MaxOpen = next.MaxOpen
+	}
+
+	if next.Format != "" {
+		base.Format = next.Format
+	}
+
+	if next.Driver != "" {
+		base.Driver = next.Driver
+	}
+
+	if next.Fallback != "" {
+		base.Fallback = next.Fallback
+	}
+
+	if next.HedgeDelay > 0 {
+		base.HedgeDelay = next.HedgeDelay
+	}
+
+	if next.Verify != "" {
+		base.Verify = next.Verify
+	}
+
 	return base
 }
 
@@ -118,6 +343,18 @@ func applyPrimesDefaults(config *Primes) *Primes {
 		config.LogLevel = "info"
 	}
 
+	if config.Database.Format == "" {
+		config.Database.Format = FormatSQLite
+	}
+
+	if config.Database.Driver == "" {
+		config.Database.Driver = DriverSQLite
+	}
+
+	if config.Database.Verify == "" {
+		config.Database.Verify = VerifySampled
+	}
+
 	if config.Server.HTTPPort == 0 {
 		config.Server.HTTPPort = 8080
 	}
@@ -126,5 +363,7 @@ func applyPrimesDefaults(config *Primes) *Primes {
 		config.Server.GRPCPort = 8081
 	}
 
+	config.Server.TLS = applyTLSDefaults(config.Server.TLS)
+
 	return config
 }
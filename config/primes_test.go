@@ -0,0 +1,55 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPrimes(t *testing.T) {
+	t.Run("DatabaseFromEnv", func(t *testing.T) {
+		// the sieve format needs no URI
+		t.Setenv("PRIMES_DB_FORMAT", FormatSieve)
+		t.Setenv("PRIMES_DB_MAX_OPEN_PARTITIONS", "4")
+		t.Setenv("PRIMES_DB_VERIFY", VerifyFull)
+
+		c, err := NewPrimes(nil)
+		require.NoError(t, err)
+		require.Equal(t, Database{
+			Format:  FormatSieve,
+			Driver:  DriverSQLite,
+			MaxOpen: 4,
+			Verify:  VerifyFull,
+		}, c.Database)
+	})
+
+	t.Run("DatabaseFromFlagsAndEnv", func(t *testing.T) {
+		t.Setenv("PRIMES_DB_FALLBACK", FormatSieve)
+		t.Setenv("PRIMES_DB_HEDGE_DELAY", "50ms")
+
+		c, err := NewPrimes([]string{"-db.uri", "./parts", "-db.partitioned", "-db.format", FormatSQLite})
+		require.NoError(t, err)
+		require.Equal(t, Database{
+			URI:         "./parts",
+			Partitioned: true,
+			Format:      FormatSQLite,
+			Driver:      DriverSQLite,
+			Fallback:    FormatSieve,
+			HedgeDelay:  50 * time.Millisecond,
+			Verify:      VerifySampled,
+		}, c.Database)
+	})
+
+	t.Run("URIFile", func(t *testing.T) {
+		t.Setenv("PRIMES_DB_URI_FILE", "./current")
+
+		c, err := NewPrimes([]string{"-db.partitioned"})
+		require.NoError(t, err)
+		require.Equal(t, "./current", c.Database.URIFile)
+		require.Empty(t, c.Database.URI)
+
+		_, err = NewPrimes([]string{"-db.uri", "./parts"})
+		require.ErrorIs(t, err, ErrConflictingURI)
+	})
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidRateLimit = errors.New("invalid rate limit")
+
+// RateLimit configures per-client rate limiting, which is disabled if no limits are set.
+type RateLimit struct {
+	Limits RateLimits `envconfig:"PRIMES_RATE_LIMITS"`
+}
+
+// RateLimitEntry sets the rate, in calls per second, and the burst of calls allowed for a method.
+type RateLimitEntry struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimits is a comma-separated list of limits per gRPC method name in a `method=rate[:burst]` format, where `*`
+// sets the default for the other methods, e.g. `Random=50:100,List=0.5:2,*=10`. The burst defaults to the rate,
+// rounded up.
+type RateLimits map[string]RateLimitEntry
+
+func (r *RateLimits) Decode(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	items := strings.Split(value, ",")
+	limits := make(map[string]RateLimitEntry, len(items))
+
+	for i := range items {
+		method, limit, ok := strings.Cut(strings.TrimSpace(items[i]), "=")
+		if !ok || method == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidRateLimit, items[i])
+		}
+
+		rate, burst, hasBurst := strings.Cut(limit, ":")
+
+		entry := RateLimitEntry{}
+
+		var err error
+
+		if entry.Rate, err = strconv.ParseFloat(rate, 64); err != nil {
+			return fmt.Errorf("%w: %q: %w", ErrInvalidRateLimit, items[i], err)
+		}
+
+		entry.Burst = max(1, int(math.Ceil(entry.Rate)))
+
+		if hasBurst {
+			if entry.Burst, err = strconv.Atoi(burst); err != nil {
+				return fmt.Errorf("%w: %q: %w", ErrInvalidRateLimit, items[i], err)
+			}
+		}
+
+		// NaN rates fail all comparisons, so they are rejected by requiring a positive rate instead
+		if !(entry.Rate > 0) || math.IsInf(entry.Rate, 0) || entry.Burst < 1 {
+			return fmt.Errorf("%w: %q", ErrInvalidRateLimit, items[i])
+		}
+
+		limits[method] = entry
+	}
+
+	*r = limits
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"runtime"
+	"strconv"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+var ErrNoOutput = errors.New("no output path")
+
+type Repartition struct {
+	Input       string    `envconfig:"PRIMES_REPARTITION_INPUT"`
+	Output      string    `envconfig:"PRIMES_REPARTITION_OUTPUT"`
+	Partitioned bool      `envconfig:"PRIMES_REPARTITION_IS_PARTITIONED"`
+	BlockSize   BlockSize `envconfig:"PRIMES_REPARTITION_BLOCK_SIZE"`
+	Strategy    Strategy  `envconfig:"PRIMES_REPARTITION_STRATEGY"`
+	Boundaries  string    `envconfig:"PRIMES_REPARTITION_BOUNDARIES"`
+	Workers     int       `envconfig:"PRIMES_REPARTITION_WORKERS"`
+}
+
+func NewRepartition(args []string) (*Repartition, error) {
+	flagsConfig, err := flagsRepartition(args)
+	if err != nil {
+		return nil, err
+	}
+
+	envConfig, err := envRepartition()
+	if err != nil {
+		return nil, err
+	}
+
+	config := applyRepartitionDefaults(mergeRepartition(flagsConfig, envConfig))
+
+	if config.Output == "" {
+		return nil, ErrNoOutput
+	}
+
+	if err = validateStrategy(config.Strategy, config.Boundaries, config.Partitioned); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func flagsRepartition(args []string) (*Repartition, error) {
+	fs := flag.NewFlagSet("repartition", flag.ExitOnError)
+
+	input := fs.String("input", "", "path to the sqlite dataset to read: a single database file, or a partitioned "+
+		"dataset's directory. Default is './sqlite/primes.db'")
+	output := fs.String("output", "", "path to write the dataset to: a directory with -partitioned, or a new "+
+		"database file otherwise")
+	partitioned := fs.Bool("partitioned", false, "write a partitioned dataset, instead of a single database file")
+	blockSize := fs.Int("block-size", 0, "value range to set for each partition with the 'width' strategy, or number "+
+		"of primes in each partition with the 'count' strategy. Default is 100_000_000 and 5_000_000 respectively")
+	strategy := fs.String("strategy", "", "how values are split into partitions [one of: 'width', 'count', 'file']. "+
+		"Default is 'width'")
+	boundaries := fs.String("boundaries", "", "path to a JSON or CSV file with the first value of each partition "+
+		"but the first one, for the 'file' strategy")
+	workers := fs.Int("workers", 0, "number of partitions built concurrently. Default is the number of CPUs")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config := &Repartition{}
+
+	if *input != "" {
+		config.Input = *input
+	}
+
+	if *output != "" {
+		config.Output = *output
+	}
+
+	if *partitioned {
+		config.Partitioned = *partitioned
+	}
+
+	if *blockSize != 0 {
+		if err := config.BlockSize.Decode(strconv.Itoa(*blockSize)); err != nil {
+			return nil, err
+		}
+	}
+
+	if *strategy != "" {
+		if err := config.Strategy.Decode(*strategy); err != nil {
+			return nil, err
+		}
+	}
+
+	if *boundaries != "" {
+		config.Boundaries = *boundaries
+	}
+
+	if *workers > 0 {
+		config.Workers = *workers
+	}
+
+	return config, nil
+}
+
+func envRepartition() (*Repartition, error) {
+	config := &Repartition{}
+
+	err := envconfig.Process("", config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func mergeRepartition(base, next *Repartition) *Repartition {
+	if next.Input != "" {
+		base.Input = next.Input
+	}
+
+	if next.Output != "" {
+		base.Output = next.Output
+	}
+
+	if next.Partitioned {
+		base.Partitioned = true
+	}
+
+	if next.BlockSize > 0 {
+		base.BlockSize = next.BlockSize
+	}
+
+	if next.Strategy != "" {
+		base.Strategy = next.Strategy
+	}
+
+	if next.Boundaries != "" {
+		base.Boundaries = next.Boundaries
+	}
+
+	if next.Workers > 0 {
+		base.Workers = next.Workers
+	}
+
+	return base
+}
+
+func applyRepartitionDefaults(config *Repartition) *Repartition {
+	if config.Input == "" {
+		config.Input = "./sqlite/primes.db"
+	}
+
+	if config.Strategy == "" {
+		config.Strategy = StrategyWidth
+	}
+
+	if config.BlockSize == 0 {
+		config.BlockSize = defaultBlockSizeFor(config.Strategy)
+	}
+
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+
+	return config
+}
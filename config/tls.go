@@ -0,0 +1,135 @@
+package config
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const defaultTLSReloadInterval = 30 * time.Second
+
+var (
+	ErrInvalidTLSVersion = errors.New("invalid minimum TLS version")
+	ErrIncompleteTLS     = errors.New("incomplete TLS configuration")
+)
+
+// TLS configures TLS on both the HTTP and gRPC ports, which serve in plaintext when no certificate is set.
+type TLS struct {
+	Cert       string     `envconfig:"PRIMES_TLS_CERT"`
+	Key        string     `envconfig:"PRIMES_TLS_KEY"`
+	ClientCA   string     `envconfig:"PRIMES_TLS_CLIENT_CA"`
+	MinVersion TLSVersion `envconfig:"PRIMES_TLS_MIN_VERSION"`
+
+	// ReloadInterval is how often the certificate files are checked for changes.
+	ReloadInterval time.Duration `envconfig:"PRIMES_TLS_RELOAD_INTERVAL"`
+
+	// GatewayCert and GatewayKey are presented by the HTTP gateway to the gRPC server, when it requires client
+	// certificates. They default to the server's certificate and key, which must then be valid for client
+	// authentication, and issued by the client CA.
+	GatewayCert string `envconfig:"PRIMES_TLS_GATEWAY_CERT"`
+	GatewayKey  string `envconfig:"PRIMES_TLS_GATEWAY_KEY"`
+	// GatewayCA is the CA bundle the HTTP gateway verifies the gRPC server with. Default is the server's certificate,
+	// trusted as is, as both servers present the same one.
+	GatewayCA string `envconfig:"PRIMES_TLS_GATEWAY_CA"`
+	// GatewayServerName is the name the HTTP gateway verifies the gRPC server's certificate for.
+	GatewayServerName string `envconfig:"PRIMES_TLS_GATEWAY_SERVER_NAME"`
+}
+
+// Enabled returns true if a server certificate is set.
+func (t TLS) Enabled() bool {
+	return t.Cert != ""
+}
+
+// TLSVersion is a minimum TLS version, as `1.2` or `1.3`.
+type TLSVersion uint16
+
+func (v *TLSVersion) Decode(value string) error {
+	switch value {
+	case "1.2":
+		*v = tls.VersionTLS12
+	case "1.3":
+		*v = tls.VersionTLS13
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidTLSVersion, value)
+	}
+
+	return nil
+}
+
+func validateTLS(t TLS) error {
+	switch {
+	case (t.Cert == "") != (t.Key == ""):
+		return fmt.Errorf("%w: a certificate and a key must be set together", ErrIncompleteTLS)
+	case (t.GatewayCert == "") != (t.GatewayKey == ""):
+		return fmt.Errorf("%w: a gateway certificate and key must be set together", ErrIncompleteTLS)
+	case !t.Enabled() && (t.ClientCA != "" || t.GatewayCert != "" || t.GatewayCA != ""):
+		return fmt.Errorf("%w: client CAs and gateway certificates require a server certificate", ErrIncompleteTLS)
+	default:
+		return nil
+	}
+}
+
+func mergeTLS(base, next TLS) TLS {
+	if next.Cert != "" {
+		base.Cert = next.Cert
+	}
+
+	if next.Key != "" {
+		base.Key = next.Key
+	}
+
+	if next.ClientCA != "" {
+		base.ClientCA = next.ClientCA
+	}
+
+	if next.MinVersion > 0 {
+		base.MinVersion = next.MinVersion
+	}
+
+	if next.ReloadInterval > 0 {
+		base.ReloadInterval = next.ReloadInterval
+	}
+
+	if next.GatewayCert != "" {
+		base.GatewayCert = next.GatewayCert
+	}
+
+	if next.GatewayKey != "" {
+		base.GatewayKey = next.GatewayKey
+	}
+
+	if next.GatewayCA != "" {
+		base.GatewayCA = next.GatewayCA
+	}
+
+	if next.GatewayServerName != "" {
+		base.GatewayServerName = next.GatewayServerName
+	}
+
+	return base
+}
+
+func applyTLSDefaults(t TLS) TLS {
+	if t.MinVersion == 0 {
+		t.MinVersion = tls.VersionTLS12
+	}
+
+	if t.ReloadInterval <= 0 {
+		t.ReloadInterval = defaultTLSReloadInterval
+	}
+
+	if t.GatewayCert == "" {
+		t.GatewayCert, t.GatewayKey = t.Cert, t.Key
+	}
+
+	if t.GatewayCA == "" {
+		t.GatewayCA = t.Cert
+	}
+
+	if t.GatewayServerName == "" {
+		t.GatewayServerName = "localhost"
+	}
+
+	return t
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"runtime"
+	"strconv"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+var ErrInvalidSampleRate = errors.New("invalid sample rate")
+
+type Verify struct {
+	Input      string     `envconfig:"PRIMES_VERIFY_INPUT"`
+	SampleRate SampleRate `envconfig:"PRIMES_VERIFY_SAMPLE_RATE"`
+	SkipSieve  bool       `envconfig:"PRIMES_VERIFY_SKIP_SIEVE"`
+	Workers    int        `envconfig:"PRIMES_VERIFY_WORKERS"`
+	Max        int64      `envconfig:"PRIMES_VERIFY_MAX"`
+}
+
+// SampleRate is the fraction of rows checked for primality, in (0, 1].
+type SampleRate float64
+
+func (r *SampleRate) Decode(value string) error {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+
+	if n <= 0 || n > 1 {
+		return fmt.Errorf("%w: %v; must be greater than 0 and up to 1", ErrInvalidSampleRate, n)
+	}
+
+	*r = SampleRate(n)
+
+	return nil
+}
+
+func NewVerify(args []string) (*Verify, error) {
+	flagsConfig, err := flagsVerify(args)
+	if err != nil {
+		return nil, err
+	}
+
+	envConfig, err := envVerify()
+	if err != nil {
+		return nil, err
+	}
+
+	return applyVerifyDefaults(mergeVerify(flagsConfig, envConfig)), nil
+}
+
+func flagsVerify(args []string) (*Verify, error) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+
+	input := fs.String("input", "", "path to the partitioned dataset to verify. Default is './sqlite/partitions'")
+	sampleRate := fs.String("sample-rate", "", "fraction of rows to run a Miller-Rabin primality test on. Default is '1'")
+	skipSieve := fs.Bool("skip-sieve", false, "skip comparing each partition against a segmented sieve over its range")
+	workers := fs.Int("workers", 0, "number of partitions verified concurrently. Default is the number of CPUs")
+	maximum := fs.Int64("max", 0, "the value the dataset is expected to cover up to. Default is '9999999999'")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	config := &Verify{}
+
+	if *input != "" {
+		config.Input = *input
+	}
+
+	if *sampleRate != "" {
+		if err := config.SampleRate.Decode(*sampleRate); err != nil {
+			return nil, err
+		}
+	}
+
+	if *skipSieve {
+		config.SkipSieve = *skipSieve
+	}
+
+	if *workers > 0 {
+		config.Workers = *workers
+	}
+
+	if *maximum > 0 {
+		config.Max = *maximum
+	}
+
+	return config, nil
+}
+
+func envVerify() (*Verify, error) {
+	config := &Verify{}
+
+	err := envconfig.Process("", config)
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func mergeVerify(base, next *Verify) *Verify {
+	if next.Input != "" {
+		base.Input = next.Input
+	}
+
+	if next.SampleRate > 0 {
+		base.SampleRate = next.SampleRate
+	}
+
+	if next.SkipSieve {
+		base.SkipSieve = true
+	}
+
+	if next.Workers > 0 {
+		base.Workers = next.Workers
+	}
+
+	if next.Max > 0 {
+		base.Max = next.Max
+	}
+
+	return base
+}
+
+func applyVerifyDefaults(config *Verify) *Verify {
+	if config.Input == "" {
+		config.Input = "./sqlite/partitions"
+	}
+
+	if config.SampleRate == 0 {
+		config.SampleRate = 1
+	}
+
+	if config.Workers <= 0 {
+		config.Workers = runtime.NumCPU()
+	}
+
+	if config.Max <= 0 {
+		config.Max = maxLimit
+	}
+
+	return config
+}
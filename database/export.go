@@ -0,0 +1,266 @@
+package database
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// StdoutOutput is the output path that writes an export to stdout.
+const StdoutOutput = "-"
+
+const exportBufferSize = 1 << 20
+
+// ExportFormat sets how exported primes are written.
+type ExportFormat string
+
+const (
+	// ExportRaw writes newline-separated text files in the same layout as the raw data directory.
+	ExportRaw ExportFormat = "raw"
+	// ExportCSV writes a single CSV file, with a prime per row under a `prime` header.
+	ExportCSV ExportFormat = "csv"
+	// ExportNDJSON writes a single file with a JSON object per line, as `{"prime":2}`.
+	ExportNDJSON ExportFormat = "ndjson"
+	// ExportBinary writes a single file with each prime as a little-endian uint64.
+	ExportBinary ExportFormat = "binary"
+)
+
+// Compression sets how exported files are compressed.
+type Compression string
+
+const (
+	CompressionNone Compression = "none"
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+var (
+	ErrInvalidExportFormat = errors.New("invalid export format")
+	ErrInvalidCompression  = errors.New("invalid compression")
+	ErrInvalidRange        = errors.New("invalid export range")
+)
+
+// ExportOptions configures an Export.
+type ExportOptions struct {
+	Format      ExportFormat
+	Compression Compression
+
+	// Min and Max set the range of primes to export, inclusive. A zero Max exports all primes from Min onwards.
+	Min int
+	Max int
+}
+
+// Export reads the primes within the options' range from the SQLite dataset at input, either a single database file
+// or a partitioned dataset's directory, and writes them to output in the options' format, returning how many primes
+// were written.
+//
+// The raw format writes ShardSize lines per file in the output directory, as `split` would name them, with a `.gz` or
+// `.zst` extension if compressed. Other formats write a single file at output, or to stdout if output is StdoutOutput.
+func Export(ctx context.Context, input, output string, opts ExportOptions, logger *slog.Logger) (int, error) {
+	start := time.Now()
+
+	if opts.Max == 0 {
+		opts.Max = math.MaxInt
+	}
+
+	if opts.Min < 0 || opts.Min > opts.Max {
+		return 0, fmt.Errorf("%w: [%d, %d]", ErrInvalidRange, opts.Min, opts.Max)
+	}
+
+	src, err := openSQLiteSource(ctx, input, logger)
+	if err != nil {
+		return 0, err
+	}
+
+	w, err := newExportWriter(output, opts)
+	if err != nil {
+		return 0, errors.Join(err, src.Close())
+	}
+
+	n, err := exportValues(ctx, src, w, opts.Min, opts.Max)
+	if err = errors.Join(err, w.Close(), src.Close()); err != nil {
+		return n, err
+	}
+
+	logger.InfoContext(ctx, "operation completed",
+		slog.Int("num_primes", n),
+		slog.Duration("time_elapsed", time.Since(start)),
+	)
+
+	return n, nil
+}
+
+// exportWriter writes exported primes in a given format.
+type exportWriter interface {
+	Write(values ...int64) error
+	Close() error
+}
+
+func newExportWriter(output string, opts ExportOptions) (exportWriter, error) {
+	compress, ext, err := compressor(opts.Compression)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		header []byte
+		encode func(buf []byte, value int64) []byte
+	)
+
+	switch opts.Format {
+	case ExportRaw:
+		if output == StdoutOutput {
+			return nil, fmt.Errorf("%w: %q writes a directory, and cannot be written to stdout",
+				ErrInvalidExportFormat, opts.Format)
+		}
+
+		w, err := NewShardWriter(output, ShardSize)
+		if err != nil {
+			return nil, err
+		}
+
+		w.compress, w.ext = compress, ext
+
+		return w, nil
+	case ExportCSV:
+		header = []byte("prime\n")
+		encode = appendLine
+	case ExportNDJSON:
+		encode = appendJSONLine
+	case ExportBinary:
+		encode = appendUint64
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidExportFormat, opts.Format)
+	}
+
+	var f io.WriteCloser = nopCloser{Writer: os.Stdout}
+
+	if output != StdoutOutput {
+		if f, err = os.Create(output); err != nil {
+			return nil, err
+		}
+	}
+
+	sink, err := compress(f)
+	if err != nil {
+		return nil, errors.Join(err, f.Close())
+	}
+
+	w := &streamWriter{
+		w:      bufio.NewWriterSize(sink, exportBufferSize),
+		closer: closers{sink, f},
+		encode: encode,
+		buf:    make([]byte, 0, 32),
+	}
+
+	if _, err = w.w.Write(header); err != nil {
+		return nil, errors.Join(err, w.Close())
+	}
+
+	return w, nil
+}
+
+// compressor returns a function wrapping a writer with the input compression, along with the extension of the files it
+// writes.
+func compressor(c Compression) (func(io.WriteCloser) (io.WriteCloser, error), string, error) {
+	switch c {
+	case CompressionNone, "":
+		return func(w io.WriteCloser) (io.WriteCloser, error) {
+			return nopCloser{Writer: w}, nil
+		}, "", nil
+	case CompressionGzip:
+		return func(w io.WriteCloser) (io.WriteCloser, error) {
+			return gzip.NewWriter(w), nil
+		}, ".gz", nil
+	case CompressionZstd:
+		return func(w io.WriteCloser) (io.WriteCloser, error) {
+			return zstd.NewWriter(w)
+		}, ".zst", nil
+	default:
+		return nil, "", fmt.Errorf("%w: %q", ErrInvalidCompression, c)
+	}
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// streamWriter writes exported primes to a single file, encoding each of them with encode.
+type streamWriter struct {
+	w      *bufio.Writer
+	closer io.Closer
+	encode func(buf []byte, value int64) []byte
+	buf    []byte
+}
+
+func (s *streamWriter) Write(values ...int64) error {
+	for i := range values {
+		s.buf = s.encode(s.buf[:0], values[i])
+
+		if _, err := s.w.Write(s.buf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close flushes the buffered output, and closes the compressor and file, in order.
+func (s *streamWriter) Close() error {
+	return errors.Join(s.w.Flush(), s.closer.Close())
+}
+
+func appendLine(buf []byte, value int64) []byte {
+	return append(strconv.AppendInt(buf, value, 10), '\n')
+}
+
+func appendJSONLine(buf []byte, value int64) []byte {
+	buf = append(buf, `{"prime":`...)
+
+	return append(strconv.AppendInt(buf, value, 10), '}', '\n')
+}
+
+func appendUint64(buf []byte, value int64) []byte {
+	return binary.LittleEndian.AppendUint64(buf, uint64(value))
+}
+
+func exportValues(ctx context.Context, src valueSource, w exportWriter, minimum, maximum int) (int, error) {
+	r, err := src.From(ctx, minimum)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+
+	for {
+		value, ok, err := r.Next()
+		if err != nil {
+			return n, errors.Join(err, r.Close())
+		}
+
+		if !ok || value > maximum {
+			return n, r.Close()
+		}
+
+		if err = w.Write(int64(value)); err != nil {
+			return n, errors.Join(err, r.Close())
+		}
+
+		n++
+	}
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+)
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	input := newTestDataset(t)
+
+	// spans the first two partitions
+	opts := ExportOptions{Min: 99_980, Max: 100_010}
+	wants := []int{99_989, 99_991, 100_003}
+
+	for _, testcase := range []struct {
+		name        string
+		format      ExportFormat
+		compression Compression
+		wants       []byte
+	}{
+		{
+			name:   "CSV",
+			format: ExportCSV,
+			wants:  []byte("prime\n99989\n99991\n100003\n"),
+		},
+		{
+			name:   "NDJSON",
+			format: ExportNDJSON,
+			wants:  []byte("{\"prime\":99989}\n{\"prime\":99991}\n{\"prime\":100003}\n"),
+		},
+		{
+			name:   "Binary",
+			format: ExportBinary,
+			wants: binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(
+				binary.LittleEndian.AppendUint64(nil, 99_989), 99_991), 100_003),
+		},
+		{
+			name:        "CSVGzip",
+			format:      ExportCSV,
+			compression: CompressionGzip,
+			wants:       []byte("prime\n99989\n99991\n100003\n"),
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "export")
+			opts := opts
+			opts.Format, opts.Compression = testcase.format, testcase.compression
+
+			n, err := Export(ctx, input, output, opts, log.NoOp())
+			require.NoError(t, err)
+			require.Equal(t, len(wants), n)
+
+			data, err := os.ReadFile(output)
+			require.NoError(t, err)
+
+			if testcase.compression == CompressionGzip {
+				gz, err := gzip.NewReader(bytes.NewReader(data))
+				require.NoError(t, err)
+
+				data, err = io.ReadAll(gz)
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, testcase.wants, data)
+		})
+	}
+
+	t.Run("RawZstd", func(t *testing.T) {
+		output := t.TempDir()
+
+		n, err := Export(ctx, input, output, ExportOptions{Format: ExportRaw, Compression: CompressionZstd}, log.NoOp())
+		require.NoError(t, err)
+		require.Equal(t, 25_997, n)
+
+		entries, err := os.ReadDir(output)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, "primes-aa.zst", entries[0].Name())
+
+		// the export is readable as a build input
+		d, err := openInput(ctx, output, Validation{}, log.NoOp())
+		require.NoError(t, err)
+
+		r, err := d.From(ctx, 99_980)
+		require.NoError(t, err)
+
+		values, err := readAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		require.Equal(t, wants, values[:3])
+	})
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		_, err := Export(ctx, input, StdoutOutput, ExportOptions{Format: ExportRaw}, log.NoOp())
+		require.ErrorIs(t, err, ErrInvalidExportFormat)
+
+		_, err = Export(ctx, input, StdoutOutput, ExportOptions{Format: ExportCSV, Compression: "lz4"}, log.NoOp())
+		require.ErrorIs(t, err, ErrInvalidCompression)
+
+		_, err = Export(ctx, input, StdoutOutput, ExportOptions{Format: ExportCSV, Min: 10, Max: 5}, log.NoOp())
+		require.ErrorIs(t, err, ErrInvalidRange)
+	})
+}
@@ -0,0 +1,289 @@
+package database
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// StdinInput is the input path that reads the input from stdin.
+const StdinInput = "-"
+
+const (
+	tarHeaderSize    = 512
+	tarMagicOffset   = 257
+	sniffBufferSize  = 4096
+	stdinTempPattern = "primes-stdin-*"
+	tarTempPattern   = "primes-tar-*"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	tarMagic  = []byte("ustar")
+
+	tarExtensions = []string{".tar", ".tar.gz", ".tgz", ".tar.zst", ".tzst"}
+)
+
+// shard is a text file with a value per line, either as is or compressed with gzip or zstd.
+type shard struct {
+	// name identifies the shard in logs and errors
+	name string
+	path string
+}
+
+func (s shard) open() (io.ReadCloser, error) {
+	return openDecompressed(s.path)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// openDecompressed opens the file at path, decompressing its contents if it is compressed with gzip or zstd, as
+// detected by its magic bytes. Uncompressed files are returned as is.
+func openDecompressed(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	magic := make([]byte, len(zstdMagic))
+
+	n, err := f.ReadAt(magic, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.Join(err, f.Close())
+	}
+
+	magic = magic[:n]
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("%s: %w", path, err), f.Close())
+		}
+
+		return readCloser{Reader: gz, Closer: closers{gz, f}}, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		// readers are opened by each build worker, so they decode in a single goroutine each
+		zr, err := zstd.NewReader(f, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("%s: %w", path, err), f.Close())
+		}
+
+		return readCloser{Reader: zr, Closer: closers{zr.IOReadCloser(), f}}, nil
+	default:
+		return f, nil
+	}
+}
+
+type closers []io.Closer
+
+func (c closers) Close() error {
+	errs := make([]error, 0, len(c))
+
+	for i := range c {
+		errs = append(errs, c[i].Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// expandFile returns the shards in the file at path, which is either a shard itself, or a tar archive of shards, sorted
+// by name.
+//
+// As blocks are built concurrently from different points in the input, the members of an archive are extracted in a
+// single pass, uncompressed, to a temporary directory, whose path is returned to be removed once the input is closed.
+func expandFile(name, path string) (shards []shard, temp string, err error) {
+	r, err := openDecompressed(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	defer r.Close()
+
+	br := bufio.NewReaderSize(r, sniffBufferSize)
+
+	head, err := br.Peek(tarHeaderSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, "", fmt.Errorf("%s: %w", name, err)
+	}
+
+	if !isTar(name, head) {
+		return []shard{{name: name, path: path}}, "", nil
+	}
+
+	temp, err = os.MkdirTemp("", tarTempPattern)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if shards, err = extractTar(name, tar.NewReader(br), temp); err != nil {
+		return nil, "", errors.Join(err, os.RemoveAll(temp))
+	}
+
+	// archives list their members in the order they were added, which is not necessarily sorted
+	sort.Slice(shards, func(i, j int) bool {
+		return shards[i].name < shards[j].name
+	})
+
+	return shards, temp, nil
+}
+
+// extractTar writes the regular files in the archive to dir, named by their position in the archive, as member names
+// may hold directories or collide once flattened.
+func extractTar(name string, tr *tar.Reader, dir string) ([]shard, error) {
+	shards := make([]shard, 0, 64)
+
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return shards, nil
+			}
+
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		s := shard{
+			name: name + ":" + header.Name,
+			path: filepath.Join(dir, fmt.Sprintf("%06d", len(shards))),
+		}
+
+		if err = extractMember(tr, s.path); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.name, err)
+		}
+
+		shards = append(shards, s)
+	}
+}
+
+func extractMember(r io.Reader, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(f, r)
+
+	return errors.Join(err, f.Close())
+}
+
+func isTar(name string, head []byte) bool {
+	if end := tarMagicOffset + len(tarMagic); len(head) >= end && bytes.Equal(head[tarMagicOffset:end], tarMagic) {
+		return true
+	}
+
+	for _, ext := range tarExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// openInput opens the build input at input, which is either a directory of shards, a single shard, a tar archive of
+// shards, or StdinInput to read any of the latter from stdin. Shards and archives may be compressed with gzip or zstd.
+// The input is read through once to check its lines as set by validation, before any block is built from it.
+//
+// As blocks are built concurrently from different points in the input, stdin is first copied as is (still compressed,
+// if so) to a temporary file, and tar archives are extracted to a temporary directory. Both are removed once the input
+// is closed.
+func openInput(ctx context.Context, input string, validation Validation, logger *slog.Logger) (*rawInput, error) {
+	d := &rawInput{logger: logger, validation: validation}
+
+	if err := d.addInput(ctx, input); err != nil {
+		return nil, errors.Join(err, d.Close())
+	}
+
+	if err := d.validate(ctx); err != nil {
+		return nil, errors.Join(err, d.Close())
+	}
+
+	return d, nil
+}
+
+func (d *rawInput) addInput(ctx context.Context, input string) error {
+	if input == StdinInput {
+		temp, err := spoolStdin(ctx, d.logger)
+		if err != nil {
+			return err
+		}
+
+		if err = d.add(ctx, "stdin", temp); err != nil {
+			return errors.Join(err, os.Remove(temp))
+		}
+
+		// archives are extracted, so the copy is only kept if it is a shard itself
+		if len(d.shards) == 0 || d.shards[len(d.shards)-1].path != temp {
+			return os.Remove(temp)
+		}
+
+		d.temps = append(d.temps, temp)
+
+		return nil
+	}
+
+	stat, err := os.Stat(input)
+	if err != nil {
+		return err
+	}
+
+	if !stat.IsDir() {
+		return d.add(ctx, filepath.Base(input), input)
+	}
+
+	entries, err := os.ReadDir(input)
+	if err != nil {
+		return err
+	}
+
+	d.logger.InfoContext(ctx, "scanned directory", slog.Int("num_files", len(entries)))
+
+	for i := range entries {
+		if !entries[i].Type().IsRegular() {
+			continue
+		}
+
+		if err = d.add(ctx, entries[i].Name(), filepath.Join(input, entries[i].Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func spoolStdin(ctx context.Context, logger *slog.Logger) (string, error) {
+	f, err := os.CreateTemp("", stdinTempPattern)
+	if err != nil {
+		return "", err
+	}
+
+	n, err := io.Copy(f, os.Stdin)
+	if err = errors.Join(err, f.Close()); err != nil {
+		return "", errors.Join(err, os.Remove(f.Name()))
+	}
+
+	logger.InfoContext(ctx, "copied input from stdin", slog.String("path", f.Name()), slog.Int64("size", n))
+
+	return f.Name(), nil
+}
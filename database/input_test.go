@@ -0,0 +1,189 @@
+package database
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+)
+
+var testShards = []struct {
+	name string
+	data string
+}{
+	// out of order, as archives do not necessarily list their members sorted
+	{name: "primes-ab", data: "7\n11\n13\n"},
+	{name: "primes-aa", data: "2\n3\n5\n"},
+	{name: "primes-ac", data: "17\n"},
+}
+
+func gzipData(t *testing.T, data []byte) []byte {
+	buf := &bytes.Buffer{}
+
+	w := gzip.NewWriter(buf)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func zstdData(t *testing.T, data []byte) []byte {
+	buf := &bytes.Buffer{}
+
+	w, err := zstd.NewWriter(buf)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func tarData(t *testing.T) []byte {
+	buf := &bytes.Buffer{}
+
+	w := tar.NewWriter(buf)
+	require.NoError(t, w.WriteHeader(&tar.Header{Name: "raw/", Typeflag: tar.TypeDir, Mode: 0o755}))
+
+	for _, s := range testShards {
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: "raw/" + s.name, Mode: 0o644, Size: int64(len(s.data))}))
+		_, err := w.Write([]byte(s.data))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func writeShards(t *testing.T, compress func(*testing.T, []byte) []byte, ext string) string {
+	dir := t.TempDir()
+
+	for _, s := range testShards {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, s.name+ext), compress(t, []byte(s.data)), 0o644))
+	}
+
+	return dir
+}
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	return path
+}
+
+func TestOpenInput(t *testing.T) {
+	ctx := context.Background()
+	wants := []int{2, 3, 5, 7, 11, 13, 17}
+
+	for _, testcase := range []struct {
+		name  string
+		input func(t *testing.T) string
+	}{
+		{
+			name: "Gzip",
+			input: func(t *testing.T) string {
+				return writeShards(t, gzipData, ".gz")
+			},
+		},
+		{
+			name: "Zstd",
+			input: func(t *testing.T) string {
+				return writeShards(t, zstdData, ".zst")
+			},
+		},
+		{
+			name: "Tar",
+			input: func(t *testing.T) string {
+				return writeFile(t, "raw.tar", tarData(t))
+			},
+		},
+		{
+			name: "TarGzipWithoutExtension",
+			input: func(t *testing.T) string {
+				return writeFile(t, "raw", gzipData(t, tarData(t)))
+			},
+		},
+		{
+			name: "TarZstdInDirectory",
+			input: func(t *testing.T) string {
+				return filepath.Dir(writeFile(t, "raw.tar.zst", zstdData(t, tarData(t))))
+			},
+		},
+		{
+			name: "Stdin",
+			input: func(t *testing.T) string {
+				f, err := os.Open(writeFile(t, "raw.tar.gz", gzipData(t, tarData(t))))
+				require.NoError(t, err)
+
+				stdin := os.Stdin
+				os.Stdin = f
+
+				t.Cleanup(func() {
+					os.Stdin = stdin
+					require.NoError(t, f.Close())
+				})
+
+				return StdinInput
+			},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			data, err := openInput(ctx, testcase.input(t), Validation{}, log.NoOp())
+			require.NoError(t, err)
+
+			last, err := data.Last()
+			require.NoError(t, err)
+			require.Equal(t, 17, last)
+
+			for from, expected := range map[int][]int{math.MinInt: wants, 6: wants[3:], 17: wants[6:]} {
+				r, err := data.From(ctx, from)
+				require.NoError(t, err)
+
+				values, err := readAll(r)
+				require.NoError(t, err)
+				require.Equal(t, expected, values)
+				require.NoError(t, r.Close())
+			}
+
+			require.NoError(t, data.Close())
+
+			for _, temp := range data.temps {
+				require.NoFileExists(t, temp)
+				require.NoDirExists(t, temp)
+			}
+		})
+	}
+
+	t.Run("TarIsExtractedOnce", func(t *testing.T) {
+		path := writeFile(t, "raw.tar.gz", gzipData(t, tarData(t)))
+
+		data, err := openInput(ctx, path, Validation{}, log.NoOp())
+		require.NoError(t, err)
+
+		defer func() {
+			require.NoError(t, data.Close())
+		}()
+
+		// blocks are read from the extracted shards, instead of decompressing the archive again for each of them
+		require.NoError(t, os.Remove(path))
+
+		r, err := data.From(ctx, 6)
+		require.NoError(t, err)
+
+		values, err := readAll(r)
+		require.NoError(t, err)
+		require.Equal(t, wants[3:], values)
+		require.NoError(t, r.Close())
+	})
+}
@@ -0,0 +1,246 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	querySelectLayout = `SELECT strategy, block_size FROM layout;`
+	insertLayoutQuery = `INSERT INTO layout (strategy, block_size) VALUES (?, ?);`
+)
+
+// Strategy sets how the values in a partitioned build are split into partitions.
+type Strategy string
+
+const (
+	// StrategyWidth splits the values into partitions covering equal value ranges.
+	StrategyWidth Strategy = "width"
+	// StrategyCount splits the values into partitions holding an equal number of primes.
+	StrategyCount Strategy = "count"
+	// StrategyFile splits the values into partitions at boundaries read from a file.
+	StrategyFile Strategy = "file"
+)
+
+var (
+	ErrInvalidStrategy   = errors.New("invalid partitioning strategy")
+	ErrInvalidBoundaries = errors.New("invalid partition boundaries")
+)
+
+// Layout sets how a partitioned build splits its values into partitions. It is recorded in the dataset's index, so
+// that readers can locate partitions accordingly, and so that a build is only resumed with the same layout.
+type Layout struct {
+	Strategy Strategy
+
+	// BlockSize is the value range covered by each partition with StrategyWidth, or the number of primes held by each
+	// partition with StrategyCount.
+	BlockSize int
+
+	// Boundaries holds the first value of each partition but the first one, which starts at zero, with StrategyFile.
+	Boundaries []int
+}
+
+// WidthLayout returns a Layout splitting the values into partitions covering blockSize values each.
+func WidthLayout(blockSize int) Layout {
+	return Layout{Strategy: StrategyWidth, BlockSize: blockSize}
+}
+
+// blocks returns the blocks to build from the values in src, up to maximum, which is the last value in src.
+func (l Layout) blocks(ctx context.Context, src valueSource, maximum int, logger *slog.Logger) ([]block, error) {
+	switch l.Strategy {
+	case StrategyWidth:
+		if l.BlockSize <= 0 {
+			return nil, fmt.Errorf("%w: block size must be positive: %d", ErrInvalidStrategy, l.BlockSize)
+		}
+
+		return prepareBlocks(maximum, l.BlockSize), nil
+	case StrategyCount:
+		if l.BlockSize <= 0 {
+			return nil, fmt.Errorf("%w: block size must be positive: %d", ErrInvalidStrategy, l.BlockSize)
+		}
+
+		boundaries, err := countBoundaries(ctx, src, l.BlockSize)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.InfoContext(ctx, "computed partition boundaries",
+			slog.Int("primes_per_partition", l.BlockSize),
+			slog.Int("num_partitions", len(boundaries)+1),
+		)
+
+		return boundaryBlocks(boundaries, maximum)
+	case StrategyFile:
+		return boundaryBlocks(l.Boundaries, maximum)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidStrategy, l.Strategy)
+	}
+}
+
+// countBoundaries reads through src, returning the first value of every partition but the first one, so that each of
+// them holds count values, except for the last one which holds the remainder.
+func countBoundaries(ctx context.Context, src valueSource, count int) ([]int, error) {
+	r, err := src.From(ctx, math.MinInt)
+	if err != nil {
+		return nil, err
+	}
+
+	boundaries := make([]int, 0, minAlloc)
+
+	for n := 0; ; n++ {
+		value, ok, err := r.Next()
+		if err != nil {
+			return nil, errors.Join(err, r.Close())
+		}
+
+		if !ok {
+			break
+		}
+
+		if n > 0 && n%count == 0 {
+			boundaries = append(boundaries, value)
+		}
+	}
+
+	return boundaries, r.Close()
+}
+
+// boundaryBlocks returns the blocks delimited by boundaries, which hold the first value of each block but the first
+// one, in strictly increasing order. The first block starts at zero, and the last one ends at maximum.
+func boundaryBlocks(boundaries []int, maximum int) ([]block, error) {
+	for i := range boundaries {
+		switch {
+		case boundaries[i] <= 0:
+			return nil, fmt.Errorf("%w: %d must be positive", ErrInvalidBoundaries, boundaries[i])
+		case boundaries[i] > maximum:
+			return nil, fmt.Errorf("%w: %d is past the input's maximum %d", ErrInvalidBoundaries, boundaries[i], maximum)
+		case i > 0 && boundaries[i] <= boundaries[i-1]:
+			return nil, fmt.Errorf("%w: %d follows %d", ErrInvalidBoundaries, boundaries[i], boundaries[i-1])
+		}
+	}
+
+	values := make([]block, 0, len(boundaries)+1)
+
+	id := make([]byte, ((len(boundaries)+1)/255)+1)
+
+	from := 0
+
+	for i := 0; i <= len(boundaries); i++ {
+		to := maximum
+		if i < len(boundaries) {
+			to = boundaries[i] - 1
+		}
+
+		values = append(values, block{
+			from: from,
+			to:   to,
+			id:   hex.EncodeToString(id),
+		})
+
+		incID(id)
+
+		from = to + 1
+	}
+
+	return values, nil
+}
+
+// ReadBoundaries reads partition boundaries from the file at path, for StrategyFile. The file holds the first value
+// of each partition but the first one, in strictly increasing order, either as a JSON array of integers, or as CSV
+// with any number of values per line.
+func ReadBoundaries(path string) ([]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		boundaries := make([]int, 0, minAlloc)
+
+		if err = json.Unmarshal(data, &boundaries); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidBoundaries, path, err)
+		}
+
+		return boundaries, nil
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	boundaries := make([]int, 0, minAlloc)
+
+	for {
+		record, err := r.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return boundaries, nil
+			}
+
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidBoundaries, path, err)
+		}
+
+		for i := range record {
+			field := strings.TrimSpace(record[i])
+			if field == "" {
+				continue
+			}
+
+			value, err := strconv.Atoi(field)
+			if err != nil {
+				line, _ := r.FieldPos(i)
+
+				return nil, fmt.Errorf("%w: %s:%d: %w", ErrInvalidBoundaries, path, line, err)
+			}
+
+			boundaries = append(boundaries, value)
+		}
+	}
+}
+
+// recordLayout writes layout to the index, or checks it against the one recorded by a previous build in the same
+// directory, returning an error if they differ.
+func recordLayout(ctx context.Context, db *sql.DB, layout Layout) error {
+	var (
+		strategy  Strategy
+		blockSize int
+	)
+
+	err := db.QueryRowContext(ctx, querySelectLayout).Scan(&strategy, &blockSize)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = db.ExecContext(ctx, insertLayoutQuery, layout.Strategy, layout.recordedBlockSize())
+
+		return err
+	case err != nil:
+		return err
+	case strategy != layout.Strategy || blockSize != layout.recordedBlockSize():
+		return fmt.Errorf("%w: dataset was built with the %q strategy and a block size of %d",
+			ErrCheckpointMismatch, strategy, blockSize)
+	default:
+		return nil
+	}
+}
+
+// recordedBlockSize returns the block size recorded in the index for the layout, which is zero for StrategyFile, as
+// its partitions have no fixed size.
+func (l Layout) recordedBlockSize() int {
+	if l.Strategy == StrategyFile {
+		return 0
+	}
+
+	return l.BlockSize
+}
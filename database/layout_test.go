@@ -0,0 +1,213 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+func TestBoundaryBlocks(t *testing.T) {
+	for _, testcase := range []struct {
+		name       string
+		boundaries []int
+		maximum    int
+		wants      []block
+		err        error
+	}{
+		{
+			name:    "NoBoundaries",
+			maximum: 97,
+			wants:   []block{{from: 0, to: 97, id: "00"}},
+		},
+		{
+			name:       "Split",
+			boundaries: []int{10, 50},
+			maximum:    97,
+			wants: []block{
+				{from: 0, to: 9, id: "00"},
+				{from: 10, to: 49, id: "01"},
+				{from: 50, to: 97, id: "02"},
+			},
+		},
+		{
+			name:       "AtMaximum",
+			boundaries: []int{97},
+			maximum:    97,
+			wants: []block{
+				{from: 0, to: 96, id: "00"},
+				{from: 97, to: 97, id: "01"},
+			},
+		},
+		{
+			name:       "NotIncreasing",
+			boundaries: []int{50, 10},
+			maximum:    97,
+			err:        ErrInvalidBoundaries,
+		},
+		{
+			name:       "Zero",
+			boundaries: []int{0, 10},
+			maximum:    97,
+			err:        ErrInvalidBoundaries,
+		},
+		{
+			name:       "PastMaximum",
+			boundaries: []int{10, 100},
+			maximum:    97,
+			err:        ErrInvalidBoundaries,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			blocks, err := boundaryBlocks(testcase.boundaries, testcase.maximum)
+			if testcase.err != nil {
+				require.ErrorIs(t, err, testcase.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testcase.wants, blocks)
+		})
+	}
+}
+
+func TestReadBoundaries(t *testing.T) {
+	for _, testcase := range []struct {
+		name  string
+		data  string
+		wants []int
+		err   string
+	}{
+		{
+			name:  "JSON",
+			data:  "[100000, 150000,\n 200000]\n",
+			wants: []int{100_000, 150_000, 200_000},
+		},
+		{
+			name:  "CSVLines",
+			data:  "100000\n150000\n200000\n",
+			wants: []int{100_000, 150_000, 200_000},
+		},
+		{
+			name:  "CSVRow",
+			data:  "100000, 150000,200000\n",
+			wants: []int{100_000, 150_000, 200_000},
+		},
+		{
+			name: "InvalidJSON",
+			data: `[100000, "x"]`,
+			err:  "invalid partition boundaries",
+		},
+		{
+			name: "InvalidCSV",
+			data: "100000\n150000\nx\n",
+			err:  "boundaries:3",
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			path := writeFile(t, "boundaries", []byte(testcase.data))
+
+			boundaries, err := ReadBoundaries(path)
+			if testcase.err != "" {
+				require.ErrorIs(t, err, ErrInvalidBoundaries)
+				require.ErrorContains(t, err, testcase.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testcase.wants, boundaries)
+		})
+	}
+}
+
+func TestPartitionData_Strategies(t *testing.T) {
+	ctx := context.Background()
+	primes := sieve.BasePrimes(300_000)
+
+	data := make([]int, len(primes))
+	for i := range primes {
+		data[i] = int(primes[i])
+	}
+
+	t.Run("Count", func(t *testing.T) {
+		dir := t.TempDir()
+		layout := Layout{Strategy: StrategyCount, BlockSize: 10_000}
+
+		require.NoError(t, PartitionData(ctx, data, layout, 2, dir, log.NoOp()))
+		require.NoError(t, VerifyManifest(dir, VerifyFull, log.NoOp()))
+
+		manifest, err := ReadManifest(dir)
+		require.NoError(t, err)
+		require.Equal(t, StrategyCount, manifest.Strategy)
+
+		// 300k holds 25997 primes, split in two full partitions and the remainder
+		require.Len(t, manifest.Partitions, 3)
+		require.Equal(t, 10_000, manifest.Partitions[0].Rows)
+		require.Equal(t, 10_000, manifest.Partitions[1].Rows)
+		require.Equal(t, len(data)-20_000, manifest.Partitions[2].Rows)
+
+		require.Equal(t, 0, manifest.Partitions[0].Min)
+		require.Equal(t, data[10_000], manifest.Partitions[1].Min)
+		require.Equal(t, data[20_000], manifest.Partitions[2].Min)
+		require.Equal(t, data[len(data)-1], manifest.Partitions[2].Max)
+
+		require.Equal(t, layout, readLayout(t, dir))
+
+		t.Run("ResumeWithDifferentStrategy", func(t *testing.T) {
+			require.ErrorIs(t, PartitionData(ctx, data, WidthLayout(100_000), 2, dir, log.NoOp()), ErrCheckpointMismatch)
+		})
+	})
+
+	t.Run("File", func(t *testing.T) {
+		dir := t.TempDir()
+		layout := Layout{Strategy: StrategyFile, Boundaries: []int{50_000, 250_000}}
+
+		require.NoError(t, PartitionData(ctx, data, layout, 2, dir, log.NoOp()))
+		require.NoError(t, VerifyManifest(dir, VerifyFull, log.NoOp()))
+
+		manifest, err := ReadManifest(dir)
+		require.NoError(t, err)
+		require.Len(t, manifest.Partitions, 3)
+
+		var rows int
+
+		for i, wants := range [][2]int{{0, 49_999}, {50_000, 249_999}, {250_000, data[len(data)-1]}} {
+			require.Equal(t, wants[0], manifest.Partitions[i].Min)
+			require.Equal(t, wants[1], manifest.Partitions[i].Max)
+
+			rows += manifest.Partitions[i].Rows
+		}
+
+		require.Equal(t, len(data), rows)
+		require.Equal(t, Layout{Strategy: StrategyFile}, readLayout(t, dir))
+
+		t.Run("ResumeWithDifferentBoundaries", func(t *testing.T) {
+			other := Layout{Strategy: StrategyFile, Boundaries: []int{100_000, 250_000}}
+
+			require.ErrorIs(t, PartitionData(ctx, data, other, 2, dir, log.NoOp()), ErrCheckpointMismatch)
+		})
+	})
+
+	t.Run("InvalidStrategy", func(t *testing.T) {
+		err := PartitionData(ctx, data, Layout{Strategy: "depth"}, 2, t.TempDir(), log.NoOp())
+		require.ErrorIs(t, err, ErrInvalidStrategy)
+	})
+}
+
+func readLayout(t *testing.T, dir string) Layout {
+	idx, err := OpenSQLite(filepath.Join(dir, "index.db"), ReadOnlyPragmas(), log.NoOp())
+	require.NoError(t, err)
+
+	defer idx.Close()
+
+	var layout Layout
+
+	require.NoError(t, idx.QueryRow(querySelectLayout).Scan(&layout.Strategy, &layout.BlockSize))
+
+	return layout
+}
@@ -0,0 +1,293 @@
+package database
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+)
+
+const (
+	ManifestFile = "manifest.json"
+
+	// SchemaVersion is the version of the partitioned dataset layout written by this builder, and the only one it can
+	// serve. It covers the primes and ranks tables in each partition, and the scopes, rank_intervals, buckets and layout
+	// tables in the index.
+	SchemaVersion = 1
+
+	sampleChunks    = 16
+	sampleChunkSize = 64 << 10
+)
+
+// Verification sets how thoroughly partition files are checked against the manifest.
+type Verification int
+
+const (
+	// VerifySampled checks each file's size, and hashes a fixed set of chunks spread across it.
+	VerifySampled Verification = iota
+	// VerifyFull checks each file's size, and hashes it in full.
+	VerifyFull
+)
+
+var (
+	ErrManifestMismatch       = errors.New("partition does not match the dataset manifest")
+	ErrUnsupportedSchema      = errors.New("unsupported dataset schema version")
+	ErrPartitionNotInManifest = errors.New("partition is not listed in the dataset manifest")
+)
+
+// Manifest describes a partitioned dataset, as written next to its index.db.
+type Manifest struct {
+	SchemaVersion  int                 `json:"schema_version"`
+	BuilderVersion string              `json:"builder_version"`
+	Strategy       Strategy            `json:"strategy,omitempty"`
+	BlockSize      int                 `json:"block_size"`
+	Partitions     []ManifestPartition `json:"partitions"`
+}
+
+type ManifestPartition struct {
+	ID   string `json:"id"`
+	File string `json:"file"`
+	Rows int    `json:"rows"`
+	Min  int    `json:"min"`
+	Max  int    `json:"max"`
+	Size int64  `json:"size"`
+
+	SHA256       string `json:"sha256"`
+	SampleSHA256 string `json:"sample_sha256"`
+}
+
+// ReadManifest reads the manifest of the partitioned dataset under dir.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{}
+
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// VerifyManifest checks the partition files under dir against the dataset's manifest, returning an error on the first
+// partition that is missing, truncated or modified, or if the dataset's schema version is not supported.
+//
+// Datasets built before manifests were introduced are not verified, and only log a warning.
+func VerifyManifest(dir string, mode Verification, logger *slog.Logger) error {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			logger.Warn("dataset has no manifest, skipping verification", slog.String("dir", dir))
+
+			return nil
+		}
+
+		return err
+	}
+
+	if manifest.SchemaVersion != SchemaVersion {
+		return fmt.Errorf("%w: %d, expected %d", ErrUnsupportedSchema, manifest.SchemaVersion, SchemaVersion)
+	}
+
+	for i := range manifest.Partitions {
+		if err = verifyPartition(dir, manifest.Partitions[i], mode); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("verified dataset manifest",
+		slog.Int("num_partitions", len(manifest.Partitions)),
+		slog.String("builder_version", manifest.BuilderVersion),
+		slog.Bool("full", mode == VerifyFull),
+	)
+
+	return nil
+}
+
+// verifyManifestIDs checks that all partition IDs registered in the index are listed in the manifest under dir, if any.
+func verifyManifestIDs(dir string, ids []string) error {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+
+	listed := make(map[string]struct{}, len(manifest.Partitions))
+	for i := range manifest.Partitions {
+		listed[manifest.Partitions[i].ID] = struct{}{}
+	}
+
+	for i := range ids {
+		if _, ok := listed[ids[i]]; !ok {
+			return fmt.Errorf("%w: %s", ErrPartitionNotInManifest, ids[i])
+		}
+	}
+
+	return nil
+}
+
+func verifyPartition(dir string, part ManifestPartition, mode Verification) error {
+	f, err := os.Open(filepath.Join(dir, part.File))
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrManifestMismatch, part.File, err)
+	}
+
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if stat.Size() != part.Size {
+		return fmt.Errorf("%w: %s: size is %d bytes, expected %d; the file may be truncated",
+			ErrManifestMismatch, part.File, stat.Size(), part.Size)
+	}
+
+	wants, hash := part.SampleSHA256, sampleHash
+	if mode == VerifyFull {
+		wants, hash = part.SHA256, fullHash
+	}
+
+	sum, err := hash(f, stat.Size())
+	if err != nil {
+		return err
+	}
+
+	if sum != wants {
+		return fmt.Errorf("%w: %s: checksum mismatch", ErrManifestMismatch, part.File)
+	}
+
+	return nil
+}
+
+// writeManifest hashes the partition files for blocks under dir, and writes the dataset's manifest. rows holds the
+// number of primes in each block.
+func writeManifest(dir string, blocks []block, rows []int, layout Layout) error {
+	manifest := Manifest{
+		SchemaVersion:  SchemaVersion,
+		BuilderVersion: builderVersion(),
+		Strategy:       layout.Strategy,
+		BlockSize:      layout.recordedBlockSize(),
+		Partitions:     make([]ManifestPartition, 0, len(blocks)),
+	}
+
+	for i := range blocks {
+		part, err := describePartition(dir, blocks[i], rows[i])
+		if err != nil {
+			return err
+		}
+
+		manifest.Partitions = append(manifest.Partitions, part)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, ManifestFile), data, 0o644)
+}
+
+func describePartition(dir string, b block, rows int) (ManifestPartition, error) {
+	name := pathBlock[1:] + b.id + ".db"
+
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return ManifestPartition{}, err
+	}
+
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return ManifestPartition{}, err
+	}
+
+	full, err := fullHash(f, stat.Size())
+	if err != nil {
+		return ManifestPartition{}, err
+	}
+
+	sample, err := sampleHash(f, stat.Size())
+	if err != nil {
+		return ManifestPartition{}, err
+	}
+
+	return ManifestPartition{
+		ID:           b.id,
+		File:         name,
+		Rows:         rows,
+		Min:          b.from,
+		Max:          b.to,
+		Size:         stat.Size(),
+		SHA256:       full,
+		SampleSHA256: sample,
+	}, nil
+}
+
+func fullHash(f *os.File, size int64) (string, error) {
+	h := sha256.New()
+
+	if _, err := io.Copy(h, io.NewSectionReader(f, 0, size)); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// sampleHash hashes the file size along with sampleChunks chunks spread evenly across the file, or the entire file if
+// it is smaller than that.
+func sampleHash(f *os.File, size int64) (string, error) {
+	if size <= sampleChunks*sampleChunkSize {
+		return fullHash(f, size)
+	}
+
+	h := sha256.New()
+
+	if err := binary.Write(h, binary.LittleEndian, size); err != nil {
+		return "", err
+	}
+
+	step := (size - sampleChunkSize) / (sampleChunks - 1)
+
+	for i := int64(0); i < sampleChunks; i++ {
+		if _, err := io.Copy(h, io.NewSectionReader(f, i*step, sampleChunkSize)); err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func builderVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+
+	return "(devel)"
+}
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+func newTestDataset(t *testing.T) string {
+	dir := t.TempDir()
+	primes := sieve.BasePrimes(300_000)
+
+	data := make([]int, len(primes))
+	for i := range primes {
+		data[i] = int(primes[i])
+	}
+
+	require.NoError(t, PartitionData(context.Background(), data, WidthLayout(100_000), 2, dir, log.NoOp()))
+
+	return dir
+}
+
+func TestManifest(t *testing.T) {
+	dir := newTestDataset(t)
+
+	manifest, err := ReadManifest(dir)
+	require.NoError(t, err)
+	require.Equal(t, SchemaVersion, manifest.SchemaVersion)
+	require.Equal(t, 100_000, manifest.BlockSize)
+	require.Len(t, manifest.Partitions, 3)
+
+	var rows int
+	for _, part := range manifest.Partitions {
+		require.FileExists(t, filepath.Join(dir, part.File))
+		require.NotEmpty(t, part.SHA256)
+		require.NotEmpty(t, part.SampleSHA256)
+
+		rows += part.Rows
+	}
+
+	require.Equal(t, 25_997, rows)
+
+	t.Run("Verify", func(t *testing.T) {
+		require.NoError(t, VerifyManifest(dir, VerifySampled, log.NoOp()))
+		require.NoError(t, VerifyManifest(dir, VerifyFull, log.NoOp()))
+	})
+
+	t.Run("NoManifest", func(t *testing.T) {
+		require.NoError(t, VerifyManifest(t.TempDir(), VerifyFull, log.NoOp()))
+	})
+}
+
+func TestVerifyManifest_Mismatch(t *testing.T) {
+	t.Run("Truncated", func(t *testing.T) {
+		dir := newTestDataset(t)
+		manifest, err := ReadManifest(dir)
+		require.NoError(t, err)
+
+		path := filepath.Join(dir, manifest.Partitions[1].File)
+		require.NoError(t, os.Truncate(path, manifest.Partitions[1].Size/2))
+
+		require.ErrorIs(t, VerifyManifest(dir, VerifySampled, log.NoOp()), ErrManifestMismatch)
+
+		_, _, err = OpenPartitions(dir, ReadOnlyPragmas(), VerifySampled, log.NoOp())
+		require.ErrorIs(t, err, ErrManifestMismatch)
+	})
+
+	t.Run("UnsupportedSchema", func(t *testing.T) {
+		dir := newTestDataset(t)
+		manifest, err := ReadManifest(dir)
+		require.NoError(t, err)
+
+		manifest.SchemaVersion = SchemaVersion + 1
+
+		data, err := json.Marshal(manifest)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ManifestFile), data, 0o644))
+
+		require.ErrorIs(t, VerifyManifest(dir, VerifySampled, log.NoOp()), ErrUnsupportedSchema)
+	})
+}
+
+func TestVerifyPartition_Sampled(t *testing.T) {
+	dir := t.TempDir()
+	b := block{from: 0, to: 99, id: "00"}
+
+	// larger than the sampled chunks combined, so that sampled hashing skips some of it
+	data := make([]byte, 2*sampleChunks*sampleChunkSize)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	path := filepath.Join(dir, "blk_00.db")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	part, err := describePartition(dir, b, 0)
+	require.NoError(t, err)
+	require.NotEqual(t, part.SHA256, part.SampleSHA256)
+
+	require.NoError(t, verifyPartition(dir, part, VerifySampled))
+	require.NoError(t, verifyPartition(dir, part, VerifyFull))
+
+	// flips a byte between the first and second sampled chunks, which only a full verification catches
+	data[sampleChunkSize+1] ^= 0xff
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	require.NoError(t, verifyPartition(dir, part, VerifySampled))
+	require.ErrorIs(t, verifyPartition(dir, part, VerifyFull), ErrManifestMismatch)
+
+	// while changes within a sampled chunk are caught by both
+	data[0] ^= 0xff
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	require.ErrorIs(t, verifyPartition(dir, part, VerifySampled), ErrManifestMismatch)
+}
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+const (
+	pgDriver = "pgx"
+
+	createPGTableQuery = `
+	CREATE TABLE IF NOT EXISTS primes (
+		prime BIGINT PRIMARY KEY NOT NULL
+	) PARTITION BY RANGE (prime);
+`
+
+	createPGScopesTableQuery = `
+	CREATE TABLE IF NOT EXISTS scopes (
+		id    TEXT PRIMARY KEY NOT NULL,
+		min   BIGINT           NOT NULL,
+		max   BIGINT           NOT NULL,
+		total BIGINT           NOT NULL
+	);
+`
+
+	// createPGRanksTableQuery holds a checkpoint every rankInterval primes, with its rank across the whole table, so
+	// that primes can be sampled by rank without scanning the table with an OFFSET clause.
+	createPGRanksTableQuery = `
+	CREATE TABLE IF NOT EXISTS ranks (
+		rank  BIGINT PRIMARY KEY NOT NULL,
+		prime BIGINT UNIQUE      NOT NULL
+	);
+`
+
+	truncatePGRanksQuery = `TRUNCATE TABLE ranks;`
+
+	insertPGRanksQuery = `
+INSERT INTO ranks (rank, prime)
+SELECT $1 + r.rn - 1, r.prime FROM (
+	SELECT prime, row_number() OVER (ORDER BY prime) AS rn FROM %s
+) AS r
+WHERE (r.rn - 1) %% $2 = 0;
+`
+
+	createPGPartitionQuery = `
+	CREATE TABLE IF NOT EXISTS primes_%s PARTITION OF primes
+		FOR VALUES FROM (%d) TO (%d);
+`
+
+	insertPGScopesQuery = `
+INSERT INTO scopes (id, min, max, total)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (id) DO UPDATE SET min = $2, max = $3, total = $4;
+`
+)
+
+var errIncompatibleDriver = errors.New("the underlying driver value is not the expected driver.Conn interface")
+
+// OpenPostgres opens a connection pool to the PostgreSQL database in uri, verifying that it is reachable.
+func OpenPostgres(uri string, logger *slog.Logger) (*sql.DB, error) {
+	db, err := sql.Open(pgDriver, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = db.PingContext(context.Background()); err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+
+	logger.Info("opened target DB", slog.String("driver", pgDriver))
+
+	db.SetMaxOpenConns(runtime.NumCPU())
+	db.SetMaxIdleConns(runtime.NumCPU())
+
+	return db, nil
+}
+
+// PartitionPostgres consumes the data in input, and loads it into a range-partitioned primes table in db, with a
+// partition for each blockSize range of values. Each partition is registered in a scopes table, in the same shape as
+// the index.db file of partitioned SQLite databases, and its rank checkpoints are written to a ranks table.
+func PartitionPostgres(ctx context.Context, db *sql.DB, blockSize int, input string, validation Validation, logger *slog.Logger) error {
+	start := time.Now()
+
+	data, err := readDataDir(ctx, input, validation, logger)
+	if err != nil {
+		return err
+	}
+
+	if err := partitionPostgresData(ctx, db, data, blockSize, logger); err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "operation completed", slog.Duration("time_elapsed", time.Since(start)))
+
+	return nil
+}
+
+func partitionPostgresData(ctx context.Context, db *sql.DB, data []int, blockSize int, logger *slog.Logger) error {
+	// ranks are numbered across all partitions, so they are all written again
+	for _, query := range []string{
+		createPGTableQuery, createPGScopesTableQuery, createPGRanksTableQuery, truncatePGRanksQuery,
+	} {
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	var rank int
+
+	blocks := prepareBlocks(data[len(data)-1], blockSize)
+	dataMap := mapBlocks(blocks, data)
+
+	for i := range blocks {
+		logger.InfoContext(ctx, "preparing block",
+			slog.String("id", blocks[i].id), slog.Int("from", blocks[i].from), slog.Int("to", blocks[i].to))
+
+		// partition bounds in PostgreSQL are inclusive on the lower bound and exclusive on the upper bound
+		if _, err := db.ExecContext(ctx,
+			fmt.Sprintf(createPGPartitionQuery, blocks[i].id, blocks[i].from, blocks[i].to+1),
+		); err != nil {
+			return err
+		}
+
+		table := "primes_" + blocks[i].id
+
+		if err := copyPrimes(ctx, db, table, dataMap[blocks[i]]); err != nil {
+			return err
+		}
+
+		if _, err := db.ExecContext(ctx,
+			fmt.Sprintf(insertPGRanksQuery, pgx.Identifier{table}.Sanitize()), rank, rankInterval,
+		); err != nil {
+			return err
+		}
+
+		rank += len(dataMap[blocks[i]])
+
+		logger.InfoContext(ctx, "adding scopes to index")
+
+		if _, err := db.ExecContext(ctx, insertPGScopesQuery,
+			blocks[i].id, blocks[i].from, blocks[i].to, len(dataMap[blocks[i]]),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyPrimes replaces the rows in table with data, truncating it first so that builds can be rerun against an existing
+// database. Both run in a transaction, so that a failed copy keeps the previous rows.
+func copyPrimes(ctx context.Context, db *sql.DB, table string, data []int) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("%w: %T", errIncompatibleDriver, driverConn)
+		}
+
+		tx, err := stdlibConn.Conn().Begin(ctx)
+		if err != nil {
+			return err
+		}
+
+		if _, err = tx.Exec(ctx, "TRUNCATE TABLE "+pgx.Identifier{table}.Sanitize()); err != nil {
+			return errors.Join(err, tx.Rollback(ctx))
+		}
+
+		if len(data) > 0 {
+			if _, err = tx.CopyFrom(ctx, pgx.Identifier{table}, []string{"prime"},
+				pgx.CopyFromSlice(len(data), func(i int) ([]any, error) {
+					return []any{int64(data[i])}, nil
+				}),
+			); err != nil {
+				return errors.Join(err, tx.Rollback(ctx))
+			}
+		}
+
+		return tx.Commit(ctx)
+	})
+}
@@ -0,0 +1,148 @@
+package database
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+const (
+	// ShardSize is the number of primes in each text file in the raw data directory.
+	ShardSize = 5_000_000
+
+	shardPrefix = "primes-"
+)
+
+// ShardWriter writes primes as newline-separated text files in the same layout as the raw data directory: files with
+// ShardSize lines each, suffixed as `split` would (primes-aa, primes-ab, ...).
+type ShardWriter struct {
+	dir  string
+	size int
+
+	// compress wraps each shard file with a compressor, if set, and ext is the extension of compressed shards
+	compress func(io.WriteCloser) (io.WriteCloser, error)
+	ext      string
+
+	shard int
+	lines int
+	file  *os.File
+	w     *bufio.Writer
+	buf   []byte
+
+	// sink is the compressor writing to the current shard file, if any
+	sink io.WriteCloser
+}
+
+// NewShardWriter creates a ShardWriter placing its files in dir, with size lines per file. If size is zero or lower,
+// ShardSize is used.
+func NewShardWriter(dir string, size int) (*ShardWriter, error) {
+	if size <= 0 {
+		size = ShardSize
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &ShardWriter{
+		dir:  dir,
+		size: size,
+		buf:  make([]byte, 0, 24),
+	}, nil
+}
+
+// Write appends the input values to the current shard, rotating files when a shard is full.
+func (s *ShardWriter) Write(values ...int64) error {
+	for i := range values {
+		if s.file == nil || s.lines >= s.size {
+			if err := s.rotate(); err != nil {
+				return err
+			}
+		}
+
+		s.buf = strconv.AppendInt(s.buf[:0], values[i], 10)
+		s.buf = append(s.buf, '\n')
+
+		if _, err := s.w.Write(s.buf); err != nil {
+			return err
+		}
+
+		s.lines++
+	}
+
+	return nil
+}
+
+// Close flushes and closes the current shard.
+func (s *ShardWriter) Close() error {
+	if s.file == nil {
+		return nil
+	}
+
+	if err := s.w.Flush(); err != nil {
+		_ = s.file.Close()
+
+		return err
+	}
+
+	var err error
+
+	// compressed shards are closed before their file, to write their footer
+	if s.sink != nil {
+		err = s.sink.Close()
+	}
+
+	err = errors.Join(err, s.file.Close())
+	s.file = nil
+
+	return err
+}
+
+func (s *ShardWriter) rotate() error {
+	if err := s.Close(); err != nil {
+		return err
+	}
+
+	name, err := shardName(s.shard)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(filepath.Join(s.dir, name+s.ext))
+	if err != nil {
+		return err
+	}
+
+	s.file = f
+	s.sink = nil
+	s.w = bufio.NewWriterSize(f, 1<<20)
+
+	if s.compress != nil {
+		if s.sink, err = s.compress(f); err != nil {
+			s.file = nil
+
+			return errors.Join(err, f.Close())
+		}
+
+		s.w.Reset(s.sink)
+	}
+
+	s.lines = 0
+	s.shard++
+
+	return nil
+}
+
+func shardName(n int) (string, error) {
+	const letters = 26
+
+	if n >= letters*letters {
+		return "", fmt.Errorf("too many shards: %d", n+1)
+	}
+
+	return shardPrefix + string([]byte{byte('a' + n/letters), byte('a' + n%letters)}), nil
+}
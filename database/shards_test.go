@@ -0,0 +1,48 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardWriter(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := NewShardWriter(dir, 3)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write(2, 3, 5, 7))
+	require.NoError(t, w.Write(11, 13, 17))
+	require.NoError(t, w.Close())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	for i, wants := range []string{"2\n3\n5\n", "7\n11\n13\n", "17\n"} {
+		name, err := shardName(i)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.Equal(t, wants, string(data))
+	}
+}
+
+func TestShardName(t *testing.T) {
+	for _, testcase := range []struct {
+		n     int
+		wants string
+	}{
+		{n: 0, wants: "primes-aa"},
+		{n: 25, wants: "primes-az"},
+		{n: 91, wants: "primes-dn"},
+	} {
+		name, err := shardName(testcase.n)
+		require.NoError(t, err)
+		require.Equal(t, testcase.wants, name)
+	}
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+// sieveSource is a valueSource over all primes up to limit, sieved one segment at a time as they are read, so that
+// blocks can be built from any range without holding all primes in memory.
+type sieveSource struct {
+	limit       int
+	base        []int64
+	segmentSize int
+}
+
+func newSieveSource(limit, segmentSize int) *sieveSource {
+	return &sieveSource{
+		limit:       limit,
+		base:        sieve.BasePrimes(sieve.BaseLimit(int64(limit))),
+		segmentSize: segmentSize,
+	}
+}
+
+func (s *sieveSource) From(ctx context.Context, from int) (valueReader, error) {
+	return &sieveValues{ctx: ctx, src: s, lo: max(from, 2)}, nil
+}
+
+// Last sieves the segments below limit, from the top, until one holds a prime.
+func (s *sieveSource) Last() (int, error) {
+	for hi := s.limit; hi >= 2; hi -= s.segmentSize {
+		if primes := sieve.Segment(int64(max(hi-s.segmentSize+1, 2)), int64(hi), s.base); len(primes) > 0 {
+			return int(primes[len(primes)-1]), nil
+		}
+	}
+
+	return 0, ErrEmptyInput
+}
+
+// sieveValues is a values sequence over a sieveSource, sieving the next segment once the current one is consumed.
+type sieveValues struct {
+	ctx context.Context
+	src *sieveSource
+	lo  int
+
+	primes []int64
+	idx    int
+}
+
+func (v *sieveValues) Peek() (int, bool, error) {
+	for v.idx >= len(v.primes) {
+		if v.lo > v.src.limit {
+			return 0, false, nil
+		}
+
+		if err := v.ctx.Err(); err != nil {
+			return 0, false, err
+		}
+
+		hi := min(v.lo+v.src.segmentSize-1, v.src.limit)
+
+		v.primes, v.idx = sieve.Segment(int64(v.lo), int64(hi), v.src.base), 0
+		v.lo = hi + 1
+	}
+
+	return int(v.primes[v.idx]), true, nil
+}
+
+func (v *sieveValues) Next() (int, bool, error) {
+	value, ok, err := v.Peek()
+	if err != nil || !ok {
+		return 0, ok, err
+	}
+
+	v.idx++
+
+	return value, true, nil
+}
+
+func (v *sieveValues) Close() error {
+	return nil
+}
+
+// PartitionSieve creates partitioned SQLite databases in dir from all primes up to limit, split as set by layout.
+// Primes are sieved per block as it is built, instead of being generated upfront.
+func PartitionSieve(ctx context.Context, limit int, layout Layout, workers int, dir string, logger *slog.Logger) error {
+	start := time.Now()
+
+	if err := partitionData(ctx, newSieveSource(limit, sieve.DefaultSegmentSize), layout, workers, dir, logger); err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "operation completed", slog.Duration("time_elapsed", time.Since(start)))
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+func TestSieveSource(t *testing.T) {
+	ctx := context.Background()
+	primes := sieve.BasePrimes(10_000)
+
+	data := make([]int, len(primes))
+	for i := range primes {
+		data[i] = int(primes[i])
+	}
+
+	// segments narrower than the gaps between some primes, so that reads cross empty segments
+	src := newSieveSource(10_000, 16)
+
+	last, err := src.Last()
+	require.NoError(t, err)
+	require.Equal(t, data[len(data)-1], last)
+
+	for _, from := range []int{0, 2, 100, 7_919, 9_974, 10_000} {
+		r, err := src.From(ctx, from)
+		require.NoError(t, err)
+
+		values, err := readAll(r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+
+		sr, err := sliceSource(data).From(ctx, from)
+		require.NoError(t, err)
+
+		wants, err := readAll(sr)
+		require.NoError(t, err)
+		require.Equal(t, wants, values)
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		_, err := newSieveSource(1, 16).Last()
+		require.ErrorIs(t, err, ErrEmptyInput)
+	})
+
+	t.Run("PartitionSieve", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, PartitionSieve(ctx, 300_000, WidthLayout(100_000), 2, dir, log.NoOp()))
+
+		manifest, err := ReadManifest(dir)
+		require.NoError(t, err)
+
+		wants, err := ReadManifest(newTestDataset(t))
+		require.NoError(t, err)
+
+		require.Equal(t, wants.Partitions, manifest.Partitions)
+	})
+}
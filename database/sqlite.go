@@ -37,6 +37,61 @@ const (
 	) STRICT;
 `
 
+	// rankInterval is the number of primes between rank checkpoints in a partition's ranks table
+	rankInterval = 1024
+
+	createRanksTableQuery = `
+	CREATE TABLE ranks (
+    rank  INTEGER PRIMARY KEY NOT NULL,
+    prime INTEGER             NOT NULL
+	) STRICT;
+`
+
+	createRankIntervalsTableQuery = `
+	CREATE TABLE rank_intervals (
+	  id       TEXT PRIMARY KEY NOT NULL,
+    interval INTEGER          NOT NULL
+	) STRICT;
+`
+
+	// histogramBuckets is the number of equal-width buckets each partition is split into in the index's histogram
+	histogramBuckets = 100
+
+	createBucketsTableQuery = `
+	CREATE TABLE buckets (
+	  id    TEXT    NOT NULL,
+    min   INTEGER NOT NULL,
+    max   INTEGER NOT NULL,
+    total INTEGER NOT NULL,
+    PRIMARY KEY (id, min)
+	) STRICT;
+`
+
+	// the layout table holds a single row with the partitioning strategy the dataset was built with
+	createLayoutTableQuery = `
+	CREATE TABLE layout (
+	  strategy   TEXT    NOT NULL,
+    block_size INTEGER NOT NULL
+	) STRICT;
+`
+
+	insertBucketQuery = `
+INSERT INTO buckets (id, min, max, total)
+VALUES (?, ?, ?, ?);
+`
+
+	insertRanksQuery = `
+INSERT INTO ranks (rank, prime)
+SELECT rank, prime FROM (
+	SELECT row_number() OVER (ORDER BY prime) - 1 AS rank, prime FROM primes
+) WHERE rank %% %d = 0;
+`
+
+	insertRankIntervalQuery = `
+INSERT INTO rank_intervals (id, interval)
+VALUES (?, ?);
+`
+
 	checkTableExists = `
 SELECT EXISTS(SELECT 1 FROM sqlite_master 
 	WHERE type='table' 
@@ -0,0 +1,169 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+const (
+	querySelectCheckpoints = `SELECT id, min, max, total FROM scopes;`
+	querySummarizeBlock    = `SELECT COUNT(*), COALESCE(MIN(prime), 0), COALESCE(MAX(prime), 0) FROM primes;`
+	queryCountRanks        = `SELECT COUNT(*) FROM ranks;`
+
+	deleteScopeQuery        = `DELETE FROM scopes WHERE id = ?;`
+	deleteRankIntervalQuery = `DELETE FROM rank_intervals WHERE id = ?;`
+	deleteBucketsQuery      = `DELETE FROM buckets WHERE id = ?;`
+)
+
+var (
+	ErrCheckpointMismatch = errors.New("existing partitions do not match the build")
+	errIncompleteBlock    = errors.New("incomplete block")
+)
+
+// checkpoint is a block registered in the index by a previous build.
+type checkpoint struct {
+	from  int
+	to    int
+	total int
+}
+
+// getCheckpoints returns the blocks registered in the index by a previous build in the same directory, if any, keyed by
+// their ID. It returns an error if any of them is not part of blocks, as the build would mix partitions of different
+// layouts, e.g. from a different block size.
+func getCheckpoints(ctx context.Context, db *sql.DB, blocks []block) (map[string]checkpoint, error) {
+	rows, err := db.QueryContext(ctx, querySelectCheckpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	checkpoints := make(map[string]checkpoint, len(blocks))
+
+	for rows.Next() {
+		var (
+			id string
+			cp checkpoint
+		)
+
+		if err = rows.Scan(&id, &cp.from, &cp.to, &cp.total); err != nil {
+			return nil, err
+		}
+
+		checkpoints[id] = cp
+	}
+
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ranges := make(map[string]block, len(blocks))
+	for i := range blocks {
+		ranges[blocks[i].id] = blocks[i]
+	}
+
+	for id, cp := range checkpoints {
+		b, ok := ranges[id]
+
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("%w: partition %s is not part of this build", ErrCheckpointMismatch, id)
+		case b.from != cp.from || b.to != cp.to:
+			return nil, fmt.Errorf("%w: partition %s covers [%d, %d], expected [%d, %d]",
+				ErrCheckpointMismatch, id, cp.from, cp.to, b.from, b.to)
+		}
+	}
+
+	return checkpoints, nil
+}
+
+// resumeBlock reports whether block b was completed by a previous build, by checking its partition file against its
+// checkpoint, returning the stats of the values in the file if so. Blocks that were not completed, or that fail the
+// check, have their files removed, to be built again; their checkpoints are left for the caller to unregister.
+func resumeBlock(
+	ctx context.Context, path string, b block, checkpoints map[string]checkpoint, logger *slog.Logger,
+) (blockStats, bool, error) {
+	if cp, ok := checkpoints[b.id]; ok {
+		stats, err := verifyBlock(ctx, path, b, cp)
+		if err == nil {
+			return stats, true, nil
+		}
+
+		logger.WarnContext(ctx, "rebuilding block", slog.String("id", b.id), slog.String("error", err.Error()))
+	}
+
+	return blockStats{}, false, removeBlock(path, b.id)
+}
+
+// verifyBlock checks that the partition file for block b holds the number of rows registered in its checkpoint, along
+// with its rank checkpoints.
+func verifyBlock(ctx context.Context, path string, b block, cp checkpoint) (blockStats, error) {
+	db, err := OpenPartition(path, b.id, ReadOnlyPragmas())
+	if err != nil {
+		return blockStats{}, err
+	}
+
+	defer db.Close()
+
+	var (
+		stats blockStats
+		ranks int
+	)
+
+	if err = db.QueryRowContext(ctx, querySummarizeBlock).Scan(&stats.rows, &stats.first, &stats.last); err != nil {
+		return blockStats{}, err
+	}
+
+	if stats.rows != cp.total {
+		return blockStats{}, fmt.Errorf("%w: holds %d primes, but the index registers %d",
+			errIncompleteBlock, stats.rows, cp.total)
+	}
+
+	if err = db.QueryRowContext(ctx, queryCountRanks).Scan(&ranks); err != nil {
+		return blockStats{}, err
+	}
+
+	if wants := (stats.rows + rankInterval - 1) / rankInterval; ranks != wants {
+		return blockStats{}, fmt.Errorf("%w: holds %d rank checkpoints, expected %d", errIncompleteBlock, ranks, wants)
+	}
+
+	return stats, nil
+}
+
+func unregisterBlock(ctx context.Context, db *sql.DB, id string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	for _, query := range []string{deleteScopeQuery, deleteRankIntervalQuery, deleteBucketsQuery} {
+		if _, err = tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// removeBlock removes the partition file for the block with the input id, along with its journal files, if any.
+func removeBlock(path, id string) error {
+	file := path + pathBlock + id + ".db"
+
+	for _, name := range []string{file, file + "-wal", file + "-shm", file + "-journal"} {
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+func TestPartitionData_Resume(t *testing.T) {
+	ctx := context.Background()
+
+	primes := sieve.BasePrimes(300_000)
+
+	data := make([]int, len(primes))
+	for i := range primes {
+		data[i] = int(primes[i])
+	}
+
+	t.Run("SkipsCompletedBlocks", func(t *testing.T) {
+		dir := newTestDataset(t)
+
+		// simulates a build interrupted while writing the last block, after the first one was completed
+		manifest, err := ReadManifest(dir)
+		require.NoError(t, err)
+
+		completed := manifest.Partitions[0]
+		partial := manifest.Partitions[2]
+
+		idx, err := OpenSQLite(filepath.Join(dir, "index.db"), ReadWritePragmas(), log.NoOp())
+		require.NoError(t, err)
+		require.NoError(t, unregisterBlock(ctx, idx, partial.ID))
+		require.NoError(t, unregisterBlock(ctx, idx, manifest.Partitions[1].ID))
+		require.NoError(t, idx.Close())
+
+		require.NoError(t, os.Truncate(filepath.Join(dir, partial.File), partial.Size/2))
+		require.NoError(t, os.Remove(filepath.Join(dir, ManifestFile)))
+
+		before, err := os.Stat(filepath.Join(dir, completed.File))
+		require.NoError(t, err)
+
+		require.NoError(t, PartitionData(ctx, data, WidthLayout(100_000), 2, dir, log.NoOp()))
+
+		after, err := os.Stat(filepath.Join(dir, completed.File))
+		require.NoError(t, err)
+		require.Equal(t, before.ModTime(), after.ModTime())
+
+		require.NoError(t, VerifyManifest(dir, VerifyFull, log.NoOp()))
+
+		resumed, err := ReadManifest(dir)
+		require.NoError(t, err)
+		require.Equal(t, manifest.Partitions, resumed.Partitions)
+	})
+
+	t.Run("RebuildsInvalidBlocks", func(t *testing.T) {
+		dir := newTestDataset(t)
+
+		manifest, err := ReadManifest(dir)
+		require.NoError(t, err)
+
+		// a registered block whose file was lost
+		require.NoError(t, os.Remove(filepath.Join(dir, manifest.Partitions[1].File)))
+
+		require.NoError(t, PartitionData(ctx, data, WidthLayout(100_000), 2, dir, log.NoOp()))
+		require.NoError(t, VerifyManifest(dir, VerifyFull, log.NoOp()))
+
+		db, parts, err := OpenPartitions(dir, ReadOnlyPragmas(), VerifySampled, log.NoOp())
+		require.NoError(t, err)
+
+		defer db.Close()
+
+		var count int
+		require.NoError(t, parts[manifest.Partitions[1].ID].QueryRow(`SELECT COUNT(*) FROM primes;`).Scan(&count))
+		require.Equal(t, manifest.Partitions[1].Rows, count)
+
+		for _, part := range parts {
+			require.NoError(t, part.Close())
+		}
+	})
+
+	t.Run("RebuildsStaleBlocksConcurrently", func(t *testing.T) {
+		dir := newTestDataset(t)
+
+		manifest, err := ReadManifest(dir)
+		require.NoError(t, err)
+
+		// every block is registered, but their files were lost or left incomplete
+		for _, part := range manifest.Partitions {
+			require.NoError(t, os.Truncate(filepath.Join(dir, part.File), 0))
+		}
+
+		require.NoError(t, PartitionData(ctx, data, WidthLayout(100_000), 4, dir, log.NoOp()))
+		require.NoError(t, VerifyManifest(dir, VerifyFull, log.NoOp()))
+
+		resumed, err := ReadManifest(dir)
+		require.NoError(t, err)
+		require.Equal(t, manifest.Partitions, resumed.Partitions)
+
+		// each block is registered once, in block order
+		idx, err := OpenSQLite(filepath.Join(dir, "index.db"), ReadOnlyPragmas(), log.NoOp())
+		require.NoError(t, err)
+
+		defer idx.Close()
+
+		rows, err := idx.Query(`SELECT id FROM scopes ORDER BY rowid;`)
+		require.NoError(t, err)
+
+		defer rows.Close()
+
+		ids := make([]string, 0, len(manifest.Partitions))
+
+		for rows.Next() {
+			var id string
+			require.NoError(t, rows.Scan(&id))
+
+			ids = append(ids, id)
+		}
+
+		require.NoError(t, rows.Err())
+		require.Len(t, ids, len(manifest.Partitions))
+
+		for i, part := range manifest.Partitions {
+			require.Equal(t, part.ID, ids[i])
+		}
+	})
+
+	t.Run("BlockSizeMismatch", func(t *testing.T) {
+		dir := newTestDataset(t)
+
+		require.ErrorIs(t, PartitionData(ctx, data, WidthLayout(50_000), 2, dir, log.NoOp()), ErrCheckpointMismatch)
+	})
+}
@@ -1,15 +1,12 @@
 package database
 
 import (
-	"bufio"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log/slog"
-	"os"
-	"path"
-	"strconv"
+	"math"
 	"strings"
 	"time"
 )
@@ -21,29 +18,45 @@ type migration struct {
 	create string
 }
 
-func MigrateSQLite(ctx context.Context, db *sql.DB, dir string, logger *slog.Logger) error {
+func MigrateSQLite(ctx context.Context, db *sql.DB, dir string, validation Validation, logger *slog.Logger) error {
 	start := time.Now()
 
-	if err := runMigrations(ctx, db,
-		migration{table: "primes", create: createTableQuery},
-	); err != nil {
-		return err
-	}
-
-	data, err := readDataDir(ctx, dir, logger)
+	data, err := openInput(ctx, dir, validation, logger)
 	if err != nil {
 		return err
 	}
 
-	if err := insertData(ctx, db, data, minBlockSize, logger); err != nil {
+	n, err := migrateValues(ctx, db, data, logger)
+	if err = errors.Join(err, data.Close()); err != nil {
 		return err
 	}
 
-	logger.InfoContext(ctx, "operation completed", slog.Duration("time_elapsed", time.Since(start)))
+	logger.InfoContext(ctx, "operation completed",
+		slog.Int("num_primes", n),
+		slog.Duration("time_elapsed", time.Since(start)),
+	)
 
 	return nil
 }
 
+// migrateValues creates the primes table in db, and inserts all values in src into it, returning their count.
+func migrateValues(ctx context.Context, db *sql.DB, src valueSource, logger *slog.Logger) (int, error) {
+	if err := runMigrations(ctx, db,
+		migration{table: "primes", create: createTableQuery},
+	); err != nil {
+		return 0, err
+	}
+
+	input, err := src.From(ctx, math.MinInt)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := insertData(ctx, db, input, minBlockSize, logger)
+
+	return n, errors.Join(err, input.Close())
+}
+
 func runMigrations(ctx context.Context, db *sql.DB, migrations ...migration) error {
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
@@ -85,115 +98,106 @@ func runMigrations(ctx context.Context, db *sql.DB, migrations ...migration) err
 	return tx.Commit()
 }
 
-func readDataDir(ctx context.Context, dir string, logger *slog.Logger) ([]int, error) {
-	entries, err := os.ReadDir(dir)
+// readDataDir reads all values in the build input at dir into memory, for the formats that are not built in a single
+// pass over the input.
+func readDataDir(ctx context.Context, dir string, validation Validation, logger *slog.Logger) ([]int, error) {
+	data, err := openInput(ctx, dir, validation, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	logger.InfoContext(ctx, "scanned directory", slog.Int("num_files", len(entries)))
-
-	total := make([]int, 0, len(entries)*maxAlloc)
-	errs := make([]error, 0, len(entries))
+	input, err := data.From(ctx, math.MinInt)
+	if err != nil {
+		return nil, errors.Join(err, data.Close())
+	}
 
-	for i := range entries {
-		logger.InfoContext(ctx, "extracting primes from file", slog.String("filename", entries[i].Name()))
+	total := make([]int, 0, maxAlloc)
 
-		data, extractErr := extract(ctx, logger, path.Join(dir, entries[i].Name()))
-		if extractErr != nil {
-			errs = append(errs, err)
+	for {
+		value, ok, err := input.Next()
+		if err != nil {
+			return nil, errors.Join(err, input.Close(), data.Close())
+		}
 
-			continue
+		if !ok {
+			break
 		}
 
-		total = append(total, data...)
+		total = append(total, value)
 	}
 
-	logger.InfoContext(ctx, "extracted primes from input file(s)",
-		slog.Int("num_primes", len(total)),
-		slog.Int("num_errors", len(errs)),
-	)
+	logger.InfoContext(ctx, "extracted primes from input file(s)", slog.Int("num_primes", len(total)))
 
-	if len(errs) > 0 {
-		return nil, errors.Join(errs...)
-	}
-
-	return total, nil
+	return total, errors.Join(input.Close(), data.Close())
 }
 
-func extract(ctx context.Context, logger *slog.Logger, path string) ([]int, error) {
-	file, err := os.Open(path)
+// insertData consumes the values in input, and inserts them in db in a single transaction, with batches of up to
+// batchSize values. It returns the number of inserted values.
+func insertData(ctx context.Context, db *sql.DB, input values, batchSize int, logger *slog.Logger) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	defer func() {
-		if closeErr := file.Close(); closeErr != nil {
-			logger.WarnContext(ctx, "error closing file",
-				slog.String("error", err.Error()),
-				slog.String("filename", file.Name()),
-			)
-		}
-	}()
-
-	scanner := bufio.NewScanner(file)
+	defer tx.Rollback()
 
-	values := make([]int, 0, maxAlloc)
-	errs := make([]error, 0, maxAlloc)
+	logger.InfoContext(ctx, "preparing transaction")
 
-	for scanner.Scan() {
-		value, convErr := strconv.Atoi(scanner.Text())
-		if convErr != nil {
-			errs = append(errs, convErr)
+	var (
+		total  int
+		offset int
+		batch  = make([]int, 0, batchSize)
+	)
 
-			continue
+	flush := func() error {
+		query, args := buildStatement(batch)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return err
 		}
 
-		values = append(values, value)
-	}
+		total += len(batch)
+		batch = batch[:0]
+		offset++
 
-	if len(errs) > 0 {
-		return nil, errors.Join(errs...)
-	}
+		if offset >= 10 {
+			offset = 0
 
-	return values, nil
-}
+			logger.InfoContext(ctx, "executing insert query", slog.Int("cur_index", total))
+		}
 
-func insertData(ctx context.Context, db *sql.DB, data []int, blockSize int, logger *slog.Logger) error {
-	tx, err := db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
+		return nil
 	}
 
-	defer tx.Rollback()
+	for {
+		value, ok, err := input.Next()
+		if err != nil {
+			return 0, err
+		}
 
-	logger.InfoContext(ctx, "preparing transaction", slog.Int("num_primes", len(data)))
+		if !ok {
+			break
+		}
 
-	var offset int
+		batch = append(batch, value)
 
-	for i := 0; i < len(data); i += blockSize {
-		to := i + blockSize - 1
-		if to > len(data) {
-			to = len(data)
+		if len(batch) < batchSize {
+			continue
 		}
 
-		query, args := buildStatement(data[i:to])
-		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
-			return err
+		if err = flush(); err != nil {
+			return 0, err
 		}
+	}
 
-		offset++
-
-		if offset >= 10 {
-			offset = 0
-
-			logger.InfoContext(ctx, "executing insert query", slog.Int("cur_index", i))
+	if len(batch) > 0 {
+		if err = flush(); err != nil {
+			return 0, err
 		}
 	}
 
-	logger.InfoContext(ctx, "committing transaction")
+	logger.InfoContext(ctx, "committing transaction", slog.Int("num_primes", total))
 
-	return tx.Commit()
+	return total, tx.Commit()
 }
 
 func buildStatement(data []int) (string, []any) {
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+)
+
+func TestInsertData(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := OpenSQLite(t.TempDir()+"/primes.db", ReadWritePragmas(), log.NoOp())
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	require.NoError(t, runMigrations(ctx, db, migration{table: "primes", create: createTableQuery}))
+
+	// spans a few full insert batches, and a partial one
+	data := make([]int, 3*minBlockSize+7)
+	for i := range data {
+		data[i] = i
+	}
+
+	n, err := insertData(ctx, db, &sliceValues{data: data}, minBlockSize, log.NoOp())
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+
+	var count, sum int
+
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*), SUM(prime) FROM primes;`).Scan(&count, &sum))
+	require.Equal(t, len(data), count)
+	require.Equal(t, len(data)*(len(data)-1)/2, sum)
+}
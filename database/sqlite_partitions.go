@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"runtime"
+	"sync"
 	"time"
 
 	"modernc.org/sqlite"
@@ -22,6 +26,9 @@ const (
 	queryPartitionIDs = `SELECT id FROM scopes;`
 
 	queryAttachDB = `ATTACH DATABASE "file:%s%s%s.db?mode=ro" AS db%s;`
+
+	// partitions are never written to once built, so they are opened as immutable to skip locking and journaling
+	partitionURIFormat = "file:%s%s%s.db?mode=ro&immutable=1"
 )
 
 type block struct {
@@ -30,9 +37,17 @@ type block struct {
 	id   string
 }
 
+// bucketWidth returns the value range covered by each bucket in the block's histogram, so that the block is split in
+// at most histogramBuckets buckets.
+func (b block) bucketWidth() int {
+	return max((b.to-b.from+histogramBuckets)/histogramBuckets, 1)
+}
+
 // AttachSQLite opens a connection to 'index.db' under dir, and queries for all IDs registered in it; which are used to
 // attach the database partitions under dir.
 //
+// If the dataset has a manifest, the partition files are verified against it before attaching them, as set by mode.
+//
 // Note that SQLite sets a maximum number of 10 attached databases by default, but can be modified as a compile-time
 // option, up to a maximum of 125 databases.
 //
@@ -43,9 +58,13 @@ type block struct {
 //
 // Then, it is possible to attach a hundred SQLite databases on the same index, making the partitions usable. The hard
 // limit is 125 databases: https://www.sqlite.org/limits.html#max_attached
-func AttachSQLite(dir string, pragmas map[string]string, logger *slog.Logger) (*sql.DB, error) {
+func AttachSQLite(dir string, pragmas map[string]string, mode Verification, logger *slog.Logger) (*sql.DB, error) {
 	ctx := context.Background()
 
+	if err := VerifyManifest(dir, mode, logger); err != nil {
+		return nil, err
+	}
+
 	db, err := OpenSQLite(dir+"/index.db", pragmas, logger)
 	if err != nil {
 		return nil, err
@@ -56,6 +75,14 @@ func AttachSQLite(dir string, pragmas map[string]string, logger *slog.Logger) (*
 		return nil, err
 	}
 
+	if err = verifyManifestIDs(dir, ids); err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+
+	if len(ids) > sqliteAttachHardLimit {
+		return nil, fmt.Errorf("number of partitions is over the SQLite limit for attaching databases (%d): len: %d", sqliteAttachHardLimit, len(ids))
+	}
+
 	if err := attachDBs(ctx, db, dir, ids); err != nil {
 		return nil, err
 	}
@@ -66,6 +93,82 @@ func AttachSQLite(dir string, pragmas map[string]string, logger *slog.Logger) (*
 	return db, nil
 }
 
+// OpenPartitions opens a connection to 'index.db' under dir, and a separate read-only connection pool for each
+// partition registered in it, keyed by partition ID.
+//
+// Unlike AttachSQLite, this works with the stock SQLite build, as there is no limit on the number of partitions. Like
+// AttachSQLite, the partition files are verified against the dataset's manifest, if any, as set by mode.
+func OpenPartitions(
+	dir string, pragmas map[string]string, mode Verification, logger *slog.Logger,
+) (*sql.DB, map[string]*sql.DB, error) {
+	ctx := context.Background()
+
+	if err := VerifyManifest(dir, mode, logger); err != nil {
+		return nil, nil, err
+	}
+
+	db, err := OpenIndex(dir, pragmas, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids, err := getIDs(ctx, db)
+	if err != nil {
+		return nil, nil, errors.Join(err, db.Close())
+	}
+
+	if err = verifyManifestIDs(dir, ids); err != nil {
+		return nil, nil, errors.Join(err, db.Close())
+	}
+
+	partitions := make(map[string]*sql.DB, len(ids))
+
+	for i := range ids {
+		part, err := OpenPartition(dir, ids[i], pragmas)
+		if err != nil {
+			for _, p := range partitions {
+				_ = p.Close()
+			}
+
+			return nil, nil, errors.Join(err, db.Close())
+		}
+
+		partitions[ids[i]] = part
+	}
+
+	logger.Info("opened partitions", slog.Int("num_partitions", len(partitions)))
+
+	return db, partitions, nil
+}
+
+// OpenIndex opens a connection to 'index.db' under dir, without opening or attaching any of its partitions.
+func OpenIndex(dir string, pragmas map[string]string, logger *slog.Logger) (*sql.DB, error) {
+	return OpenSQLite(dir+"/index.db", pragmas, logger)
+}
+
+// OpenPartition opens a read-only connection pool to the partition with the input id, under dir.
+func OpenPartition(dir, id string, pragmas map[string]string) (*sql.DB, error) {
+	path := dir + pathBlock + id + ".db"
+
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(sqlDriver, fmt.Sprintf(partitionURIFormat, dir, pathBlock, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = applyPragmas(context.Background(), db, pragmas); err != nil {
+		return nil, errors.Join(err, db.Close())
+	}
+
+	db.SetMaxOpenConns(runtime.NumCPU())
+	db.SetMaxIdleConns(1)
+
+	return db, nil
+}
+
 func attachDBs(ctx context.Context, db *sql.DB, dir string, ids []string) error {
 	conn, err := db.Conn(ctx)
 	if err != nil {
@@ -115,9 +218,9 @@ func getIDs(ctx context.Context, db *sql.DB) ([]string, error) {
 	return ids, nil
 }
 
-// Partition consumes the data in input, and creates partitioned SQLite databases in dir, with a given blockSize size.
+// Partition consumes the data in input, and creates partitioned SQLite databases in dir, split as set by layout.
 //
-// Different blockSize values result in different number of partitions. Note that SQLite has configured a maximum of 10
+// Different layouts result in different number of partitions. Note that SQLite has configured a maximum of 10
 // attached databases at a time by default. In order to have access to the maximum amount of 125 databases, SQLite must
 // be compiled with this maximum.
 //
@@ -128,15 +231,17 @@ func getIDs(ctx context.Context, db *sql.DB) ([]string, error) {
 //
 // Then, it is possible to attach a hundred SQLite databases on the same index, making the partitions usable. The hard
 // limit is 125 databases: https://www.sqlite.org/limits.html#max_attached
-func Partition(ctx context.Context, blockSize int, input, dir string, logger *slog.Logger) error {
+func Partition(
+	ctx context.Context, layout Layout, workers int, input, dir string, validation Validation, logger *slog.Logger,
+) error {
 	start := time.Now()
 
-	data, err := readDataDir(ctx, input, logger)
+	data, err := openInput(ctx, input, validation, logger)
 	if err != nil {
 		return err
 	}
 
-	if err := partitionData(ctx, data, blockSize, dir, logger); err != nil {
+	if err = errors.Join(partitionData(ctx, data, layout, workers, dir, logger), data.Close()); err != nil {
 		return err
 	}
 
@@ -145,7 +250,40 @@ func Partition(ctx context.Context, blockSize int, input, dir string, logger *sl
 	return nil
 }
 
-func partitionData(ctx context.Context, data []int, blockSize int, path string, logger *slog.Logger) error {
+// PartitionData creates partitioned SQLite databases in dir from the input data, split as set by layout. The input
+// data must be sorted in increasing order.
+func PartitionData(ctx context.Context, data []int, layout Layout, workers int, dir string, logger *slog.Logger) error {
+	start := time.Now()
+
+	if err := partitionData(ctx, sliceSource(data), layout, workers, dir, logger); err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "operation completed", slog.Duration("time_elapsed", time.Since(start)))
+
+	return nil
+}
+
+// blockResult is the outcome of building a block, or of skipping it if it was completed by a previous build. stale is
+// set for blocks registered by a previous build that were built again, to be unregistered first.
+type blockResult struct {
+	stats blockStats
+	built bool
+	stale bool
+	err   error
+}
+
+// partitionData builds a partition for each block of values in src under path, as set by layout, with up to workers
+// partition files being built concurrently, along with its index. Blocks completed by a previous build in path are
+// checked and skipped.
+func partitionData(
+	ctx context.Context, src valueSource, layout Layout, workers int, path string, logger *slog.Logger,
+) error {
+	maximum, err := src.Last()
+	if err != nil {
+		return err
+	}
+
 	idxDB, err := OpenSQLite(path+"/index.db", ReadWritePragmas(), logger)
 	if err != nil {
 		return err
@@ -153,51 +291,268 @@ func partitionData(ctx context.Context, data []int, blockSize int, path string,
 
 	if err = runMigrations(ctx, idxDB,
 		migration{table: "scopes", create: createScopesTableQuery},
+		migration{table: "rank_intervals", create: createRankIntervalsTableQuery},
+		migration{table: "buckets", create: createBucketsTableQuery},
+		migration{table: "layout", create: createLayoutTableQuery},
 	); err != nil {
-		return err
+		return errors.Join(err, idxDB.Close())
 	}
 
-	blocks := prepareBlocks(data[len(data)-1], blockSize)
+	blocks, err := layout.blocks(ctx, src, maximum, logger)
+	if err != nil {
+		return errors.Join(err, idxDB.Close())
+	}
+
+	if err = recordLayout(ctx, idxDB, layout); err != nil {
+		return errors.Join(err, idxDB.Close())
+	}
 
 	if len(blocks) > sqliteAttachHardLimit {
-		return fmt.Errorf("number of generated partitions is over the SQLite limit for attaching databases (%d): len: %d", sqliteAttachHardLimit, len(blocks))
+		logger.WarnContext(ctx, "number of partitions is over the SQLite limit for attaching databases, "+
+			"they can only be served as separate connection pools",
+			slog.Int("limit", sqliteAttachHardLimit), slog.Int("num_partitions", len(blocks)))
+	}
+
+	checkpoints, err := getCheckpoints(ctx, idxDB, blocks)
+	if err != nil {
+		return errors.Join(err, idxDB.Close())
+	}
+
+	// a manifest left by a previous build would describe the dataset as complete until this build finishes
+	if err = os.Remove(filepath.Join(path, ManifestFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Join(err, idxDB.Close())
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan blockResult, len(blocks))
+	for i := range results {
+		results[i] = make(chan blockResult, 1)
+	}
+
+	jobs := make(chan int)
+	wg := &sync.WaitGroup{}
+
+	for w := range max(workers, 1) {
+		wg.Add(1)
+
+		go func(logger *slog.Logger) {
+			defer wg.Done()
+
+			for i := range jobs {
+				results[i] <- partitionBlock(ctx, src, path, blocks[i], checkpoints, maximum, logger)
+			}
+		}(logger.With(slog.Int("worker", w)))
 	}
 
-	dataMap := mapBlocks(blocks, data)
+	go func() {
+		defer close(jobs)
+
+		for i := range blocks {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- i:
+			}
+		}
+	}()
+
+	counts := make([]int, len(blocks))
+	err = registerBlocks(ctx, idxDB, blocks, results, counts, logger)
+
+	// stops the remaining workers on failure
+	cancel()
+	wg.Wait()
+
+	if err = errors.Join(err, idxDB.Close()); err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "writing dataset manifest")
+
+	return writeManifest(path, blocks, counts, layout)
+}
 
+// registerBlocks waits for the result of each block, in order, and registers the built ones in the index, so that it
+// is written in the same order regardless of the number of workers, and only by the caller's goroutine. Stale
+// checkpoints are unregistered before their block is registered again. It sets the number of primes in each block in
+// counts.
+func registerBlocks(
+	ctx context.Context, idxDB *sql.DB, blocks []block, results []chan blockResult, counts []int, logger *slog.Logger,
+) error {
 	for i := range blocks {
-		uri := path + pathBlock + blocks[i].id + ".db"
-		logger.InfoContext(ctx, "preparing block",
-			slog.String("uri", uri), slog.Int("from", blocks[i].from), slog.Int("to", blocks[i].to))
+		var res blockResult
 
-		db, err := OpenSQLite(uri, ReadWritePragmas(), logger)
-		if err != nil {
-			return err
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case res = <-results[i]:
 		}
 
-		if err = runMigrations(ctx, db,
-			migration{table: "primes", create: createTableQuery},
-		); err != nil {
-			return err
+		if res.err != nil {
+			return res.err
 		}
 
-		if err = insertData(ctx, db, dataMap[blocks[i]], minBlockSize, logger); err != nil {
-			return err
+		counts[i] = res.stats.rows
+
+		if res.stale {
+			if err := unregisterBlock(ctx, idxDB, blocks[i].id); err != nil {
+				return err
+			}
 		}
 
-		if err = db.Close(); err != nil {
-			return err
+		if res.built {
+			logger.InfoContext(ctx, "adding scopes to index.db", slog.String("id", blocks[i].id))
+
+			if err := registerBlock(ctx, idxDB, blocks[i], res.stats); err != nil {
+				return err
+			}
 		}
 
-		logger.InfoContext(ctx, "adding scopes to index.db")
+		logger.InfoContext(ctx, "completed block",
+			slog.String("id", blocks[i].id),
+			slog.Int("num_completed", i+1),
+			slog.Int("num_blocks", len(blocks)),
+		)
+	}
+
+	return nil
+}
+
+// partitionBlock builds the partition file for block b from the values in src, or skips over them if the block was
+// completed by a previous build. It only writes to the block's partition file, leaving the index to registerBlocks.
+func partitionBlock(
+	ctx context.Context, src valueSource, path string, b block, checkpoints map[string]checkpoint, maximum int,
+	logger *slog.Logger,
+) blockResult {
+	start := time.Now()
+
+	built, done, err := resumeBlock(ctx, path, b, checkpoints, logger)
+	if err != nil {
+		return blockResult{err: err}
+	}
+
+	r, err := src.From(ctx, b.from)
+	if err != nil {
+		return blockResult{err: err}
+	}
+
+	input := newBlockValues(r, b)
+
+	if err = buildOrSkipBlock(ctx, path, b, input, built, done, logger); err != nil {
+		return blockResult{err: errors.Join(err, r.Close())}
+	}
+
+	// the last block must consume the rest of the input
+	if b.to == maximum {
+		_, trailing, err := r.Peek()
+		if err == nil && trailing {
+			err = fmt.Errorf("%w: input has values past %d", ErrValueOutOfRange, maximum)
+		}
+
+		if err != nil {
+			return blockResult{err: errors.Join(err, r.Close())}
+		}
+	}
+
+	if err = r.Close(); err != nil {
+		return blockResult{err: err}
+	}
+
+	if !done {
+		logger.InfoContext(ctx, "built block",
+			slog.String("id", b.id),
+			slog.Int("num_primes", input.stats.rows),
+			slog.Duration("time_elapsed", time.Since(start)),
+		)
+	}
+
+	_, registered := checkpoints[b.id]
+
+	return blockResult{stats: input.stats, built: !done, stale: registered && !done}
+}
+
+func buildOrSkipBlock(
+	ctx context.Context, path string, b block, input *blockValues, built blockStats, done bool, logger *slog.Logger,
+) error {
+	if !done {
+		return buildBlock(ctx, path, b, input, logger)
+	}
+
+	if err := input.drain(); err != nil {
+		return err
+	}
+
+	if input.stats.rows != built.rows || input.stats.first != built.first || input.stats.last != built.last {
+		return fmt.Errorf("%w: partition %s was built from a different input", ErrCheckpointMismatch, b.id)
+	}
+
+	logger.InfoContext(ctx, "skipping completed block",
+		slog.String("id", b.id), slog.Int("from", b.from), slog.Int("to", b.to))
+
+	return nil
+}
+
+// buildBlock writes the partition file for block b, with the values in input.
+func buildBlock(ctx context.Context, path string, b block, input values, logger *slog.Logger) error {
+	uri := path + pathBlock + b.id + ".db"
+	logger.InfoContext(ctx, "preparing block", slog.String("uri", uri), slog.Int("from", b.from), slog.Int("to", b.to))
+
+	db, err := OpenSQLite(uri, ReadWritePragmas(), logger)
+	if err != nil {
+		return err
+	}
+
+	if err = runMigrations(ctx, db,
+		migration{table: "primes", create: createTableQuery},
+		migration{table: "ranks", create: createRanksTableQuery},
+	); err != nil {
+		return errors.Join(err, db.Close())
+	}
+
+	if _, err = insertData(ctx, db, input, minBlockSize, logger); err != nil {
+		return errors.Join(err, db.Close())
+	}
+
+	if _, err = db.ExecContext(ctx, fmt.Sprintf(insertRanksQuery, rankInterval)); err != nil {
+		return errors.Join(err, db.Close())
+	}
+
+	return db.Close()
+}
+
+// registerBlock adds block b to the index, along with its rank interval and histogram, as recorded in stats while its
+// values were inserted. It is written in a single transaction, once the block's partition file is complete, so that a
+// block listed in scopes serves as a checkpoint for resumed builds.
+func registerBlock(ctx context.Context, db *sql.DB, b block, stats blockStats) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, insertScopesQuery, b.id, b.from, b.to, stats.rows); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, insertRankIntervalQuery, b.id, rankInterval); err != nil {
+		return err
+	}
+
+	width := b.bucketWidth()
+
+	for i := range stats.buckets {
+		from := b.from + i*width
+		to := min(from+width-1, b.to)
 
-		if _, err = idxDB.ExecContext(
-			ctx, insertScopesQuery, blocks[i].id, blocks[i].from, blocks[i].to, len(dataMap[blocks[i]])); err != nil {
+		if _, err = tx.ExecContext(ctx, insertBucketQuery, b.id, from, to, stats.buckets[i]); err != nil {
 			return err
 		}
 	}
 
-	return idxDB.Close()
+	return tx.Commit()
 }
 
 func mapBlocks(blocks []block, data []int) map[block][]int {
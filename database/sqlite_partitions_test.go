@@ -11,7 +11,7 @@ import (
 func TestBlocks(t *testing.T) {
 	logger := log.New("debug")
 
-	data, err := readDataDir(context.Background(), "testdata/raw", logger)
+	data, err := readDataDir(context.Background(), "testdata/raw", Validation{}, logger)
 	require.NoError(t, err)
 
 	blocks := prepareBlocks(data[len(data)-1], 50_000_000)
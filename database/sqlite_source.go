@@ -0,0 +1,326 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	querySelectRanges     = `SELECT id, min, max FROM scopes ORDER BY min;`
+	querySelectPrimesFrom = `SELECT prime FROM primes WHERE prime >= ? ORDER BY prime;`
+	querySelectLastPrime  = `SELECT MAX(prime) FROM primes;`
+)
+
+var (
+	ErrSameInputOutput = errors.New("output is the same as the input")
+	ErrOutputExists    = errors.New("output already exists")
+)
+
+// sourceDB is a database in an SQLite dataset, holding the primes within [from, to].
+type sourceDB struct {
+	db   *sql.DB
+	from int
+	to   int
+}
+
+// sqliteSource is a valueSource over the primes tables in an SQLite dataset: either a single database, as written by
+// MigrateSQLite, or the partitions of a partitioned dataset, in order.
+type sqliteSource struct {
+	index *sql.DB
+	dbs   []sourceDB
+}
+
+// openSQLiteSource opens the SQLite dataset at input, which is either a single database file, or a partitioned dataset's
+// directory. Partitioned datasets are verified against their manifest, if any, like when serving them.
+func openSQLiteSource(ctx context.Context, input string, logger *slog.Logger) (*sqliteSource, error) {
+	stat, err := os.Stat(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if !stat.IsDir() {
+		db, err := OpenSQLite(input, ReadOnlyPragmas(), logger)
+		if err != nil {
+			return nil, err
+		}
+
+		return &sqliteSource{dbs: []sourceDB{{db: db, from: math.MinInt, to: math.MaxInt}}}, nil
+	}
+
+	index, partitions, err := OpenPartitions(input, ReadOnlyPragmas(), VerifySampled, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	src := &sqliteSource{index: index, dbs: make([]sourceDB, 0, len(partitions))}
+
+	rows, err := index.QueryContext(ctx, querySelectRanges)
+	if err != nil {
+		return nil, errors.Join(err, closePartitions(partitions), src.Close())
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id string
+			s  sourceDB
+		)
+
+		if err = rows.Scan(&id, &s.from, &s.to); err != nil {
+			return nil, errors.Join(err, closePartitions(partitions), src.Close())
+		}
+
+		s.db = partitions[id]
+		delete(partitions, id)
+
+		src.dbs = append(src.dbs, s)
+	}
+
+	if err = errors.Join(rows.Close(), rows.Err()); err != nil {
+		return nil, errors.Join(err, closePartitions(partitions), src.Close())
+	}
+
+	return src, nil
+}
+
+func closePartitions(partitions map[string]*sql.DB) error {
+	errs := make([]error, 0, len(partitions))
+
+	for _, db := range partitions {
+		errs = append(errs, db.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *sqliteSource) From(ctx context.Context, from int) (valueReader, error) {
+	var start int
+
+	for start < len(s.dbs) && s.dbs[start].to < from {
+		start++
+	}
+
+	return &sqliteValues{ctx: ctx, dbs: s.dbs[start:], from: from}, nil
+}
+
+// Last returns the greatest prime in the dataset, from the last database holding any.
+func (s *sqliteSource) Last() (int, error) {
+	for i := len(s.dbs) - 1; i >= 0; i-- {
+		var last sql.NullInt64
+
+		if err := s.dbs[i].db.QueryRow(querySelectLastPrime).Scan(&last); err != nil {
+			return 0, err
+		}
+
+		if last.Valid {
+			return int(last.Int64), nil
+		}
+	}
+
+	return 0, ErrEmptyInput
+}
+
+func (s *sqliteSource) Close() error {
+	errs := make([]error, 0, len(s.dbs)+1)
+
+	for i := range s.dbs {
+		errs = append(errs, s.dbs[i].db.Close())
+	}
+
+	if s.index != nil {
+		errs = append(errs, s.index.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// sqliteValues is a values sequence over the primes in the databases of an sqliteSource, queried one database at a
+// time. It returns an error if the values are not strictly increasing across databases.
+type sqliteValues struct {
+	ctx  context.Context
+	dbs  []sourceDB
+	from int
+
+	rows *sql.Rows
+
+	prev    int
+	started bool
+
+	peeked    int
+	hasPeeked bool
+}
+
+func (v *sqliteValues) Peek() (int, bool, error) {
+	if v.hasPeeked {
+		return v.peeked, true, nil
+	}
+
+	value, ok, err := v.read()
+	if err != nil || !ok {
+		return 0, ok, err
+	}
+
+	v.peeked, v.hasPeeked = value, true
+
+	return value, true, nil
+}
+
+func (v *sqliteValues) Next() (int, bool, error) {
+	if v.hasPeeked {
+		v.hasPeeked = false
+
+		return v.peeked, true, nil
+	}
+
+	return v.read()
+}
+
+func (v *sqliteValues) read() (int, bool, error) {
+	for {
+		if v.rows == nil {
+			if len(v.dbs) == 0 {
+				return 0, false, nil
+			}
+
+			rows, err := v.dbs[0].db.QueryContext(v.ctx, querySelectPrimesFrom, v.from)
+			if err != nil {
+				return 0, false, err
+			}
+
+			v.rows = rows
+			v.dbs = v.dbs[1:]
+		}
+
+		if !v.rows.Next() {
+			err := errors.Join(v.rows.Err(), v.rows.Close())
+
+			v.rows = nil
+
+			if err != nil {
+				return 0, false, err
+			}
+
+			continue
+		}
+
+		var value int
+
+		if err := v.rows.Scan(&value); err != nil {
+			return 0, false, err
+		}
+
+		if v.started && value <= v.prev {
+			return 0, false, fmt.Errorf("%w: %d follows %d", ErrUnsortedInput, value, v.prev)
+		}
+
+		v.prev, v.started = value, true
+
+		return value, true, nil
+	}
+}
+
+// Close closes the rows being read, if any.
+func (v *sqliteValues) Close() error {
+	if v.rows == nil {
+		return nil
+	}
+
+	err := v.rows.Close()
+	v.rows = nil
+
+	return err
+}
+
+// Repartition reads the primes in the SQLite dataset at input, either a single database file as written by
+// MigrateSQLite, or a partitioned dataset's directory, and creates partitioned SQLite databases from them in dir, split
+// as set by layout. This converts a single database into a partitioned dataset, or changes the layout of an existing
+// one, without going through the raw text files.
+//
+// dir must not be the input's directory; like Partition, an interrupted repartition is resumed by running it again.
+func Repartition(ctx context.Context, input string, layout Layout, workers int, dir string, logger *slog.Logger) error {
+	start := time.Now()
+
+	if err := checkDistinct(input, dir); err != nil {
+		return err
+	}
+
+	src, err := openSQLiteSource(ctx, input, logger)
+	if err != nil {
+		return err
+	}
+
+	if err = errors.Join(partitionData(ctx, src, layout, workers, dir, logger), src.Close()); err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "operation completed", slog.Duration("time_elapsed", time.Since(start)))
+
+	return nil
+}
+
+// Merge reads the primes in the SQLite dataset at input, either a single database file or a partitioned dataset's
+// directory, and writes them to a new single database file at output, as written by MigrateSQLite.
+func Merge(ctx context.Context, input, output string, logger *slog.Logger) error {
+	start := time.Now()
+
+	if err := checkDistinct(input, output); err != nil {
+		return err
+	}
+
+	// unlike partitioned builds, a single database cannot be resumed, so it is never written over
+	switch _, err := os.Stat(output); {
+	case err == nil:
+		return fmt.Errorf("%w: %s", ErrOutputExists, output)
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+
+	src, err := openSQLiteSource(ctx, input, logger)
+	if err != nil {
+		return err
+	}
+
+	db, err := OpenSQLite(output, ReadWritePragmas(), logger)
+	if err != nil {
+		return errors.Join(err, src.Close())
+	}
+
+	n, err := migrateValues(ctx, db, src, logger)
+	if err = errors.Join(err, db.Close(), src.Close()); err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "operation completed",
+		slog.Int("num_primes", n),
+		slog.Duration("time_elapsed", time.Since(start)),
+	)
+
+	return nil
+}
+
+// checkDistinct returns an error if input and output point to the same path, or if output is within input.
+func checkDistinct(input, output string) error {
+	in, err := filepath.Abs(input)
+	if err != nil {
+		return err
+	}
+
+	out, err := filepath.Abs(output)
+	if err != nil {
+		return err
+	}
+
+	if rel, err := filepath.Rel(in, out); in == out || (err == nil && filepath.IsLocal(rel)) {
+		return fmt.Errorf("%w: %s", ErrSameInputOutput, output)
+	}
+
+	return nil
+}
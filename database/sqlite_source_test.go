@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+func TestRepartition(t *testing.T) {
+	ctx := context.Background()
+	primes := sieve.BasePrimes(300_000)
+
+	data := make([]int, len(primes))
+	for i := range primes {
+		data[i] = int(primes[i])
+	}
+
+	// a single database, as written by MigrateSQLite
+	single := filepath.Join(t.TempDir(), "primes.db")
+
+	db, err := OpenSQLite(single, ReadWritePragmas(), log.NoOp())
+	require.NoError(t, err)
+	require.NoError(t, MigrateSQLite(ctx, db, writeRawDir(t, 7_000, primes...), Validation{}, log.NoOp()))
+	require.NoError(t, db.Close())
+
+	t.Run("SingleToPartitioned", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, Repartition(ctx, single, WidthLayout(100_000), 2, dir, log.NoOp()))
+
+		// matches the dataset built from the same primes in memory
+		manifest, err := ReadManifest(dir)
+		require.NoError(t, err)
+
+		wants, err := ReadManifest(newTestDataset(t))
+		require.NoError(t, err)
+
+		require.Equal(t, wants.Partitions, manifest.Partitions)
+	})
+
+	t.Run("ChangeStrategy", func(t *testing.T) {
+		dir := t.TempDir()
+		layout := Layout{Strategy: StrategyCount, BlockSize: 10_000}
+
+		require.NoError(t, Repartition(ctx, newTestDataset(t), layout, 2, dir, log.NoOp()))
+
+		manifest, err := ReadManifest(dir)
+		require.NoError(t, err)
+
+		wants := t.TempDir()
+		require.NoError(t, PartitionData(ctx, data, layout, 1, wants, log.NoOp()))
+
+		wantsManifest, err := ReadManifest(wants)
+		require.NoError(t, err)
+
+		require.Equal(t, wantsManifest.Partitions, manifest.Partitions)
+	})
+
+	t.Run("PartitionedToSingle", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "primes.db")
+		require.NoError(t, Merge(ctx, newTestDataset(t), output, log.NoOp()))
+
+		db, err := OpenSQLite(output, ReadOnlyPragmas(), log.NoOp())
+		require.NoError(t, err)
+
+		defer db.Close()
+
+		src := &sqliteSource{dbs: []sourceDB{{db: db, from: 0, to: data[len(data)-1]}}}
+
+		r, err := src.From(ctx, 0)
+		require.NoError(t, err)
+
+		values, err := readAll(r)
+		require.NoError(t, err)
+		require.Equal(t, data, values)
+
+		t.Run("OutputExists", func(t *testing.T) {
+			require.ErrorIs(t, Merge(ctx, single, output, log.NoOp()), ErrOutputExists)
+		})
+	})
+
+	t.Run("SameInputOutput", func(t *testing.T) {
+		dir := newTestDataset(t)
+
+		require.ErrorIs(t, Repartition(ctx, dir, WidthLayout(50_000), 1, dir, log.NoOp()), ErrSameInputOutput)
+		require.ErrorIs(t, Repartition(ctx, dir, WidthLayout(50_000), 1, filepath.Join(dir, "parts"), log.NoOp()),
+			ErrSameInputOutput)
+		require.ErrorIs(t, Merge(ctx, single, single, log.NoOp()), ErrSameInputOutput)
+	})
+}
+
+func TestSQLiteSource_From(t *testing.T) {
+	ctx := context.Background()
+
+	src, err := openSQLiteSource(ctx, newTestDataset(t), log.NoOp())
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, src.Close())
+	}()
+
+	require.Len(t, src.dbs, 3)
+
+	last, err := src.Last()
+	require.NoError(t, err)
+	require.Equal(t, 299_993, last)
+
+	// reads across partitions, starting within the second one
+	r, err := src.From(ctx, 199_990)
+	require.NoError(t, err)
+
+	values, err := readAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+
+	require.Equal(t, 199_999, values[0])
+	require.Equal(t, 200_003, values[1])
+	require.Equal(t, last, values[len(values)-1])
+}
@@ -0,0 +1,493 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+)
+
+var (
+	ErrEmptyInput      = errors.New("input has no values")
+	ErrValueOutOfRange = errors.New("input value is out of range")
+)
+
+// values is a sequence of primes in increasing order, consumed one at a time.
+type values interface {
+	// Next returns the next value in the sequence, or false once it is exhausted.
+	Next() (int, bool, error)
+}
+
+// peekValues is a values sequence that can also return its next value without consuming it.
+type peekValues interface {
+	values
+	// Peek returns the next value in the sequence without consuming it, or false once it is exhausted.
+	Peek() (int, bool, error)
+}
+
+// valueReader is a peekValues sequence over an input that must be closed once consumed.
+type valueReader interface {
+	peekValues
+	Close() error
+}
+
+// valueSource is a sorted input, which can be read from any value, so that blocks can be built from it concurrently.
+type valueSource interface {
+	// From returns a sequence over the values in the input, starting at the first value greater than or equal to from.
+	From(ctx context.Context, from int) (valueReader, error)
+	// Last returns the last value in the input, which is its maximum.
+	Last() (int, error)
+}
+
+// sliceSource is a valueSource over an in-memory slice.
+type sliceSource []int
+
+func (s sliceSource) From(_ context.Context, from int) (valueReader, error) {
+	return &sliceValues{data: s, idx: sort.SearchInts(s, from)}, nil
+}
+
+func (s sliceSource) Last() (int, error) {
+	if len(s) == 0 {
+		return 0, ErrEmptyInput
+	}
+
+	return s[len(s)-1], nil
+}
+
+// sliceValues is a values sequence over an in-memory slice.
+type sliceValues struct {
+	data []int
+	idx  int
+}
+
+func (v *sliceValues) Peek() (int, bool, error) {
+	if v.idx >= len(v.data) {
+		return 0, false, nil
+	}
+
+	return v.data[v.idx], true, nil
+}
+
+func (v *sliceValues) Next() (int, bool, error) {
+	if v.idx >= len(v.data) {
+		return 0, false, nil
+	}
+
+	v.idx++
+
+	return v.data[v.idx-1], true, nil
+}
+
+func (v *sliceValues) Close() error {
+	return nil
+}
+
+// rawInput is a valueSource over the shards in a build input, holding a value per line, in increasing order across
+// shards sorted by name.
+type rawInput struct {
+	logger     *slog.Logger
+	validation Validation
+
+	shards []shard
+
+	// firsts holds the first valid value in each shard, or math.MaxInt if it has none, to find where to start reading
+	// from a given value
+	firsts []int
+	// prevs holds the last valid value before each shard, or math.MinInt if there is none, so that readers starting at a
+	// shard check its values the same way as the validation did
+	prevs []int
+	// last is the last valid value in the input, which is its maximum
+	last    int
+	hasLast bool
+
+	// temps are the paths to temporary copies of the input, if any, removed once the input is closed
+	temps []string
+}
+
+// add appends the shards in the file at path to the input.
+func (d *rawInput) add(ctx context.Context, name, path string) error {
+	shards, temp, err := expandFile(name, path)
+	if err != nil {
+		return err
+	}
+
+	if temp != "" {
+		d.temps = append(d.temps, temp)
+
+		d.logger.InfoContext(ctx, "extracted archive",
+			slog.String("filename", name),
+			slog.String("path", temp),
+			slog.Int("num_files", len(shards)),
+		)
+	}
+
+	d.shards = append(d.shards, shards...)
+
+	return nil
+}
+
+// validate reads through the input once, checking each line as set by the input's Validation, and recording the first
+// valid value in each shard and the last one in the input. It logs a summary of the checks, writes the report if set,
+// and returns an error for the first invalid line if the validation is strict.
+//
+// Invalid lines are skipped the same way when reading from any value later on, so that blocks built concurrently hold
+// the same values as this single pass accepted.
+func (d *rawInput) validate(ctx context.Context) error {
+	report := &ValidationReport{Lenient: d.validation.Lenient, Files: len(d.shards)}
+
+	r := &dataReader{
+		ctx:        ctx,
+		logger:     d.logger,
+		validation: d.validation,
+		report:     report,
+		shards:     d.shards,
+	}
+
+	d.firsts = make([]int, len(d.shards))
+	d.prevs = make([]int, len(d.shards))
+	lasts := make([]int, len(d.shards))
+
+	for i := range d.firsts {
+		d.firsts[i] = math.MaxInt
+	}
+
+	for {
+		value, ok, err := r.Next()
+		if err != nil {
+			return errors.Join(err, r.Close())
+		}
+
+		if !ok {
+			break
+		}
+
+		if i := r.idx - 1; d.firsts[i] == math.MaxInt {
+			d.firsts[i] = value
+		}
+
+		lasts[r.idx-1] = value
+		d.last, d.hasLast = value, true
+		report.Values++
+	}
+
+	if err := r.Close(); err != nil {
+		return err
+	}
+
+	for i, prev := 0, math.MinInt; i < len(d.prevs); i++ {
+		d.prevs[i] = prev
+
+		if d.firsts[i] != math.MaxInt {
+			prev = lasts[i]
+		}
+	}
+
+	report.OK = report.NumIssues == 0
+
+	d.logger.InfoContext(ctx, "validated input",
+		slog.Bool("ok", report.OK),
+		slog.Bool("lenient", report.Lenient),
+		slog.Int("num_files", report.Files),
+		slog.Int("num_lines", report.Lines),
+		slog.Int("num_values", report.Values),
+		slog.Int("num_issues", report.NumIssues),
+	)
+
+	if d.validation.Report != "" {
+		if err := report.write(d.validation.Report); err != nil {
+			return err
+		}
+	}
+
+	if report.OK || d.validation.Lenient {
+		return nil
+	}
+
+	return fmt.Errorf("%w (invalid lines: %d)", report.first, report.NumIssues)
+}
+
+func (d *rawInput) From(ctx context.Context, from int) (valueReader, error) {
+	var start int
+
+	for i := range d.firsts {
+		if d.firsts[i] <= from {
+			start = i
+		}
+	}
+
+	r := &dataReader{
+		ctx:        ctx,
+		logger:     d.logger,
+		validation: d.validation,
+		shards:     d.shards,
+		idx:        start,
+	}
+
+	if start < len(d.prevs) && d.prevs[start] != math.MinInt {
+		r.prev, r.started = d.prevs[start], true
+	}
+
+	for {
+		value, ok, err := r.Peek()
+		if err != nil {
+			return nil, errors.Join(err, r.Close())
+		}
+
+		if !ok || value >= from {
+			return r, nil
+		}
+
+		if _, _, err = r.Next(); err != nil {
+			return nil, errors.Join(err, r.Close())
+		}
+	}
+}
+
+// Last returns the last valid value in the input, as found when validating it. As the input is sorted, this is its
+// maximum, which sets the layout of the blocks before they are built.
+func (d *rawInput) Last() (int, error) {
+	if !d.hasLast {
+		return 0, ErrEmptyInput
+	}
+
+	return d.last, nil
+}
+
+// Close removes the temporary copies of the input, if any.
+func (d *rawInput) Close() error {
+	errs := make([]error, 0, len(d.temps))
+
+	for i := range d.temps {
+		errs = append(errs, os.RemoveAll(d.temps[i]))
+	}
+
+	return errors.Join(errs...)
+}
+
+// dataReader is a values sequence over the shards in a rawInput, read one line at a time, so that memory does not grow
+// with the size of the input. Lines are checked as set by its Validation, as blocks are built in a single pass over
+// the input: invalid lines return an error, or are skipped if the validation is lenient.
+type dataReader struct {
+	ctx        context.Context
+	logger     *slog.Logger
+	validation Validation
+
+	// report records the invalid lines, if set, which are then skipped regardless of the validation
+	report *ValidationReport
+
+	shards []shard
+	idx    int
+
+	file    io.ReadCloser
+	scanner *bufio.Scanner
+	line    int
+
+	prev    int
+	started bool
+
+	// peeked holds a value read ahead by Peek, to be returned by the following call to Next
+	peeked    int
+	hasPeeked bool
+}
+
+func (r *dataReader) Peek() (int, bool, error) {
+	if r.hasPeeked {
+		return r.peeked, true, nil
+	}
+
+	value, ok, err := r.read()
+	if err != nil || !ok {
+		return 0, ok, err
+	}
+
+	r.peeked, r.hasPeeked = value, true
+
+	return value, true, nil
+}
+
+func (r *dataReader) Next() (int, bool, error) {
+	if r.hasPeeked {
+		r.hasPeeked = false
+
+		return r.peeked, true, nil
+	}
+
+	return r.read()
+}
+
+func (r *dataReader) read() (int, bool, error) {
+	for {
+		if r.scanner == nil {
+			if r.idx >= len(r.shards) {
+				return 0, false, nil
+			}
+
+			if err := r.open(); err != nil {
+				return 0, false, err
+			}
+		}
+
+		if !r.scanner.Scan() {
+			if err := r.closeFile(); err != nil {
+				return 0, false, err
+			}
+
+			continue
+		}
+
+		r.line++
+
+		if r.report != nil {
+			r.report.Lines++
+		}
+
+		value, err := strconv.Atoi(r.scanner.Text())
+		if err != nil {
+			if err = r.invalid(IssueMalformed, err); err != nil {
+				return 0, false, err
+			}
+
+			continue
+		}
+
+		if kind, err := r.validation.check(value, r.prev, r.started); err != nil {
+			if err = r.invalid(kind, err); err != nil {
+				return 0, false, err
+			}
+
+			continue
+		}
+
+		r.prev, r.started = value, true
+
+		return value, true, nil
+	}
+}
+
+// invalid handles an invalid line of the given kind, returning its error with the line's position, unless it is
+// skipped.
+func (r *dataReader) invalid(kind string, err error) error {
+	name := r.shards[r.idx-1].name
+
+	if r.report != nil {
+		r.report.add(name, r.line, kind, fmt.Errorf("%s:%d: %w", name, r.line, err))
+
+		return nil
+	}
+
+	if r.validation.Lenient {
+		return nil
+	}
+
+	return fmt.Errorf("%s:%d: %w", name, r.line, err)
+}
+
+func (r *dataReader) open() error {
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
+
+	s := r.shards[r.idx]
+	r.idx++
+
+	r.logger.InfoContext(r.ctx, "extracting primes from file", slog.String("filename", s.name))
+
+	f, err := s.open()
+	if err != nil {
+		return err
+	}
+
+	r.file = f
+	r.scanner = bufio.NewScanner(f)
+	r.line = 0
+
+	return nil
+}
+
+func (r *dataReader) closeFile() error {
+	err := r.scanner.Err()
+
+	r.scanner = nil
+
+	return errors.Join(err, r.file.Close())
+}
+
+// Close closes the file being read, if any.
+func (r *dataReader) Close() error {
+	if r.scanner == nil {
+		return nil
+	}
+
+	return r.closeFile()
+}
+
+// blockStats summarizes the values consumed for a block.
+type blockStats struct {
+	rows  int
+	first int
+	last  int
+
+	// buckets holds the number of values in each histogram bucket of the block
+	buckets []int
+}
+
+// blockValues is a values sequence over the values in input within block b's range, recording their stats as they are
+// consumed.
+type blockValues struct {
+	input peekValues
+	b     block
+	width int
+	stats blockStats
+}
+
+func newBlockValues(input peekValues, b block) *blockValues {
+	width := b.bucketWidth()
+
+	return &blockValues{
+		input: input,
+		b:     b,
+		width: width,
+		stats: blockStats{buckets: make([]int, (b.to-b.from)/width+1)},
+	}
+}
+
+func (v *blockValues) Next() (int, bool, error) {
+	value, ok, err := v.input.Peek()
+	if err != nil || !ok || value > v.b.to {
+		return 0, false, err
+	}
+
+	if value < v.b.from {
+		return 0, false, fmt.Errorf("%w: %d is below the block's minimum %d", ErrValueOutOfRange, value, v.b.from)
+	}
+
+	if _, _, err = v.input.Next(); err != nil {
+		return 0, false, err
+	}
+
+	if v.stats.rows == 0 {
+		v.stats.first = value
+	}
+
+	v.stats.rows++
+	v.stats.last = value
+	v.stats.buckets[(value-v.b.from)/v.width]++
+
+	return value, true, nil
+}
+
+// drain consumes the remaining values in the block, to skip over it.
+func (v *blockValues) drain() error {
+	for {
+		_, ok, err := v.Next()
+		if err != nil || !ok {
+			return err
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+func writeRawDir(t *testing.T, shardSize int, values ...int64) string {
+	dir := t.TempDir()
+
+	w, err := NewShardWriter(dir, shardSize)
+	require.NoError(t, err)
+	require.NoError(t, w.Write(values...))
+	require.NoError(t, w.Close())
+
+	return dir
+}
+
+func readAll(input values) ([]int, error) {
+	data := make([]int, 0, 16)
+
+	for {
+		value, ok, err := input.Next()
+		if err != nil || !ok {
+			return data, err
+		}
+
+		data = append(data, value)
+	}
+}
+
+func TestDataReader(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		dir := writeRawDir(t, 3, 2, 3, 5, 7, 11, 13, 17)
+
+		d, err := openInput(ctx, dir, Validation{}, log.NoOp())
+		require.NoError(t, err)
+
+		last, err := d.Last()
+		require.NoError(t, err)
+		require.Equal(t, 17, last)
+
+		r, err := d.From(ctx, 0)
+		require.NoError(t, err)
+
+		value, ok, err := r.Peek()
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, 2, value)
+
+		data, err := readAll(r)
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 3, 5, 7, 11, 13, 17}, data)
+		require.NoError(t, r.Close())
+	})
+
+	t.Run("From", func(t *testing.T) {
+		d, err := openInput(ctx, writeRawDir(t, 3, 2, 3, 5, 7, 11, 13, 17), Validation{}, log.NoOp())
+		require.NoError(t, err)
+
+		for _, testcase := range []struct {
+			from  int
+			wants []int
+		}{
+			{from: 6, wants: []int{7, 11, 13, 17}},
+			{from: 11, wants: []int{11, 13, 17}},
+			{from: 12, wants: []int{13, 17}},
+			{from: 18, wants: []int{}},
+		} {
+			r, err := d.From(ctx, testcase.from)
+			require.NoError(t, err)
+
+			data, err := readAll(r)
+			require.NoError(t, err)
+			require.Equal(t, testcase.wants, data)
+			require.NoError(t, r.Close())
+		}
+	})
+
+	t.Run("Unsorted", func(t *testing.T) {
+		_, err := openInput(ctx, writeRawDir(t, 3, 2, 3, 5, 7, 5, 13), Validation{}, log.NoOp())
+		require.ErrorIs(t, err, ErrUnsortedInput)
+		require.ErrorContains(t, err, "primes-ab:2")
+	})
+
+	t.Run("InvalidLine", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "primes-aa"), []byte("2\n3\nfive\n"), 0o644))
+
+		_, err := openInput(ctx, dir, Validation{}, log.NoOp())
+		require.ErrorContains(t, err, "primes-aa:3")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		d, err := openInput(ctx, t.TempDir(), Validation{}, log.NoOp())
+		require.NoError(t, err)
+
+		_, err = d.Last()
+		require.ErrorIs(t, err, ErrEmptyInput)
+	})
+}
+
+func TestPartition(t *testing.T) {
+	ctx := context.Background()
+	primes := sieve.BasePrimes(300_000)
+
+	// shards that do not line up with the blocks
+	input := writeRawDir(t, 7_000, primes...)
+
+	dir := t.TempDir()
+	require.NoError(t, Partition(ctx, WidthLayout(100_000), 3, input, dir, Validation{}, log.NoOp()))
+
+	// matches the dataset built from the same primes in memory
+	manifest, err := ReadManifest(dir)
+	require.NoError(t, err)
+
+	wants, err := ReadManifest(newTestDataset(t))
+	require.NoError(t, err)
+
+	require.Equal(t, wants.Partitions, manifest.Partitions)
+
+	// blocks are registered in order, regardless of which worker completes them first
+	idx, err := OpenIndex(dir, ReadOnlyPragmas(), log.NoOp())
+	require.NoError(t, err)
+
+	defer idx.Close()
+
+	ids, err := getIDs(ctx, idx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"00", "01", "02"}, ids)
+
+	t.Run("ResumeWithDifferentInput", func(t *testing.T) {
+		other := writeRawDir(t, 7_000, primes[1:]...)
+
+		require.ErrorIs(t, Partition(ctx, WidthLayout(100_000), 2, other, dir, Validation{}, log.NoOp()), ErrCheckpointMismatch)
+	})
+}
+
+func TestMigrateSQLite(t *testing.T) {
+	ctx := context.Background()
+	primes := sieve.BasePrimes(100_000)
+
+	db, err := OpenSQLite(t.TempDir()+"/primes.db", ReadWritePragmas(), log.NoOp())
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	require.NoError(t, MigrateSQLite(ctx, db, writeRawDir(t, 1_000, primes...), Validation{}, log.NoOp()))
+
+	var count int
+
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM primes;`).Scan(&count))
+	require.Equal(t, len(primes), count)
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// maxReportedIssues is the number of invalid lines listed in a ValidationReport; the remaining ones are only counted.
+const maxReportedIssues = 100
+
+const (
+	IssueMalformed   = "malformed"
+	IssueOutOfBounds = "out_of_bounds"
+	IssueDuplicate   = "duplicate"
+	IssueUnordered   = "unordered"
+)
+
+var ErrDuplicateValue = errors.New("input value is duplicated")
+
+// Validation sets how the lines in a build input are checked, and how invalid ones are handled.
+type Validation struct {
+	// Lenient skips invalid lines, instead of failing the build.
+	Lenient bool
+
+	// Min and Max are the bounds each value must fall within, inclusive. A zero Max sets no upper bound.
+	Min int
+	Max int
+
+	// Report is the path to write the ValidationReport to, as JSON. None is written if empty.
+	Report string
+}
+
+// check returns the kind of issue with value, which follows prev if started, along with its error, or an empty kind if
+// value is valid.
+func (v Validation) check(value, prev int, started bool) (string, error) {
+	switch {
+	case value < v.Min:
+		return IssueOutOfBounds, fmt.Errorf("%w: %d is below the minimum %d", ErrValueOutOfRange, value, v.Min)
+	case v.Max > 0 && value > v.Max:
+		return IssueOutOfBounds, fmt.Errorf("%w: %d is above the maximum %d", ErrValueOutOfRange, value, v.Max)
+	case started && value == prev:
+		return IssueDuplicate, fmt.Errorf("%w: %d", ErrDuplicateValue, value)
+	case started && value < prev:
+		return IssueUnordered, fmt.Errorf("%w: %d follows %d", ErrUnsortedInput, value, prev)
+	default:
+		return "", nil
+	}
+}
+
+// ValidationReport summarizes the checks run over a build input, before it is built.
+type ValidationReport struct {
+	OK      bool `json:"ok"`
+	Lenient bool `json:"lenient"`
+
+	Files  int `json:"files"`
+	Lines  int `json:"lines"`
+	Values int `json:"values"`
+
+	// Counts holds the number of invalid lines of each kind.
+	Counts    map[string]int    `json:"counts,omitempty"`
+	NumIssues int               `json:"num_issues"`
+	Issues    []ValidationIssue `json:"issues,omitempty"`
+
+	// first is the error for the first invalid line, returned when the validation is strict
+	first error
+}
+
+// ValidationIssue is an invalid line in a build input.
+type ValidationIssue struct {
+	File  string `json:"file"`
+	Line  int    `json:"line"`
+	Kind  string `json:"kind"`
+	Error string `json:"error"`
+}
+
+func (r *ValidationReport) add(file string, line int, kind string, err error) {
+	if r.first == nil {
+		r.first = err
+	}
+
+	if r.Counts == nil {
+		r.Counts = make(map[string]int, 4)
+	}
+
+	r.Counts[kind]++
+	r.NumIssues++
+
+	if len(r.Issues) < maxReportedIssues {
+		r.Issues = append(r.Issues, ValidationIssue{File: file, Line: line, Kind: kind, Error: err.Error()})
+	}
+}
+
+func (r *ValidationReport) write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
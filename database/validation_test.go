@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+)
+
+func TestValidation(t *testing.T) {
+	ctx := context.Background()
+
+	// a malformed line, a duplicate across shards, an out of order value and one past the maximum
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "primes-aa"), []byte("2\n3\nfive\n7\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "primes-ab"), []byte("7\n11\n5\n13\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "primes-ac"), []byte("17\n101\n"), 0o644))
+
+	validation := Validation{Min: 2, Max: 100}
+
+	t.Run("Strict", func(t *testing.T) {
+		report := filepath.Join(t.TempDir(), "report.json")
+		validation := validation
+		validation.Report = report
+
+		_, err := openInput(ctx, dir, validation, log.NoOp())
+		require.ErrorContains(t, err, "primes-aa:3")
+		require.ErrorContains(t, err, "invalid lines: 4")
+
+		data, err := os.ReadFile(report)
+		require.NoError(t, err)
+
+		var r ValidationReport
+		require.NoError(t, json.Unmarshal(data, &r))
+
+		require.False(t, r.OK)
+		require.Equal(t, 3, r.Files)
+		require.Equal(t, 10, r.Lines)
+		require.Equal(t, 6, r.Values)
+		require.Equal(t, 4, r.NumIssues)
+		require.Equal(t, map[string]int{
+			IssueMalformed:   1,
+			IssueDuplicate:   1,
+			IssueUnordered:   1,
+			IssueOutOfBounds: 1,
+		}, r.Counts)
+		require.Equal(t, ValidationIssue{
+			File:  "primes-ab",
+			Line:  1,
+			Kind:  IssueDuplicate,
+			Error: "primes-ab:1: input value is duplicated: 7",
+		}, r.Issues[1])
+	})
+
+	t.Run("Lenient", func(t *testing.T) {
+		validation := validation
+		validation.Lenient = true
+
+		d, err := openInput(ctx, dir, validation, log.NoOp())
+		require.NoError(t, err)
+
+		last, err := d.Last()
+		require.NoError(t, err)
+		require.Equal(t, 17, last)
+
+		for _, testcase := range []struct {
+			from  int
+			wants []int
+		}{
+			{from: 0, wants: []int{2, 3, 7, 11, 13, 17}},
+			// starts within the second shard, past its duplicate first line
+			{from: 8, wants: []int{11, 13, 17}},
+			{from: 14, wants: []int{17}},
+		} {
+			r, err := d.From(ctx, testcase.from)
+			require.NoError(t, err)
+
+			data, err := readAll(r)
+			require.NoError(t, err)
+			require.Equal(t, testcase.wants, data)
+			require.NoError(t, r.Close())
+		}
+
+		require.NoError(t, d.Close())
+	})
+
+	t.Run("Bounds", func(t *testing.T) {
+		_, err := openInput(ctx, writeRawDir(t, 3, 2, 3, 5, 7), Validation{Min: 3}, log.NoOp())
+		require.ErrorIs(t, err, ErrValueOutOfRange)
+		require.ErrorContains(t, err, "primes-aa:1")
+	})
+}
@@ -1,12 +1,14 @@
 package grpcserver
 
 import (
+	"crypto/tls"
 	"net"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 
 	pb "github.com/zalgonoise/tendigitprimes/pb/primes/v1"
@@ -22,14 +24,16 @@ type Metrics interface {
 	RegisterCollector(collector prometheus.Collector)
 }
 
+// NewServer creates a Server with the input interceptors, serving TLS with tlsConfig if set, or plaintext otherwise.
 func NewServer(
 	metrics Metrics,
+	tlsConfig *tls.Config,
 	unaryInterceptors []grpc.UnaryServerInterceptor,
 	streamInterceptors []grpc.StreamServerInterceptor,
 ) *Server {
 	serverMetrics := grpc_prometheus.NewServerMetrics(grpc_prometheus.WithServerHandlingTimeHistogram())
 
-	s := grpc.NewServer(
+	opts := []grpc.ServerOption{
 		grpc.StatsHandler(otelgrpc.NewServerHandler(
 			otelgrpc.WithMessageEvents(otelgrpc.SentEvents, otelgrpc.ReceivedEvents),
 		)),
@@ -39,7 +43,13 @@ func NewServer(
 		grpc.ChainStreamInterceptor(
 			append([]grpc.StreamServerInterceptor{serverMetrics.StreamServerInterceptor()}, streamInterceptors...)...,
 		),
-	)
+	}
+
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	s := grpc.NewServer(opts...)
 
 	reflection.Register(s)
 	metrics.RegisterCollector(serverMetrics)
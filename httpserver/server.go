@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"net/http"
 	"time"
@@ -13,6 +14,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/zalgonoise/tendigitprimes/apikeys"
 	pb "github.com/zalgonoise/tendigitprimes/pb/primes/v1"
 )
 
@@ -26,7 +28,9 @@ const (
 	defaultWriteTimeout = 210 * time.Second // consider wide-range queries
 )
 
-func NewServer(addr string) (*Server, error) {
+// NewServer creates a Server listening on addr, serving TLS with tlsConfig if set, or plaintext otherwise. Requests go
+// through the input middleware in order, after being traced.
+func NewServer(addr string, tlsConfig *tls.Config, middleware ...func(http.Handler) http.Handler) (*Server, error) {
 	mux := runtime.NewServeMux(
 		runtime.WithMetadata(func(ctx context.Context, request *http.Request) metadata.MD {
 			md := metadata.MD{}
@@ -35,6 +39,7 @@ func NewServer(addr string) (*Server, error) {
 
 			return md
 		}),
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
 	)
 
 	err := mux.HandlePath(http.MethodGet, "/ready", ready)
@@ -48,12 +53,19 @@ func NewServer(addr string) (*Server, error) {
 		}),
 	)
 
+	var handler http.Handler = mux
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
 	return &Server{
 		server: http.Server{
-			Handler:      tracingMiddleware(urlAttributesMiddleware(mux)),
+			Handler:      tracingMiddleware(urlAttributesMiddleware(handler)),
 			Addr:         addr,
 			ReadTimeout:  defaultReadTimeout,
 			WriteTimeout: defaultWriteTimeout,
+			TLSConfig:    tlsConfig,
 		},
 		mux: mux,
 	}, nil
@@ -73,10 +85,19 @@ func (s *Server) RegisterHTTP(method, path string, handler http.Handler) error {
 }
 
 func (s *Server) ListenAndServe() error {
+	// certificates are served from the TLS config
+	if s.server.TLSConfig != nil {
+		return s.server.ListenAndServeTLS("", "")
+	}
+
 	return s.server.ListenAndServe()
 }
 
 func (s *Server) Serve(lis net.Listener) error {
+	if s.server.TLSConfig != nil {
+		return s.server.ServeTLS(lis, "", "")
+	}
+
 	return s.server.Serve(lis)
 }
 
@@ -84,6 +105,15 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.server.Shutdown(ctx)
 }
 
+// headerMatcher forwards the API key header to the gRPC server as metadata, on top of the gateway's defaults.
+func headerMatcher(key string) (string, bool) {
+	if http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(apikeys.Header) {
+		return apikeys.MetadataKey, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
 func ready(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
 	w.WriteHeader(http.StatusOK)
 }
@@ -18,8 +18,19 @@ const (
 
 const maxSpanContextAttrs = 2
 
+type attrsCtx struct{}
+
+// WithAttrs returns a copy of ctx holding the input attributes, which a SpanContextHandler adds to each record logged
+// with ctx, along with any attributes already in it.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	prev, _ := ctx.Value(attrsCtx{}).([]slog.Attr)
+
+	return context.WithValue(ctx, attrsCtx{}, append(prev[:len(prev):len(prev)], attrs...))
+}
+
 // SpanContextHandler is a slog.Handler wrapper that adds trace data as log attributes on each
 // Handle call, given that the input context to the method contains a valid trace.SpanContext.
+// It also adds any attributes set in the context with WithAttrs.
 type SpanContextHandler struct {
 	withSpanID bool
 	handler    slog.Handler
@@ -91,6 +102,10 @@ func (h *SpanContextHandler) Handle(ctx context.Context, record slog.Record) err
 		record.AddAttrs(attrs...)
 	}
 
+	if attrs, ok := ctx.Value(attrsCtx{}).([]slog.Attr); ok {
+		record.AddAttrs(attrs...)
+	}
+
 	return h.handler.Handle(ctx, record)
 }
 
@@ -101,6 +101,32 @@ func TestSpanContextHandler_Handle(t *testing.T) {
 	}
 }
 
+func TestWithAttrs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(NewSpanContextHandler(slog.NewJSONHandler(buf, nil), false))
+
+	ctx := WithAttrs(context.Background(), slog.String("key", "value"))
+	other := WithAttrs(ctx, slog.Int("num", 1))
+
+	logger.InfoContext(other, "test log event")
+
+	var m map[string]any
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, "value", m["key"])
+	require.Equal(t, float64(1), m["num"])
+
+	// the parent context is left as is
+	buf.Reset()
+	logger.InfoContext(ctx, "test log event")
+
+	m = nil
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	require.Equal(t, "value", m["key"])
+	require.NotContains(t, m, "num")
+}
+
 func TestSpanContextHandler_WithAttrs(t *testing.T) {
 	handler1 := NewSpanContextHandler(slog.NewJSONHandler(io.Discard, nil), false)
 	handler2 := NewSpanContextHandler(slog.NewJSONHandler(io.Discard, nil), false)
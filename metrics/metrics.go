@@ -23,6 +23,40 @@ type Metrics struct {
 	requestsReceivedErrored *prometheus.CounterVec
 	requestsLatencySeconds  *prometheus.HistogramVec
 
+	// Partition metrics
+	partitionsOpenedTotal  prometheus.Counter
+	partitionsEvictedTotal prometheus.Counter
+	partitionsOpen         prometheus.Gauge
+
+	// Pool metrics
+	poolHitsTotal   *prometheus.CounterVec
+	poolMissesTotal *prometheus.CounterVec
+
+	// Cache metrics
+	cacheHitsTotal      *prometheus.CounterVec
+	cacheMissesTotal    *prometheus.CounterVec
+	cacheEvictionsTotal prometheus.Counter
+	cacheEntries        prometheus.Gauge
+
+	// Composite repository metrics
+	backendFailuresTotal *prometheus.CounterVec
+	hedgedRequestsTotal  *prometheus.CounterVec
+
+	// Dataset reload metrics
+	datasetReloadsTotal       prometheus.Counter
+	datasetReloadsFailedTotal prometheus.Counter
+
+	// Certificate reload metrics
+	certificateReloadsTotal       prometheus.Counter
+	certificateReloadsFailedTotal prometheus.Counter
+
+	// API key metrics
+	apiKeyRequestsTotal   *prometheus.CounterVec
+	apiKeyRejectionsTotal *prometheus.CounterVec
+
+	// Rate limiting metrics
+	rateLimitedTotal *prometheus.CounterVec
+
 	// Third party metrics
 	collectors []prometheus.Collector
 }
@@ -60,6 +94,78 @@ func (m *Metrics) ObserveRequestLatency(ctx context.Context, minimum, maximum st
 	m.requestsLatencySeconds.WithLabelValues(minimum, maximum).Observe(duration.Seconds())
 }
 
+func (m *Metrics) IncPartitionsOpened() {
+	m.partitionsOpenedTotal.Inc()
+}
+
+func (m *Metrics) IncPartitionsEvicted() {
+	m.partitionsEvictedTotal.Inc()
+}
+
+func (m *Metrics) SetPartitionsOpen(n int) {
+	m.partitionsOpen.Set(float64(n))
+}
+
+func (m *Metrics) IncPoolHits(minimum, maximum string) {
+	m.poolHitsTotal.WithLabelValues(minimum, maximum).Inc()
+}
+
+func (m *Metrics) IncPoolMisses(minimum, maximum string) {
+	m.poolMissesTotal.WithLabelValues(minimum, maximum).Inc()
+}
+
+func (m *Metrics) IncCacheHits(op string) {
+	m.cacheHitsTotal.WithLabelValues(op).Inc()
+}
+
+func (m *Metrics) IncCacheMisses(op string) {
+	m.cacheMissesTotal.WithLabelValues(op).Inc()
+}
+
+func (m *Metrics) IncCacheEvictions() {
+	m.cacheEvictionsTotal.Inc()
+}
+
+func (m *Metrics) SetCacheEntries(n int) {
+	m.cacheEntries.Set(float64(n))
+}
+
+func (m *Metrics) IncBackendFailures(backend string) {
+	m.backendFailuresTotal.WithLabelValues(backend).Inc()
+}
+
+func (m *Metrics) IncHedgedRequests(backend string) {
+	m.hedgedRequestsTotal.WithLabelValues(backend).Inc()
+}
+
+func (m *Metrics) IncDatasetReloads() {
+	m.datasetReloadsTotal.Inc()
+}
+
+func (m *Metrics) IncDatasetReloadsFailed() {
+	m.datasetReloadsFailedTotal.Inc()
+}
+
+func (m *Metrics) IncCertificateReloads() {
+	m.certificateReloadsTotal.Inc()
+}
+
+func (m *Metrics) IncCertificateReloadsFailed() {
+	m.certificateReloadsFailedTotal.Inc()
+}
+
+func (m *Metrics) IncAPIKeyRequests(key, method string) {
+	m.apiKeyRequestsTotal.WithLabelValues(key, method).Inc()
+}
+
+func (m *Metrics) IncAPIKeyRejections(reason string) {
+	m.apiKeyRejectionsTotal.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) IncRateLimited(method string) {
+	m.rateLimitedTotal.WithLabelValues(method).Inc()
+}
+
 func (m *Metrics) Registry() (*prometheus.Registry, error) {
 	reg := prometheus.NewRegistry()
 
@@ -71,6 +177,24 @@ func (m *Metrics) Registry() (*prometheus.Registry, error) {
 		m.requestsReceivedTotal,
 		m.requestsReceivedErrored,
 		m.requestsLatencySeconds,
+		m.partitionsOpenedTotal,
+		m.partitionsEvictedTotal,
+		m.partitionsOpen,
+		m.poolHitsTotal,
+		m.poolMissesTotal,
+		m.cacheHitsTotal,
+		m.cacheMissesTotal,
+		m.cacheEvictionsTotal,
+		m.cacheEntries,
+		m.backendFailuresTotal,
+		m.hedgedRequestsTotal,
+		m.datasetReloadsTotal,
+		m.datasetReloadsFailedTotal,
+		m.certificateReloadsTotal,
+		m.certificateReloadsFailedTotal,
+		m.apiKeyRequestsTotal,
+		m.apiKeyRejectionsTotal,
+		m.rateLimitedTotal,
 	} {
 		err := reg.Register(metric)
 		if err != nil {
@@ -103,5 +227,77 @@ func NewMetrics() *Metrics {
 			Help:    "Histogram of request processing times",
 			Buckets: []float64{.00001, .00005, .0001, .0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
 		}, []string{"minimum", "maximum"}),
+		partitionsOpenedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "partitions_opened_total",
+			Help: "Count of database partitions opened on demand",
+		}),
+		partitionsEvictedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "partitions_evicted_total",
+			Help: "Count of database partitions closed after being evicted from the handle cache",
+		}),
+		partitionsOpen: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "partitions_open",
+			Help: "Number of currently open database partitions",
+		}),
+		poolHitsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pool_hits_total",
+			Help: "Count of requests served from the pre-sampled primes pool",
+		}, []string{"minimum", "maximum"}),
+		poolMissesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pool_misses_total",
+			Help: "Count of requests on a pooled range that were served by the repository",
+		}, []string{"minimum", "maximum"}),
+		cacheHitsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cache_hits_total",
+			Help: "Count of lookups served from the cache",
+		}, []string{"op"}),
+		cacheMissesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cache_misses_total",
+			Help: "Count of lookups that were not in the cache",
+		}, []string{"op"}),
+		cacheEvictionsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "cache_evictions_total",
+			Help: "Count of entries evicted from the cache due to its size limit",
+		}),
+		cacheEntries: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "cache_entries",
+			Help: "Number of entries currently in the cache",
+		}),
+		backendFailuresTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "backend_failures_total",
+			Help: "Count of requests that failed on a composite repository backend",
+		}, []string{"backend"}),
+		hedgedRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "hedged_requests_total",
+			Help: "Count of slow requests that were also fired on a composite repository backend",
+		}, []string{"backend"}),
+		datasetReloadsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "dataset_reloads_total",
+			Help: "Count of successful dataset reloads",
+		}),
+		datasetReloadsFailedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "dataset_reloads_failed_total",
+			Help: "Count of dataset reloads that failed, keeping the previous dataset",
+		}),
+		certificateReloadsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "certificate_reloads_total",
+			Help: "Count of TLS certificate reloads after their files changed",
+		}),
+		certificateReloadsFailedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "certificate_reloads_failed_total",
+			Help: "Count of TLS certificate reloads that failed, keeping the previous certificates",
+		}),
+		apiKeyRequestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "api_key_requests_total",
+			Help: "Count of authenticated gRPC calls, by API key ID and method",
+		}, []string{"key", "method"}),
+		apiKeyRejectionsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "api_key_rejections_total",
+			Help: "Count of requests rejected for a missing, invalid or revoked API key",
+		}, []string{"reason"}),
+		rateLimitedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "rate_limited_total",
+			Help: "Count of requests rejected for exceeding their client's rate limit, by method",
+		}, []string{"method"}),
 	}
 }
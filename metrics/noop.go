@@ -15,4 +15,22 @@ func (m Noop) IncRequestsReceivedTotal(string, string)
 func (m Noop) IncRequestsReceivedErrored(string, string)                            {}
 func (m Noop) ObserveRequestLatency(context.Context, string, string, time.Duration) {}
 func (m Noop) SetDatabaseReadiness(bool)                                            {}
+func (m Noop) IncPartitionsOpened()                                                 {}
+func (m Noop) IncPartitionsEvicted()                                                {}
+func (m Noop) SetPartitionsOpen(int)                                                {}
+func (m Noop) IncPoolHits(string, string)                                           {}
+func (m Noop) IncPoolMisses(string, string)                                         {}
+func (m Noop) IncCacheHits(string)                                                  {}
+func (m Noop) IncCacheMisses(string)                                                {}
+func (m Noop) IncCacheEvictions()                                                   {}
+func (m Noop) SetCacheEntries(int)                                                  {}
+func (m Noop) IncBackendFailures(string)                                            {}
+func (m Noop) IncHedgedRequests(string)                                             {}
+func (m Noop) IncDatasetReloads()                                                   {}
+func (m Noop) IncDatasetReloadsFailed()                                             {}
+func (m Noop) IncCertificateReloads()                                               {}
+func (m Noop) IncCertificateReloadsFailed()                                         {}
+func (m Noop) IncAPIKeyRequests(string, string)                                     {}
+func (m Noop) IncAPIKeyRejections(string)                                           {}
+func (m Noop) IncRateLimited(string)                                                {}
 func (m Noop) Registry() (*prometheus.Registry, error)                              { return prometheus.NewRegistry(), nil }
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.33.0
+// 	protoc-gen-go v1.34.2
 // 	protoc        (unknown)
 // source: primes/v1/primes.proto
 
@@ -237,6 +237,484 @@ func (x *ListResponse) GetPrimes() []int64 {
 	return nil
 }
 
+type IsPrimeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Number int64 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+}
+
+func (x *IsPrimeRequest) Reset() {
+	*x = IsPrimeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_primes_v1_primes_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IsPrimeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsPrimeRequest) ProtoMessage() {}
+
+func (x *IsPrimeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_primes_v1_primes_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsPrimeRequest.ProtoReflect.Descriptor instead.
+func (*IsPrimeRequest) Descriptor() ([]byte, []int) {
+	return file_primes_v1_primes_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *IsPrimeRequest) GetNumber() int64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+type IsPrimeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Prime bool `protobuf:"varint,1,opt,name=prime,json=is_prime,proto3" json:"prime,omitempty"`
+}
+
+func (x *IsPrimeResponse) Reset() {
+	*x = IsPrimeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_primes_v1_primes_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IsPrimeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IsPrimeResponse) ProtoMessage() {}
+
+func (x *IsPrimeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_primes_v1_primes_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IsPrimeResponse.ProtoReflect.Descriptor instead.
+func (*IsPrimeResponse) Descriptor() ([]byte, []int) {
+	return file_primes_v1_primes_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *IsPrimeResponse) GetPrime() bool {
+	if x != nil {
+		return x.Prime
+	}
+	return false
+}
+
+type NextRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Number int64 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+}
+
+func (x *NextRequest) Reset() {
+	*x = NextRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_primes_v1_primes_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NextRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextRequest) ProtoMessage() {}
+
+func (x *NextRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_primes_v1_primes_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextRequest.ProtoReflect.Descriptor instead.
+func (*NextRequest) Descriptor() ([]byte, []int) {
+	return file_primes_v1_primes_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *NextRequest) GetNumber() int64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+type NextResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Prime int64 `protobuf:"varint,1,opt,name=prime,json=prime_number,proto3" json:"prime,omitempty"`
+}
+
+func (x *NextResponse) Reset() {
+	*x = NextResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_primes_v1_primes_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NextResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextResponse) ProtoMessage() {}
+
+func (x *NextResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_primes_v1_primes_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextResponse.ProtoReflect.Descriptor instead.
+func (*NextResponse) Descriptor() ([]byte, []int) {
+	return file_primes_v1_primes_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *NextResponse) GetPrime() int64 {
+	if x != nil {
+		return x.Prime
+	}
+	return 0
+}
+
+type PreviousRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Number int64 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+}
+
+func (x *PreviousRequest) Reset() {
+	*x = PreviousRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_primes_v1_primes_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PreviousRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviousRequest) ProtoMessage() {}
+
+func (x *PreviousRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_primes_v1_primes_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviousRequest.ProtoReflect.Descriptor instead.
+func (*PreviousRequest) Descriptor() ([]byte, []int) {
+	return file_primes_v1_primes_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PreviousRequest) GetNumber() int64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+type PreviousResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Prime int64 `protobuf:"varint,1,opt,name=prime,json=prime_number,proto3" json:"prime,omitempty"`
+}
+
+func (x *PreviousResponse) Reset() {
+	*x = PreviousResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_primes_v1_primes_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PreviousResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviousResponse) ProtoMessage() {}
+
+func (x *PreviousResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_primes_v1_primes_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviousResponse.ProtoReflect.Descriptor instead.
+func (*PreviousResponse) Descriptor() ([]byte, []int) {
+	return file_primes_v1_primes_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PreviousResponse) GetPrime() int64 {
+	if x != nil {
+		return x.Prime
+	}
+	return 0
+}
+
+type CountRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Min int64 `protobuf:"varint,1,opt,name=min,proto3" json:"min,omitempty"`
+	Max int64 `protobuf:"varint,2,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (x *CountRequest) Reset() {
+	*x = CountRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_primes_v1_primes_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountRequest) ProtoMessage() {}
+
+func (x *CountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_primes_v1_primes_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountRequest.ProtoReflect.Descriptor instead.
+func (*CountRequest) Descriptor() ([]byte, []int) {
+	return file_primes_v1_primes_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CountRequest) GetMin() int64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *CountRequest) GetMax() int64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+type CountResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count int64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+}
+
+func (x *CountResponse) Reset() {
+	*x = CountResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_primes_v1_primes_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountResponse) ProtoMessage() {}
+
+func (x *CountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_primes_v1_primes_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountResponse.ProtoReflect.Descriptor instead.
+func (*CountResponse) Descriptor() ([]byte, []int) {
+	return file_primes_v1_primes_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CountResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type NthRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	N int64 `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+}
+
+func (x *NthRequest) Reset() {
+	*x = NthRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_primes_v1_primes_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NthRequest) ProtoMessage() {}
+
+func (x *NthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_primes_v1_primes_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NthRequest.ProtoReflect.Descriptor instead.
+func (*NthRequest) Descriptor() ([]byte, []int) {
+	return file_primes_v1_primes_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *NthRequest) GetN() int64 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+type NthResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Prime int64 `protobuf:"varint,1,opt,name=prime,json=prime_number,proto3" json:"prime,omitempty"`
+}
+
+func (x *NthResponse) Reset() {
+	*x = NthResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_primes_v1_primes_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NthResponse) ProtoMessage() {}
+
+func (x *NthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_primes_v1_primes_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NthResponse.ProtoReflect.Descriptor instead.
+func (*NthResponse) Descriptor() ([]byte, []int) {
+	return file_primes_v1_primes_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *NthResponse) GetPrime() int64 {
+	if x != nil {
+		return x.Prime
+	}
+	return 0
+}
+
 var File_primes_v1_primes_proto protoreflect.FileDescriptor
 
 var file_primes_v1_primes_proto_rawDesc = []byte{
@@ -270,57 +748,166 @@ var file_primes_v1_primes_proto_rawDesc = []byte{
 	0x78, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x2d, 0x0a, 0x0c, 0x4c, 0x69, 0x73,
 	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x06, 0x70, 0x72, 0x69,
 	0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x03, 0x52, 0x0d, 0x70, 0x72, 0x69, 0x6d, 0x65,
-	0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x32, 0xce, 0x03, 0x0a, 0x06, 0x50, 0x72, 0x69,
-	0x6d, 0x65, 0x73, 0x12, 0xe5, 0x01, 0x0a, 0x06, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x12, 0x18,
-	0x2e, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e, 0x64, 0x6f,
-	0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x69, 0x6d, 0x65,
-	0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x22, 0xa5, 0x01, 0x92, 0x41, 0x8a, 0x01, 0x0a, 0x06, 0x50, 0x72, 0x69, 0x6d,
-	0x65, 0x73, 0x12, 0x37, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x61, 0x20, 0x72, 0x61,
-	0x6e, 0x64, 0x6f, 0x6d, 0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65,
-	0x72, 0x20, 0x75, 0x70, 0x20, 0x74, 0x6f, 0x20, 0x31, 0x30, 0x20, 0x64, 0x69, 0x67, 0x69, 0x74,
-	0x73, 0x20, 0x69, 0x6e, 0x20, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x1a, 0x47, 0x54, 0x68, 0x69,
-	0x73, 0x20, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x20, 0x72, 0x65, 0x74, 0x75, 0x72,
-	0x6e, 0x73, 0x20, 0x61, 0x20, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x20, 0x70, 0x72, 0x69, 0x6d,
-	0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x2c, 0x20, 0x75, 0x70, 0x20, 0x74, 0x6f, 0x20,
+	0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x22, 0x37, 0x0a, 0x0e, 0x49, 0x73, 0x50, 0x72,
+	0x69, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x06, 0x6e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x42, 0x0d, 0xfa, 0x42, 0x0a, 0x22,
+	0x08, 0x18, 0xff, 0xc7, 0xaf, 0xa0, 0x25, 0x28, 0x00, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x22, 0x2a, 0x0a, 0x0f, 0x49, 0x73, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x17, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x5f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x22, 0x34, 0x0a,
+	0x0b, 0x4e, 0x65, 0x78, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x06,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x42, 0x0d, 0xfa, 0x42,
+	0x0a, 0x22, 0x08, 0x18, 0xff, 0xc7, 0xaf, 0xa0, 0x25, 0x28, 0x00, 0x52, 0x06, 0x6e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x22, 0x2b, 0x0a, 0x0c, 0x4e, 0x65, 0x78, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0c, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x22, 0x38, 0x0a, 0x0f, 0x50, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x42, 0x0d, 0xfa, 0x42, 0x0a, 0x22, 0x08, 0x18, 0xff, 0xc7, 0xaf, 0xa0, 0x25,
+	0x28, 0x00, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x2f, 0x0a, 0x10, 0x50, 0x72,
+	0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b,
+	0x0a, 0x05, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x70,
+	0x72, 0x69, 0x6d, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x48, 0x0a, 0x0c, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x03, 0x6d,
+	0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x22, 0x02, 0x28,
+	0x02, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x12, 0x1d, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x42, 0x0b, 0xfa, 0x42, 0x08, 0x22, 0x06, 0x18, 0xff, 0xc7, 0xaf, 0xa0, 0x25,
+	0x52, 0x03, 0x6d, 0x61, 0x78, 0x22, 0x25, 0x0a, 0x0d, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x23, 0x0a, 0x0a,
+	0x4e, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x01, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x22, 0x02, 0x28, 0x01, 0x52, 0x01,
+	0x6e, 0x22, 0x2a, 0x0a, 0x0b, 0x4e, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x1b, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0c, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x32, 0xa3, 0x0d,
+	0x0a, 0x06, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x12, 0xe5, 0x01, 0x0a, 0x06, 0x52, 0x61, 0x6e,
+	0x64, 0x6f, 0x6d, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e,
+	0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xa5, 0x01, 0x92, 0x41, 0x8a, 0x01, 0x0a,
+	0x06, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x37, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73,
+	0x20, 0x61, 0x20, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x20, 0x75, 0x70, 0x20, 0x74, 0x6f, 0x20, 0x31, 0x30, 0x20,
+	0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x20, 0x69, 0x6e, 0x20, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68,
+	0x1a, 0x47, 0x54, 0x68, 0x69, 0x73, 0x20, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x20,
+	0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x61, 0x20, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d,
+	0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x2c, 0x20, 0x75,
+	0x70, 0x20, 0x74, 0x6f, 0x20, 0x31, 0x30, 0x20, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x20, 0x69,
+	0x6e, 0x20, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x2e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x12,
+	0x0f, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2f, 0x72, 0x61, 0x6e, 0x64,
+	0x12, 0xdb, 0x01, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x69, 0x6d,
+	0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xa1, 0x01, 0x92, 0x41, 0x8b,
+	0x01, 0x0a, 0x06, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x38, 0x52, 0x65, 0x74, 0x75, 0x72,
+	0x6e, 0x73, 0x20, 0x61, 0x20, 0x73, 0x65, 0x74, 0x20, 0x6f, 0x66, 0x20, 0x70, 0x72, 0x69, 0x6d,
+	0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x20, 0x75, 0x70, 0x20, 0x74, 0x6f, 0x20,
 	0x31, 0x30, 0x20, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x20, 0x69, 0x6e, 0x20, 0x6c, 0x65, 0x6e,
-	0x67, 0x74, 0x68, 0x2e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x11, 0x12, 0x0f, 0x2f, 0x76, 0x31, 0x2f,
-	0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2f, 0x72, 0x61, 0x6e, 0x64, 0x12, 0xdb, 0x01, 0x0a, 0x04,
-	0x4c, 0x69, 0x73, 0x74, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31,
-	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70,
-	0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xa1, 0x01, 0x92, 0x41, 0x8b, 0x01, 0x0a, 0x06, 0x50, 0x72,
-	0x69, 0x6d, 0x65, 0x73, 0x12, 0x38, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x61, 0x20,
-	0x73, 0x65, 0x74, 0x20, 0x6f, 0x66, 0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d,
-	0x62, 0x65, 0x72, 0x73, 0x20, 0x75, 0x70, 0x20, 0x74, 0x6f, 0x20, 0x31, 0x30, 0x20, 0x64, 0x69,
-	0x67, 0x69, 0x74, 0x73, 0x20, 0x69, 0x6e, 0x20, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x1a, 0x47,
-	0x54, 0x68, 0x69, 0x73, 0x20, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x20, 0x72, 0x65,
-	0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x61, 0x20, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x20, 0x70,
-	0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x2c, 0x20, 0x75, 0x70, 0x20,
-	0x74, 0x6f, 0x20, 0x31, 0x30, 0x20, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x20, 0x69, 0x6e, 0x20,
-	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x2e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0c, 0x12, 0x0a, 0x2f,
-	0x76, 0x31, 0x2f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x42, 0xcc, 0x02, 0x92, 0x41, 0x9c, 0x02,
-	0x0a, 0x03, 0x32, 0x2e, 0x30, 0x12, 0x46, 0x0a, 0x06, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x12,
-	0x37, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x20,
-	0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x20, 0x75, 0x70,
+	0x67, 0x74, 0x68, 0x1a, 0x47, 0x54, 0x68, 0x69, 0x73, 0x20, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x20, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x61, 0x20, 0x72, 0x61, 0x6e,
+	0x64, 0x6f, 0x6d, 0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x2c, 0x20, 0x75, 0x70, 0x20, 0x74, 0x6f, 0x20, 0x31, 0x30, 0x20, 0x64, 0x69, 0x67, 0x69, 0x74,
+	0x73, 0x20, 0x69, 0x6e, 0x20, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x2e, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x0c, 0x12, 0x0a, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x12, 0xf5,
+	0x01, 0x0a, 0x07, 0x49, 0x73, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x69,
+	0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x73, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x76,
+	0x31, 0x2e, 0x49, 0x73, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0xb2, 0x01, 0x92, 0x41, 0x93, 0x01, 0x0a, 0x06, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x73,
+	0x12, 0x2a, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x77, 0x68, 0x65, 0x74, 0x68, 0x65,
+	0x72, 0x20, 0x61, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x20, 0x69, 0x73, 0x20, 0x61, 0x20,
+	0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x1a, 0x5d, 0x54, 0x68,
+	0x69, 0x73, 0x20, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x20, 0x72, 0x65, 0x74, 0x75,
+	0x72, 0x6e, 0x73, 0x20, 0x77, 0x68, 0x65, 0x74, 0x68, 0x65, 0x72, 0x20, 0x74, 0x68, 0x65, 0x20,
+	0x69, 0x6e, 0x70, 0x75, 0x74, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x2c, 0x20, 0x75, 0x70,
 	0x20, 0x74, 0x6f, 0x20, 0x31, 0x30, 0x20, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x20, 0x69, 0x6e,
-	0x20, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x2e, 0x32, 0x03, 0x31, 0x2e, 0x30, 0x1a, 0x0e, 0x6c,
-	0x6f, 0x63, 0x61, 0x6c, 0x68, 0x6f, 0x73, 0x74, 0x3a, 0x38, 0x30, 0x38, 0x30, 0x2a, 0x01, 0x01,
-	0x52, 0x35, 0x0a, 0x03, 0x34, 0x30, 0x31, 0x12, 0x2e, 0x0a, 0x0f, 0x55, 0x6e, 0x61, 0x75, 0x74,
-	0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x19, 0x1a, 0x17,
-	0x23, 0x2f, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x72, 0x70,
-	0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x32, 0x0a, 0x03, 0x34, 0x30, 0x33, 0x12, 0x2b,
-	0x0a, 0x0c, 0x55, 0x6e, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x64, 0x12, 0x1b,
-	0x0a, 0x19, 0x1a, 0x17, 0x23, 0x2f, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x2f, 0x72, 0x70, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x6a, 0x4f, 0x0a, 0x06, 0x50,
-	0x72, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x45, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x20, 0x77,
-	0x68, 0x69, 0x63, 0x68, 0x20, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x73, 0x20, 0x72, 0x61,
-	0x6e, 0x64, 0x6f, 0x6d, 0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65,
-	0x72, 0x73, 0x20, 0x75, 0x70, 0x20, 0x74, 0x6f, 0x20, 0x31, 0x30, 0x20, 0x64, 0x69, 0x67, 0x69,
-	0x74, 0x73, 0x20, 0x69, 0x6e, 0x20, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x5a, 0x2a, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x7a, 0x61, 0x6c, 0x67, 0x6f, 0x6e, 0x6f,
-	0x69, 0x73, 0x65, 0x2f, 0x74, 0x65, 0x6e, 0x64, 0x69, 0x67, 0x69, 0x74, 0x70, 0x72, 0x69, 0x6d,
-	0x65, 0x73, 0x2f, 0x70, 0x62, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x20, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x2c, 0x20, 0x69, 0x73, 0x20, 0x61, 0x20, 0x70, 0x72,
+	0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x2e, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x15, 0x12, 0x13, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2f, 0x69, 0x73,
+	0x2d, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x12, 0x8a, 0x02, 0x0a, 0x04, 0x4e, 0x65, 0x78, 0x74, 0x12,
+	0x16, 0x2e, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x65, 0x78, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73,
+	0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x65, 0x78, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0xd0, 0x01, 0x92, 0x41, 0xb5, 0x01, 0x0a, 0x06, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x12,
+	0x37, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x74, 0x68, 0x65, 0x20, 0x73, 0x6d, 0x61,
+	0x6c, 0x6c, 0x65, 0x73, 0x74, 0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62,
+	0x65, 0x72, 0x20, 0x67, 0x72, 0x65, 0x61, 0x74, 0x65, 0x72, 0x20, 0x74, 0x68, 0x61, 0x6e, 0x20,
+	0x61, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x1a, 0x72, 0x54, 0x68, 0x69, 0x73, 0x20, 0x65,
+	0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x20, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20,
+	0x74, 0x68, 0x65, 0x20, 0x73, 0x6d, 0x61, 0x6c, 0x6c, 0x65, 0x73, 0x74, 0x20, 0x70, 0x72, 0x69,
+	0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x2c, 0x20, 0x75, 0x70, 0x20, 0x74, 0x6f,
+	0x20, 0x31, 0x30, 0x20, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x20, 0x69, 0x6e, 0x20, 0x6c, 0x65,
+	0x6e, 0x67, 0x74, 0x68, 0x2c, 0x20, 0x74, 0x68, 0x61, 0x74, 0x20, 0x69, 0x73, 0x20, 0x67, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x72, 0x20, 0x74, 0x68, 0x61, 0x6e, 0x20, 0x74, 0x68, 0x65, 0x20, 0x69,
+	0x6e, 0x70, 0x75, 0x74, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x2e, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x11, 0x12, 0x0f, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2f, 0x6e,
+	0x65, 0x78, 0x74, 0x12, 0xfa, 0x01, 0x0a, 0x08, 0x50, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73,
+	0x12, 0x1a, 0x2e, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65,
+	0x76, 0x69, 0x6f, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x70,
+	0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xb4, 0x01, 0x92, 0x41, 0x95, 0x01,
+	0x0a, 0x06, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x35, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e,
+	0x73, 0x20, 0x74, 0x68, 0x65, 0x20, 0x67, 0x72, 0x65, 0x61, 0x74, 0x65, 0x73, 0x74, 0x20, 0x70,
+	0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x20, 0x6c, 0x6f, 0x77, 0x65,
+	0x72, 0x20, 0x74, 0x68, 0x61, 0x6e, 0x20, 0x61, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x1a,
+	0x54, 0x54, 0x68, 0x69, 0x73, 0x20, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x20, 0x72,
+	0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x74, 0x68, 0x65, 0x20, 0x67, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x73, 0x74, 0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x20, 0x74, 0x68, 0x61, 0x74, 0x20, 0x69, 0x73, 0x20, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x20, 0x74,
+	0x68, 0x61, 0x6e, 0x20, 0x74, 0x68, 0x65, 0x20, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x20, 0x6e, 0x75,
+	0x6d, 0x62, 0x65, 0x72, 0x2e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15, 0x12, 0x13, 0x2f, 0x76, 0x31,
+	0x2f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2f, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73,
+	0x12, 0xfb, 0x01, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x69,
+	0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xbe, 0x01,
+	0x92, 0x41, 0xa2, 0x01, 0x0a, 0x06, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x32, 0x52, 0x65,
+	0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x74, 0x68, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x20, 0x6f, 0x66, 0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x73, 0x20, 0x77, 0x69, 0x74, 0x68, 0x69, 0x6e, 0x20, 0x61, 0x20, 0x72, 0x61, 0x6e, 0x67, 0x65,
+	0x1a, 0x64, 0x54, 0x68, 0x69, 0x73, 0x20, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x20,
+	0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x68, 0x6f, 0x77, 0x20, 0x6d, 0x61, 0x6e, 0x79,
+	0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x2c, 0x20,
+	0x75, 0x70, 0x20, 0x74, 0x6f, 0x20, 0x31, 0x30, 0x20, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x20,
+	0x69, 0x6e, 0x20, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x2c, 0x20, 0x61, 0x72, 0x65, 0x20, 0x77,
+	0x69, 0x74, 0x68, 0x69, 0x6e, 0x20, 0x74, 0x68, 0x65, 0x20, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x20,
+	0x72, 0x61, 0x6e, 0x67, 0x65, 0x2e, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x12, 0x12, 0x10, 0x2f, 0x76,
+	0x31, 0x2f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0xd2,
+	0x01, 0x0a, 0x03, 0x4e, 0x74, 0x68, 0x12, 0x15, 0x2e, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e,
+	0x76, 0x31, 0x2e, 0x4e, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x74, 0x68, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x9b, 0x01, 0x92, 0x41, 0x81, 0x01, 0x0a, 0x06, 0x50, 0x72,
+	0x69, 0x6d, 0x65, 0x73, 0x12, 0x1d, 0x52, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x74, 0x68,
+	0x65, 0x20, 0x6e, 0x2d, 0x74, 0x68, 0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x1a, 0x58, 0x54, 0x68, 0x69, 0x73, 0x20, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x20, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x73, 0x20, 0x74, 0x68, 0x65, 0x20, 0x6e,
+	0x2d, 0x74, 0x68, 0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72,
+	0x2c, 0x20, 0x77, 0x68, 0x65, 0x72, 0x65, 0x20, 0x74, 0x68, 0x65, 0x20, 0x66, 0x69, 0x72, 0x73,
+	0x74, 0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x20, 0x28,
+	0x32, 0x29, 0x20, 0x68, 0x61, 0x73, 0x20, 0x6e, 0x20, 0x3d, 0x20, 0x31, 0x2e, 0x82, 0xd3, 0xe4,
+	0x93, 0x02, 0x10, 0x12, 0x0e, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2f,
+	0x6e, 0x74, 0x68, 0x42, 0xcc, 0x02, 0x92, 0x41, 0x9c, 0x02, 0x0a, 0x03, 0x32, 0x2e, 0x30, 0x12,
+	0x46, 0x0a, 0x06, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x12, 0x37, 0x52, 0x65, 0x74, 0x75, 0x72,
+	0x6e, 0x73, 0x20, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x20, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x20,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x20, 0x75, 0x70, 0x20, 0x74, 0x6f, 0x20, 0x31, 0x30,
+	0x20, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x20, 0x69, 0x6e, 0x20, 0x6c, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x2e, 0x32, 0x03, 0x31, 0x2e, 0x30, 0x1a, 0x0e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x68, 0x6f,
+	0x73, 0x74, 0x3a, 0x38, 0x30, 0x38, 0x30, 0x2a, 0x01, 0x01, 0x52, 0x35, 0x0a, 0x03, 0x34, 0x30,
+	0x31, 0x12, 0x2e, 0x0a, 0x0f, 0x55, 0x6e, 0x61, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x19, 0x1a, 0x17, 0x23, 0x2f, 0x64, 0x65, 0x66, 0x69,
+	0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x72, 0x70, 0x63, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x32, 0x0a, 0x03, 0x34, 0x30, 0x33, 0x12, 0x2b, 0x0a, 0x0c, 0x55, 0x6e, 0x61, 0x75,
+	0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x19, 0x1a, 0x17, 0x23, 0x2f,
+	0x64, 0x65, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x72, 0x70, 0x63, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x6a, 0x4f, 0x0a, 0x06, 0x50, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x12,
+	0x45, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x20, 0x77, 0x68, 0x69, 0x63, 0x68, 0x20, 0x70,
+	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x73, 0x20, 0x72, 0x61, 0x6e, 0x64, 0x6f, 0x6d, 0x20, 0x70,
+	0x72, 0x69, 0x6d, 0x65, 0x20, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x20, 0x75, 0x70, 0x20,
+	0x74, 0x6f, 0x20, 0x31, 0x30, 0x20, 0x64, 0x69, 0x67, 0x69, 0x74, 0x73, 0x20, 0x69, 0x6e, 0x20,
+	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x7a, 0x61, 0x6c, 0x67, 0x6f, 0x6e, 0x6f, 0x69, 0x73, 0x65, 0x2f, 0x74, 0x65,
+	0x6e, 0x64, 0x69, 0x67, 0x69, 0x74, 0x70, 0x72, 0x69, 0x6d, 0x65, 0x73, 0x2f, 0x70, 0x62, 0x3b,
+	0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -335,23 +922,43 @@ func file_primes_v1_primes_proto_rawDescGZIP() []byte {
 	return file_primes_v1_primes_proto_rawDescData
 }
 
-var file_primes_v1_primes_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
-var file_primes_v1_primes_proto_goTypes = []interface{}{
-	(*RandomRequest)(nil),  // 0: primes.v1.RandomRequest
-	(*RandomResponse)(nil), // 1: primes.v1.RandomResponse
-	(*ListRequest)(nil),    // 2: primes.v1.ListRequest
-	(*ListResponse)(nil),   // 3: primes.v1.ListResponse
+var file_primes_v1_primes_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_primes_v1_primes_proto_goTypes = []any{
+	(*RandomRequest)(nil),    // 0: primes.v1.RandomRequest
+	(*RandomResponse)(nil),   // 1: primes.v1.RandomResponse
+	(*ListRequest)(nil),      // 2: primes.v1.ListRequest
+	(*ListResponse)(nil),     // 3: primes.v1.ListResponse
+	(*IsPrimeRequest)(nil),   // 4: primes.v1.IsPrimeRequest
+	(*IsPrimeResponse)(nil),  // 5: primes.v1.IsPrimeResponse
+	(*NextRequest)(nil),      // 6: primes.v1.NextRequest
+	(*NextResponse)(nil),     // 7: primes.v1.NextResponse
+	(*PreviousRequest)(nil),  // 8: primes.v1.PreviousRequest
+	(*PreviousResponse)(nil), // 9: primes.v1.PreviousResponse
+	(*CountRequest)(nil),     // 10: primes.v1.CountRequest
+	(*CountResponse)(nil),    // 11: primes.v1.CountResponse
+	(*NthRequest)(nil),       // 12: primes.v1.NthRequest
+	(*NthResponse)(nil),      // 13: primes.v1.NthResponse
 }
 var file_primes_v1_primes_proto_depIdxs = []int32{
-	0, // 0: primes.v1.Primes.Random:input_type -> primes.v1.RandomRequest
-	2, // 1: primes.v1.Primes.List:input_type -> primes.v1.ListRequest
-	1, // 2: primes.v1.Primes.Random:output_type -> primes.v1.RandomResponse
-	3, // 3: primes.v1.Primes.List:output_type -> primes.v1.ListResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: primes.v1.Primes.Random:input_type -> primes.v1.RandomRequest
+	2,  // 1: primes.v1.Primes.List:input_type -> primes.v1.ListRequest
+	4,  // 2: primes.v1.Primes.IsPrime:input_type -> primes.v1.IsPrimeRequest
+	6,  // 3: primes.v1.Primes.Next:input_type -> primes.v1.NextRequest
+	8,  // 4: primes.v1.Primes.Previous:input_type -> primes.v1.PreviousRequest
+	10, // 5: primes.v1.Primes.Count:input_type -> primes.v1.CountRequest
+	12, // 6: primes.v1.Primes.Nth:input_type -> primes.v1.NthRequest
+	1,  // 7: primes.v1.Primes.Random:output_type -> primes.v1.RandomResponse
+	3,  // 8: primes.v1.Primes.List:output_type -> primes.v1.ListResponse
+	5,  // 9: primes.v1.Primes.IsPrime:output_type -> primes.v1.IsPrimeResponse
+	7,  // 10: primes.v1.Primes.Next:output_type -> primes.v1.NextResponse
+	9,  // 11: primes.v1.Primes.Previous:output_type -> primes.v1.PreviousResponse
+	11, // 12: primes.v1.Primes.Count:output_type -> primes.v1.CountResponse
+	13, // 13: primes.v1.Primes.Nth:output_type -> primes.v1.NthResponse
+	7,  // [7:14] is the sub-list for method output_type
+	0,  // [0:7] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_primes_v1_primes_proto_init() }
@@ -360,7 +967,7 @@ func file_primes_v1_primes_proto_init() {
 		return
 	}
 	if !protoimpl.UnsafeEnabled {
-		file_primes_v1_primes_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+		file_primes_v1_primes_proto_msgTypes[0].Exporter = func(v any, i int) any {
 			switch v := v.(*RandomRequest); i {
 			case 0:
 				return &v.state
@@ -372,7 +979,7 @@ func file_primes_v1_primes_proto_init() {
 				return nil
 			}
 		}
-		file_primes_v1_primes_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+		file_primes_v1_primes_proto_msgTypes[1].Exporter = func(v any, i int) any {
 			switch v := v.(*RandomResponse); i {
 			case 0:
 				return &v.state
@@ -384,7 +991,7 @@ func file_primes_v1_primes_proto_init() {
 				return nil
 			}
 		}
-		file_primes_v1_primes_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+		file_primes_v1_primes_proto_msgTypes[2].Exporter = func(v any, i int) any {
 			switch v := v.(*ListRequest); i {
 			case 0:
 				return &v.state
@@ -396,7 +1003,7 @@ func file_primes_v1_primes_proto_init() {
 				return nil
 			}
 		}
-		file_primes_v1_primes_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+		file_primes_v1_primes_proto_msgTypes[3].Exporter = func(v any, i int) any {
 			switch v := v.(*ListResponse); i {
 			case 0:
 				return &v.state
@@ -408,6 +1015,126 @@ func file_primes_v1_primes_proto_init() {
 				return nil
 			}
 		}
+		file_primes_v1_primes_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*IsPrimeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_primes_v1_primes_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*IsPrimeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_primes_v1_primes_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*NextRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_primes_v1_primes_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*NextResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_primes_v1_primes_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*PreviousRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_primes_v1_primes_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*PreviousResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_primes_v1_primes_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*CountRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_primes_v1_primes_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*CountResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_primes_v1_primes_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*NthRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_primes_v1_primes_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*NthResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -415,7 +1142,7 @@ func file_primes_v1_primes_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_primes_v1_primes_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -489,3 +489,1074 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = ListResponseValidationError{}
+
+// Validate checks the field values on IsPrimeRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *IsPrimeRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on IsPrimeRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in IsPrimeRequestMultiError,
+// or nil if none found.
+func (m *IsPrimeRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *IsPrimeRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if val := m.GetNumber(); val < 0 || val > 9999999999 {
+		err := IsPrimeRequestValidationError{
+			field:  "Number",
+			reason: "value must be inside range [0, 9999999999]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return IsPrimeRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// IsPrimeRequestMultiError is an error wrapping multiple validation errors
+// returned by IsPrimeRequest.ValidateAll() if the designated constraints
+// aren't met.
+type IsPrimeRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m IsPrimeRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m IsPrimeRequestMultiError) AllErrors() []error { return m }
+
+// IsPrimeRequestValidationError is the validation error returned by
+// IsPrimeRequest.Validate if the designated constraints aren't met.
+type IsPrimeRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e IsPrimeRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e IsPrimeRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e IsPrimeRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e IsPrimeRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e IsPrimeRequestValidationError) ErrorName() string { return "IsPrimeRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e IsPrimeRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sIsPrimeRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = IsPrimeRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = IsPrimeRequestValidationError{}
+
+// Validate checks the field values on IsPrimeResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *IsPrimeResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on IsPrimeResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// IsPrimeResponseMultiError, or nil if none found.
+func (m *IsPrimeResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *IsPrimeResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Prime
+
+	if len(errors) > 0 {
+		return IsPrimeResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// IsPrimeResponseMultiError is an error wrapping multiple validation errors
+// returned by IsPrimeResponse.ValidateAll() if the designated constraints
+// aren't met.
+type IsPrimeResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m IsPrimeResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m IsPrimeResponseMultiError) AllErrors() []error { return m }
+
+// IsPrimeResponseValidationError is the validation error returned by
+// IsPrimeResponse.Validate if the designated constraints aren't met.
+type IsPrimeResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e IsPrimeResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e IsPrimeResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e IsPrimeResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e IsPrimeResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e IsPrimeResponseValidationError) ErrorName() string { return "IsPrimeResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e IsPrimeResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sIsPrimeResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = IsPrimeResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = IsPrimeResponseValidationError{}
+
+// Validate checks the field values on NextRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *NextRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on NextRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in NextRequestMultiError, or
+// nil if none found.
+func (m *NextRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *NextRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if val := m.GetNumber(); val < 0 || val > 9999999999 {
+		err := NextRequestValidationError{
+			field:  "Number",
+			reason: "value must be inside range [0, 9999999999]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return NextRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// NextRequestMultiError is an error wrapping multiple validation errors
+// returned by NextRequest.ValidateAll() if the designated constraints aren't met.
+type NextRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m NextRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m NextRequestMultiError) AllErrors() []error { return m }
+
+// NextRequestValidationError is the validation error returned by
+// NextRequest.Validate if the designated constraints aren't met.
+type NextRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e NextRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e NextRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e NextRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e NextRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e NextRequestValidationError) ErrorName() string { return "NextRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e NextRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sNextRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = NextRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = NextRequestValidationError{}
+
+// Validate checks the field values on NextResponse with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *NextResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on NextResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in NextResponseMultiError, or
+// nil if none found.
+func (m *NextResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *NextResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Prime
+
+	if len(errors) > 0 {
+		return NextResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// NextResponseMultiError is an error wrapping multiple validation errors
+// returned by NextResponse.ValidateAll() if the designated constraints aren't met.
+type NextResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m NextResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m NextResponseMultiError) AllErrors() []error { return m }
+
+// NextResponseValidationError is the validation error returned by
+// NextResponse.Validate if the designated constraints aren't met.
+type NextResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e NextResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e NextResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e NextResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e NextResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e NextResponseValidationError) ErrorName() string { return "NextResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e NextResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sNextResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = NextResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = NextResponseValidationError{}
+
+// Validate checks the field values on PreviousRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *PreviousRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on PreviousRequest with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// PreviousRequestMultiError, or nil if none found.
+func (m *PreviousRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *PreviousRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if val := m.GetNumber(); val < 0 || val > 9999999999 {
+		err := PreviousRequestValidationError{
+			field:  "Number",
+			reason: "value must be inside range [0, 9999999999]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return PreviousRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// PreviousRequestMultiError is an error wrapping multiple validation errors
+// returned by PreviousRequest.ValidateAll() if the designated constraints
+// aren't met.
+type PreviousRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PreviousRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PreviousRequestMultiError) AllErrors() []error { return m }
+
+// PreviousRequestValidationError is the validation error returned by
+// PreviousRequest.Validate if the designated constraints aren't met.
+type PreviousRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PreviousRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PreviousRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PreviousRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PreviousRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PreviousRequestValidationError) ErrorName() string { return "PreviousRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e PreviousRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPreviousRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PreviousRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PreviousRequestValidationError{}
+
+// Validate checks the field values on PreviousResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *PreviousResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on PreviousResponse with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// PreviousResponseMultiError, or nil if none found.
+func (m *PreviousResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *PreviousResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Prime
+
+	if len(errors) > 0 {
+		return PreviousResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// PreviousResponseMultiError is an error wrapping multiple validation errors
+// returned by PreviousResponse.ValidateAll() if the designated constraints
+// aren't met.
+type PreviousResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m PreviousResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m PreviousResponseMultiError) AllErrors() []error { return m }
+
+// PreviousResponseValidationError is the validation error returned by
+// PreviousResponse.Validate if the designated constraints aren't met.
+type PreviousResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e PreviousResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e PreviousResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e PreviousResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e PreviousResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e PreviousResponseValidationError) ErrorName() string { return "PreviousResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e PreviousResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sPreviousResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = PreviousResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = PreviousResponseValidationError{}
+
+// Validate checks the field values on CountRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *CountRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CountRequest with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in CountRequestMultiError, or
+// nil if none found.
+func (m *CountRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CountRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetMin() < 2 {
+		err := CountRequestValidationError{
+			field:  "Min",
+			reason: "value must be greater than or equal to 2",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetMax() > 9999999999 {
+		err := CountRequestValidationError{
+			field:  "Max",
+			reason: "value must be less than or equal to 9999999999",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return CountRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// CountRequestMultiError is an error wrapping multiple validation errors
+// returned by CountRequest.ValidateAll() if the designated constraints aren't met.
+type CountRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CountRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CountRequestMultiError) AllErrors() []error { return m }
+
+// CountRequestValidationError is the validation error returned by
+// CountRequest.Validate if the designated constraints aren't met.
+type CountRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CountRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CountRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CountRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CountRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CountRequestValidationError) ErrorName() string { return "CountRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CountRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCountRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CountRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CountRequestValidationError{}
+
+// Validate checks the field values on CountResponse with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *CountResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on CountResponse with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in CountResponseMultiError, or
+// nil if none found.
+func (m *CountResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *CountResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Count
+
+	if len(errors) > 0 {
+		return CountResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// CountResponseMultiError is an error wrapping multiple validation errors
+// returned by CountResponse.ValidateAll() if the designated constraints
+// aren't met.
+type CountResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m CountResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m CountResponseMultiError) AllErrors() []error { return m }
+
+// CountResponseValidationError is the validation error returned by
+// CountResponse.Validate if the designated constraints aren't met.
+type CountResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e CountResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e CountResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e CountResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e CountResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e CountResponseValidationError) ErrorName() string { return "CountResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e CountResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sCountResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = CountResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = CountResponseValidationError{}
+
+// Validate checks the field values on NthRequest with the rules defined in the
+// proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *NthRequest) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on NthRequest with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in NthRequestMultiError, or
+// nil if none found.
+func (m *NthRequest) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *NthRequest) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if m.GetN() < 1 {
+		err := NthRequestValidationError{
+			field:  "N",
+			reason: "value must be greater than or equal to 1",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return NthRequestMultiError(errors)
+	}
+
+	return nil
+}
+
+// NthRequestMultiError is an error wrapping multiple validation errors
+// returned by NthRequest.ValidateAll() if the designated constraints aren't met.
+type NthRequestMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m NthRequestMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m NthRequestMultiError) AllErrors() []error { return m }
+
+// NthRequestValidationError is the validation error returned by
+// NthRequest.Validate if the designated constraints aren't met.
+type NthRequestValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e NthRequestValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e NthRequestValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e NthRequestValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e NthRequestValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e NthRequestValidationError) ErrorName() string { return "NthRequestValidationError" }
+
+// Error satisfies the builtin error interface
+func (e NthRequestValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sNthRequest.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = NthRequestValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = NthRequestValidationError{}
+
+// Validate checks the field values on NthResponse with the rules defined in
+// the proto definition for this message. If any rules are violated, the first
+// error encountered is returned, or nil if there are no violations.
+func (m *NthResponse) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on NthResponse with the rules defined in
+// the proto definition for this message. If any rules are violated, the
+// result is a list of violation errors wrapped in NthResponseMultiError, or
+// nil if none found.
+func (m *NthResponse) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *NthResponse) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for Prime
+
+	if len(errors) > 0 {
+		return NthResponseMultiError(errors)
+	}
+
+	return nil
+}
+
+// NthResponseMultiError is an error wrapping multiple validation errors
+// returned by NthResponse.ValidateAll() if the designated constraints aren't met.
+type NthResponseMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m NthResponseMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m NthResponseMultiError) AllErrors() []error { return m }
+
+// NthResponseValidationError is the validation error returned by
+// NthResponse.Validate if the designated constraints aren't met.
+type NthResponseValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e NthResponseValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e NthResponseValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e NthResponseValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e NthResponseValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e NthResponseValidationError) ErrorName() string { return "NthResponseValidationError" }
+
+// Error satisfies the builtin error interface
+func (e NthResponseValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sNthResponse.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = NthResponseValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = NthResponseValidationError{}
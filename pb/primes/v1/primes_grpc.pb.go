@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
 // versions:
-// - protoc-gen-go-grpc v1.3.0
+// - protoc-gen-go-grpc v1.4.0
 // - protoc             (unknown)
 // source: primes/v1/primes.proto
 
@@ -15,12 +15,17 @@ import (
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-// Requires gRPC-Go v1.32.0 or later.
-const _ = grpc.SupportPackageIsVersion7
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
 
 const (
-	Primes_Random_FullMethodName = "/primes.v1.Primes/Random"
-	Primes_List_FullMethodName   = "/primes.v1.Primes/List"
+	Primes_Random_FullMethodName   = "/primes.v1.Primes/Random"
+	Primes_List_FullMethodName     = "/primes.v1.Primes/List"
+	Primes_IsPrime_FullMethodName  = "/primes.v1.Primes/IsPrime"
+	Primes_Next_FullMethodName     = "/primes.v1.Primes/Next"
+	Primes_Previous_FullMethodName = "/primes.v1.Primes/Previous"
+	Primes_Count_FullMethodName    = "/primes.v1.Primes/Count"
+	Primes_Nth_FullMethodName      = "/primes.v1.Primes/Nth"
 )
 
 // PrimesClient is the client API for Primes service.
@@ -29,6 +34,11 @@ const (
 type PrimesClient interface {
 	Random(ctx context.Context, in *RandomRequest, opts ...grpc.CallOption) (*RandomResponse, error)
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+	IsPrime(ctx context.Context, in *IsPrimeRequest, opts ...grpc.CallOption) (*IsPrimeResponse, error)
+	Next(ctx context.Context, in *NextRequest, opts ...grpc.CallOption) (*NextResponse, error)
+	Previous(ctx context.Context, in *PreviousRequest, opts ...grpc.CallOption) (*PreviousResponse, error)
+	Count(ctx context.Context, in *CountRequest, opts ...grpc.CallOption) (*CountResponse, error)
+	Nth(ctx context.Context, in *NthRequest, opts ...grpc.CallOption) (*NthResponse, error)
 }
 
 type primesClient struct {
@@ -40,8 +50,9 @@ func NewPrimesClient(cc grpc.ClientConnInterface) PrimesClient {
 }
 
 func (c *primesClient) Random(ctx context.Context, in *RandomRequest, opts ...grpc.CallOption) (*RandomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RandomResponse)
-	err := c.cc.Invoke(ctx, Primes_Random_FullMethodName, in, out, opts...)
+	err := c.cc.Invoke(ctx, Primes_Random_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -49,8 +60,59 @@ func (c *primesClient) Random(ctx context.Context, in *RandomRequest, opts ...gr
 }
 
 func (c *primesClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListResponse)
-	err := c.cc.Invoke(ctx, Primes_List_FullMethodName, in, out, opts...)
+	err := c.cc.Invoke(ctx, Primes_List_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *primesClient) IsPrime(ctx context.Context, in *IsPrimeRequest, opts ...grpc.CallOption) (*IsPrimeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IsPrimeResponse)
+	err := c.cc.Invoke(ctx, Primes_IsPrime_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *primesClient) Next(ctx context.Context, in *NextRequest, opts ...grpc.CallOption) (*NextResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NextResponse)
+	err := c.cc.Invoke(ctx, Primes_Next_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *primesClient) Previous(ctx context.Context, in *PreviousRequest, opts ...grpc.CallOption) (*PreviousResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PreviousResponse)
+	err := c.cc.Invoke(ctx, Primes_Previous_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *primesClient) Count(ctx context.Context, in *CountRequest, opts ...grpc.CallOption) (*CountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountResponse)
+	err := c.cc.Invoke(ctx, Primes_Count_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *primesClient) Nth(ctx context.Context, in *NthRequest, opts ...grpc.CallOption) (*NthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NthResponse)
+	err := c.cc.Invoke(ctx, Primes_Nth_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +125,11 @@ func (c *primesClient) List(ctx context.Context, in *ListRequest, opts ...grpc.C
 type PrimesServer interface {
 	Random(context.Context, *RandomRequest) (*RandomResponse, error)
 	List(context.Context, *ListRequest) (*ListResponse, error)
+	IsPrime(context.Context, *IsPrimeRequest) (*IsPrimeResponse, error)
+	Next(context.Context, *NextRequest) (*NextResponse, error)
+	Previous(context.Context, *PreviousRequest) (*PreviousResponse, error)
+	Count(context.Context, *CountRequest) (*CountResponse, error)
+	Nth(context.Context, *NthRequest) (*NthResponse, error)
 	mustEmbedUnimplementedPrimesServer()
 }
 
@@ -76,6 +143,21 @@ func (UnimplementedPrimesServer) Random(context.Context, *RandomRequest) (*Rando
 func (UnimplementedPrimesServer) List(context.Context, *ListRequest) (*ListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedPrimesServer) IsPrime(context.Context, *IsPrimeRequest) (*IsPrimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsPrime not implemented")
+}
+func (UnimplementedPrimesServer) Next(context.Context, *NextRequest) (*NextResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Next not implemented")
+}
+func (UnimplementedPrimesServer) Previous(context.Context, *PreviousRequest) (*PreviousResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Previous not implemented")
+}
+func (UnimplementedPrimesServer) Count(context.Context, *CountRequest) (*CountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Count not implemented")
+}
+func (UnimplementedPrimesServer) Nth(context.Context, *NthRequest) (*NthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Nth not implemented")
+}
 func (UnimplementedPrimesServer) mustEmbedUnimplementedPrimesServer() {}
 
 // UnsafePrimesServer may be embedded to opt out of forward compatibility for this service.
@@ -125,6 +207,96 @@ func _Primes_List_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Primes_IsPrime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsPrimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrimesServer).IsPrime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Primes_IsPrime_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrimesServer).IsPrime(ctx, req.(*IsPrimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Primes_Next_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrimesServer).Next(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Primes_Next_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrimesServer).Next(ctx, req.(*NextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Primes_Previous_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviousRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrimesServer).Previous(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Primes_Previous_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrimesServer).Previous(ctx, req.(*PreviousRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Primes_Count_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrimesServer).Count(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Primes_Count_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrimesServer).Count(ctx, req.(*CountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Primes_Nth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrimesServer).Nth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Primes_Nth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrimesServer).Nth(ctx, req.(*NthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Primes_ServiceDesc is the grpc.ServiceDesc for Primes service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -140,6 +312,26 @@ var Primes_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "List",
 			Handler:    _Primes_List_Handler,
 		},
+		{
+			MethodName: "IsPrime",
+			Handler:    _Primes_IsPrime_Handler,
+		},
+		{
+			MethodName: "Next",
+			Handler:    _Primes_Next_Handler,
+		},
+		{
+			MethodName: "Previous",
+			Handler:    _Primes_Previous_Handler,
+		},
+		{
+			MethodName: "Count",
+			Handler:    _Primes_Count_Handler,
+		},
+		{
+			MethodName: "Nth",
+			Handler:    _Primes_Nth_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "primes/v1/primes.proto",
@@ -2,6 +2,7 @@ package primes
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"strconv"
 	"time"
@@ -12,12 +13,28 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// The repositories wrap these errors for lookups that are not server errors, to be mapped to a gRPC status.
+var (
+	ErrNotFound    = errors.New("prime number not found")
+	ErrOutOfRange  = errors.New("out of range")
+	ErrUnsupported = errors.New("unsupported")
+)
+
 type Repository interface {
 	Random(ctx context.Context, min, max int64) (int64, error)
 	List(ctx context.Context, min, max, limit int64) ([]int64, error)
 	Close() error
 }
 
+// Lookup describes deterministic queries on the dataset, which are served from a cache.
+type Lookup interface {
+	IsPrime(ctx context.Context, n int64) (bool, error)
+	Next(ctx context.Context, n int64) (int64, error)
+	Previous(ctx context.Context, n int64) (int64, error)
+	Count(ctx context.Context, min, max int64) (int64, error)
+	Nth(ctx context.Context, n int64) (int64, error)
+}
+
 type Metrics interface {
 	RegisterCollector(collector prometheus.Collector)
 
@@ -30,7 +47,8 @@ type Metrics interface {
 type Service struct {
 	pb.UnimplementedPrimesServer
 
-	repo Repository
+	repo   Repository
+	lookup Lookup
 
 	m      Metrics
 	logger *slog.Logger
@@ -110,9 +128,172 @@ func (s Service) List(ctx context.Context, req *pb.ListRequest) (*pb.ListRespons
 	return &pb.ListResponse{Primes: primes}, nil
 }
 
-func NewService(repo Repository, logger *slog.Logger, m Metrics) Service {
+func (s Service) IsPrime(ctx context.Context, req *pb.IsPrimeRequest) (*pb.IsPrimeResponse, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	r := s.received(req.Number, req.Number)
+
+	ok, err := s.lookup.IsPrime(ctx, req.Number)
+	s.completed(ctx, r, err)
+
+	if err != nil {
+		return nil, s.lookupError(ctx, "is_prime", err)
+	}
+
+	return &pb.IsPrimeResponse{Prime: ok}, nil
+}
+
+func (s Service) Next(ctx context.Context, req *pb.NextRequest) (*pb.NextResponse, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	r := s.received(req.Number, req.Number)
+
+	prime, err := s.lookup.Next(ctx, req.Number)
+	s.completed(ctx, r, err)
+
+	if err != nil {
+		return nil, s.lookupError(ctx, "next", err)
+	}
+
+	return &pb.NextResponse{Prime: prime}, nil
+}
+
+func (s Service) Previous(ctx context.Context, req *pb.PreviousRequest) (*pb.PreviousResponse, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	r := s.received(req.Number, req.Number)
+
+	prime, err := s.lookup.Previous(ctx, req.Number)
+	s.completed(ctx, r, err)
+
+	if err != nil {
+		return nil, s.lookupError(ctx, "previous", err)
+	}
+
+	return &pb.PreviousResponse{Prime: prime}, nil
+}
+
+func (s Service) Count(ctx context.Context, req *pb.CountRequest) (*pb.CountResponse, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	r := s.received(req.Min, req.Max)
+
+	count, err := s.lookup.Count(ctx, req.Min, req.Max)
+	s.completed(ctx, r, err)
+
+	if err != nil {
+		return nil, s.lookupError(ctx, "count", err)
+	}
+
+	return &pb.CountResponse{Count: count}, nil
+}
+
+func (s Service) Nth(ctx context.Context, req *pb.NthRequest) (*pb.NthResponse, error) {
+	if err := s.validate(ctx, req); err != nil {
+		return nil, err
+	}
+
+	r := s.received(req.N, req.N)
+
+	prime, err := s.lookup.Nth(ctx, req.N)
+	s.completed(ctx, r, err)
+
+	if err != nil {
+		return nil, s.lookupError(ctx, "nth", err)
+	}
+
+	return &pb.NthResponse{Prime: prime}, nil
+}
+
+func (s Service) validate(ctx context.Context, req interface{ Validate() error }) error {
+	if err := req.Validate(); err != nil {
+		s.logger.WarnContext(ctx, "invalid request",
+			slog.Any("request", req),
+			slog.String("error", err.Error()),
+		)
+
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if s.lookup == nil {
+		return status.Error(codes.Unimplemented, "lookups are not supported")
+	}
+
+	return nil
+}
+
+// request holds the metrics labels of a lookup, for the range or number it covers, along with its start time.
+type request struct {
+	minimum string
+	maximum string
+	start   time.Time
+}
+
+// received counts a lookup covering [minimum, maximum] as received, as Random and List do for their range.
+func (s Service) received(minimum, maximum int64) request {
+	r := request{
+		minimum: strconv.FormatInt(minimum, 10),
+		maximum: strconv.FormatInt(maximum, 10),
+		start:   time.Now(),
+	}
+
+	s.m.IncRequestsReceivedTotal(r.minimum, r.maximum)
+
+	return r
+}
+
+// completed observes the latency of lookup r, counting it as errored if err is not nil.
+func (s Service) completed(ctx context.Context, r request, err error) {
+	if err != nil {
+		s.m.IncRequestsReceivedErrored(r.minimum, r.maximum)
+	}
+
+	s.m.ObserveRequestLatency(ctx, r.minimum, r.maximum, time.Since(r.start))
+}
+
+// lookupError maps the errors of the repositories' lookups to a gRPC status, as a number that is not in the dataset
+// is not a server error.
+func (s Service) lookupError(ctx context.Context, op string, err error) error {
+	var code codes.Code
+
+	switch {
+	case errors.Is(err, ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, ErrOutOfRange):
+		code = codes.OutOfRange
+	case errors.Is(err, ErrUnsupported):
+		code = codes.Unimplemented
+	default:
+		s.logger.ErrorContext(ctx, "failed to look up prime number",
+			slog.String("op", op),
+			slog.String("error", err.Error()),
+		)
+
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	s.logger.DebugContext(ctx, "rejected lookup",
+		slog.String("op", op),
+		slog.String("error", err.Error()),
+	)
+
+	return status.Error(code, err.Error())
+}
+
+// NewService creates a Service serving random primes from repo, and lookups from lookup. If lookup is nil, lookups
+// return an Unimplemented status.
+func NewService(repo Repository, lookup Lookup, logger *slog.Logger, m Metrics) Service {
 	return Service{
 		repo:   repo,
+		lookup: lookup,
 		logger: logger,
 		m:      m,
 	}
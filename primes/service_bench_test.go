@@ -1,6 +1,6 @@
 //go:build bench
 
-package primes
+package primes_test
 
 import (
 	"context"
@@ -12,6 +12,7 @@ import (
 	"github.com/zalgonoise/tendigitprimes/log"
 	"github.com/zalgonoise/tendigitprimes/metrics"
 	pb "github.com/zalgonoise/tendigitprimes/pb/primes/v1"
+	"github.com/zalgonoise/tendigitprimes/primes"
 	"github.com/zalgonoise/tendigitprimes/repository/sqlite"
 )
 
@@ -31,13 +32,13 @@ func BenchmarkService(b *testing.B) {
 	logger := log.New(c.LogLevel)
 	m := metrics.Noop{}
 
-	db, err := database.AttachSQLite(c.Database.URI, database.ReadOnlyPragmas(), logger)
+	db, err := database.AttachSQLite(c.Database.URI, database.ReadOnlyPragmas(), database.VerifySampled, logger)
 	require.NoError(b, err)
 
 	repo, err := sqlite.NewPartitionSet(db)
 	require.NoError(b, err)
 
-	service := NewService(repo, logger, m)
+	service := primes.NewService(repo, repo, logger, m)
 
 	ctx := context.Background()
 	logger.InfoContext(ctx, "service is ready")
@@ -0,0 +1,134 @@
+package primes_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/metrics"
+	pb "github.com/zalgonoise/tendigitprimes/pb/primes/v1"
+	"github.com/zalgonoise/tendigitprimes/primes"
+	"github.com/zalgonoise/tendigitprimes/repository/cache"
+	"github.com/zalgonoise/tendigitprimes/repository/packed"
+	"github.com/zalgonoise/tendigitprimes/repository/postgres"
+	"github.com/zalgonoise/tendigitprimes/repository/reload"
+	"github.com/zalgonoise/tendigitprimes/repository/sieve"
+	"github.com/zalgonoise/tendigitprimes/repository/sqlite"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errLookup is a primes.Lookup that fails every lookup with err.
+type errLookup struct {
+	err error
+}
+
+func (l errLookup) IsPrime(context.Context, int64) (bool, error)             { return false, l.err }
+func (l errLookup) Next(context.Context, int64) (int64, error)               { return 0, l.err }
+func (l errLookup) Previous(context.Context, int64) (int64, error)           { return 0, l.err }
+func (l errLookup) Count(context.Context, int64, int64) (int64, error)       { return 0, l.err }
+func (l errLookup) Nth(context.Context, int64) (int64, error)                { return 0, l.err }
+func (errLookup) Random(context.Context, int64, int64) (int64, error)        { return 2, nil }
+func (errLookup) List(context.Context, int64, int64, int64) ([]int64, error) { return nil, nil }
+func (errLookup) Close() error                                               { return nil }
+
+// testMetrics records the request metrics, keyed by their labels.
+type testMetrics struct {
+	mu        sync.Mutex
+	received  map[string]int
+	errored   map[string]int
+	latencies map[string]int
+}
+
+func newTestMetrics() *testMetrics {
+	return &testMetrics{received: map[string]int{}, errored: map[string]int{}, latencies: map[string]int{}}
+}
+
+func (m *testMetrics) RegisterCollector(prometheus.Collector) {}
+func (m *testMetrics) InitRequestsMetrics(string, string)     {}
+
+func (m *testMetrics) IncRequestsReceivedTotal(minimum, maximum string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.received[minimum+":"+maximum]++
+}
+
+func (m *testMetrics) IncRequestsReceivedErrored(minimum, maximum string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.errored[minimum+":"+maximum]++
+}
+
+func (m *testMetrics) ObserveRequestLatency(_ context.Context, minimum, maximum string, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.latencies[minimum+":"+maximum]++
+}
+
+func TestService_LookupMetrics(t *testing.T) {
+	ctx := context.Background()
+	m := newTestMetrics()
+
+	service := primes.NewService(errLookup{}, errLookup{}, log.NoOp(), m)
+
+	_, err := service.IsPrime(ctx, &pb.IsPrimeRequest{Number: 7})
+	require.NoError(t, err)
+	_, err = service.Next(ctx, &pb.NextRequest{Number: 7})
+	require.NoError(t, err)
+	_, err = service.Previous(ctx, &pb.PreviousRequest{Number: 11})
+	require.NoError(t, err)
+	_, err = service.Count(ctx, &pb.CountRequest{Min: 2, Max: 100})
+	require.NoError(t, err)
+	_, err = service.Nth(ctx, &pb.NthRequest{N: 4})
+	require.NoError(t, err)
+
+	failing := primes.NewService(errLookup{}, errLookup{err: sqlite.ErrNotFound}, log.NoOp(), m)
+
+	_, err = failing.Next(ctx, &pb.NextRequest{Number: 7})
+	require.Error(t, err)
+
+	require.Equal(t, map[string]int{"7:7": 3, "11:11": 1, "2:100": 1, "4:4": 1}, m.received)
+	require.Equal(t, map[string]int{"7:7": 1}, m.errored)
+	require.Equal(t, m.received, m.latencies)
+}
+
+func TestService_LookupErrors(t *testing.T) {
+	ctx := context.Background()
+
+	for _, testcase := range []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{name: "SQLiteNotFound", err: sqlite.ErrNotFound, code: codes.NotFound},
+		{name: "SQLiteOutOfBounds", err: sqlite.ErrOutOfBounds, code: codes.OutOfRange},
+		{name: "PackedNotFound", err: packed.ErrNoPrimesInRange, code: codes.NotFound},
+		{name: "PackedOutOfBounds", err: packed.ErrOutOfBounds, code: codes.OutOfRange},
+		{name: "SieveNotFound", err: sieve.ErrNoPrimesInRange, code: codes.NotFound},
+		{name: "SieveOutOfBounds", err: sieve.ErrOutOfBounds, code: codes.OutOfRange},
+		{name: "PostgresNotFound", err: postgres.ErrNoPrimesInRange, code: codes.NotFound},
+		{name: "PostgresOutOfBounds", err: postgres.ErrOutOfBounds, code: codes.OutOfRange},
+		{name: "CacheUnsupported", err: cache.ErrUnsupported, code: codes.Unimplemented},
+		{name: "ReloadUnsupported", err: reload.ErrUnsupported, code: codes.Unimplemented},
+		{name: "Wrapped", err: errors.Join(errors.New("primary failed"), fmt.Errorf("%w: 7", sieve.ErrOutOfBounds)),
+			code: codes.OutOfRange},
+		{name: "Internal", err: errors.New("database is locked"), code: codes.Internal},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			l := errLookup{err: fmt.Errorf("%w: 42", testcase.err)}
+			service := primes.NewService(l, l, log.NoOp(), metrics.Noop{})
+
+			_, err := service.Next(ctx, &pb.NextRequest{Number: 42})
+			require.Equal(t, testcase.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/x509"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/zalgonoise/tendigitprimes/apikeys"
+)
+
+// gatewayMetadataKey holds the Limiter's token on the gateway's calls to the gRPC server.
+const gatewayMetadataKey = "x-ratelimit-gateway"
+
+// UnaryServerInterceptor limits unary calls, except for the ones from the gateway. It must follow the authentication
+// interceptor, to limit calls by API key.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := l.limitCall(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor limits the opening of streams, except for the ones from the gateway. It must follow the
+// authentication interceptor, to limit calls by API key.
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.limitCall(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// Middleware returns an HTTP middleware limiting the requests on the input routes, which map URL paths to their gRPC
+// method. Other paths are not limited. It must follow the authentication middleware, to limit requests by API key.
+func (l *Limiter) Middleware(routes map[string]string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, ok := routes[r.URL.Path]
+			if !ok {
+				h.ServeHTTP(w, r)
+
+				return
+			}
+
+			client := requestClient(r)
+
+			allowed, wait := l.Allow(client, method)
+			if !allowed {
+				l.reject(r.Context(), client, method, wait)
+				reject(w, wait)
+
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GatewayCredentials returns the credentials for the gateway's connection to the gRPC server, so that its calls are
+// not limited again, for the gateway itself.
+func (l *Limiter) GatewayCredentials() credentials.PerRPCCredentials {
+	return gatewayCredentials(l.token)
+}
+
+type gatewayCredentials string
+
+func (c gatewayCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{gatewayMetadataKey: string(c)}, nil
+}
+
+func (c gatewayCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+func (l *Limiter) limitCall(ctx context.Context, method string) error {
+	if l.fromGateway(ctx) {
+		return nil
+	}
+
+	client := contextClient(ctx)
+
+	allowed, wait := l.Allow(client, method)
+	if allowed {
+		return nil
+	}
+
+	l.reject(ctx, client, method, wait)
+
+	return exhausted(wait).Err()
+}
+
+func (l *Limiter) fromGateway(ctx context.Context) bool {
+	for _, token := range metadata.ValueFromIncomingContext(ctx, gatewayMetadataKey) {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(l.token)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contextClient(ctx context.Context) string {
+	if key, ok := apikeys.KeyFrom(ctx); ok {
+		return "key:" + key.ID
+	}
+
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.PeerCertificates) > 0 {
+		return certClient(info.State.PeerCertificates[0])
+	}
+
+	return ipClient(p.Addr.String())
+}
+
+func requestClient(r *http.Request) string {
+	if key, ok := apikeys.KeyFrom(r.Context()); ok {
+		return "key:" + key.ID
+	}
+
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		return certClient(r.TLS.PeerCertificates[0])
+	}
+
+	return ipClient(r.RemoteAddr)
+}
+
+func certClient(cert *x509.Certificate) string {
+	return "cert:" + cert.Subject.String()
+}
+
+func ipClient(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	return "ip:" + host
+}
+
+// exhausted returns a RESOURCE_EXHAUSTED status, with the time to wait before retrying as its details.
+func exhausted(wait time.Duration) *status.Status {
+	s := status.New(codes.ResourceExhausted, "rate limit exceeded")
+
+	if detailed, err := s.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)}); err == nil {
+		return detailed
+	}
+
+	return s
+}
+
+func reject(w http.ResponseWriter, wait time.Duration) {
+	s := exhausted(wait)
+	code := runtime.HTTPStatusFromCode(s.Code())
+
+	// Retry-After only takes whole seconds, so waits are rounded up to retry once a token is available
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+
+	body, err := protojson.Marshal(s.Proto())
+	if err != nil {
+		http.Error(w, s.Message(), code)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(body)
+}
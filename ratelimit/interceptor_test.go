@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/zalgonoise/tendigitprimes/apikeys"
+)
+
+func TestMiddleware(t *testing.T) {
+	l, _ := newTestLimiter(t, Limits{"List": {Rate: 0.5, Burst: 1}})
+
+	handler := l.Middleware(map[string]string{"/v1/primes": "/primes.v1.Primes/List"})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	serve := func(path, remoteAddr string, key *apikeys.Key) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		r.RemoteAddr = remoteAddr
+
+		if key != nil {
+			r = r.WithContext(apikeys.WithKey(r.Context(), *key))
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		return w
+	}
+
+	require.Equal(t, http.StatusOK, serve("/v1/primes", "10.0.0.1:1234", nil).Code)
+
+	w := serve("/v1/primes", "10.0.0.1:5678", nil)
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "2", w.Header().Get("Retry-After"))
+
+	// the same address with an API key, another address, and paths without a route are limited separately
+	require.Equal(t, http.StatusOK, serve("/v1/primes", "10.0.0.1:1234", &apikeys.Key{ID: "a"}).Code)
+	require.Equal(t, http.StatusOK, serve("/v1/primes", "10.0.0.2:1234", nil).Code)
+	require.Equal(t, http.StatusOK, serve("/ready", "10.0.0.1:1234", nil).Code)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	l, _ := newTestLimiter(t, Limits{"Random": {Rate: 1, Burst: 1}})
+
+	interceptor := l.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/primes.v1.Primes/Random"}
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234},
+	})
+
+	_, err := interceptor(ctx, nil, info, handler)
+	require.NoError(t, err)
+
+	_, err = interceptor(ctx, nil, info, handler)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	require.Equal(t, int64(1), details[0].(*errdetails.RetryInfo).GetRetryDelay().GetSeconds())
+
+	t.Run("Gateway", func(t *testing.T) {
+		md, err := l.GatewayCredentials().GetRequestMetadata(ctx)
+		require.NoError(t, err)
+
+		_, err = interceptor(metadata.NewIncomingContext(ctx, metadata.New(md)), nil, info, handler)
+		require.NoError(t, err)
+
+		_, err = interceptor(metadata.NewIncomingContext(ctx, metadata.Pairs(gatewayMetadataKey, "guess")), nil, info,
+			handler)
+		require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
@@ -0,0 +1,163 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"path"
+	"sync"
+	"time"
+)
+
+const (
+	// Any sets the default Limit, for the methods without one of their own.
+	Any = "*"
+
+	tokenSize = 16
+
+	// sweepInterval is how often the buckets that refilled are dropped, so that clients which stopped calling are not
+	// kept around.
+	sweepInterval = time.Minute
+)
+
+var ErrInvalidLimit = errors.New("invalid rate limit")
+
+// Limit is a token bucket, refilled at Rate tokens per second, up to Burst tokens. Each call takes one token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Limits sets a Limit per RPC, by its method name, as in `List` for `/primes.v1.Primes/List`. Methods without a Limit
+// use the one set for Any, or are not limited if none is set.
+type Limits map[string]Limit
+
+type Metrics interface {
+	IncRateLimited(method string)
+}
+
+type key struct {
+	client string
+	method string
+}
+
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens earned since the bucket was last used, returning true if it is full.
+func (b *bucket) refill(now time.Time) bool {
+	b.tokens = min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate)
+	b.last = now
+
+	return b.tokens >= float64(b.limit.Burst)
+}
+
+// Limiter keeps a token bucket per client and method. Clients are identified by their API key, their certificate's
+// subject with mutual TLS, or their IP address, in this order.
+type Limiter struct {
+	limits Limits
+	m      Metrics
+	logger *slog.Logger
+
+	// token marks the gateway's calls to the gRPC server, as they are limited by the HTTP middleware instead
+	token string
+
+	mu      sync.Mutex
+	buckets map[key]*bucket
+	swept   time.Time
+	now     func() time.Time
+}
+
+func New(limits Limits, m Metrics, logger *slog.Logger) (*Limiter, error) {
+	for method, limit := range limits {
+		if !(limit.Rate > 0) || math.IsInf(limit.Rate, 0) || limit.Burst < 1 {
+			return nil, fmt.Errorf("%w: %q: rate and burst must be positive and finite", ErrInvalidLimit, method)
+		}
+	}
+
+	token := make([]byte, tokenSize)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	return &Limiter{
+		limits:  limits,
+		m:       m,
+		logger:  logger,
+		token:   hex.EncodeToString(token),
+		buckets: make(map[key]*bucket),
+		swept:   time.Now(),
+		now:     time.Now,
+	}, nil
+}
+
+// Allow takes a token from the client's bucket for the input method, which may be a full gRPC method name. If the
+// bucket is empty, it returns false along with how long until a token is available.
+func (l *Limiter) Allow(client, method string) (bool, time.Duration) {
+	method = path.Base(method)
+
+	limit, ok := l.limits[method]
+	if !ok {
+		if limit, ok = l.limits[Any]; !ok {
+			return true, 0
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	k := key{client: client, method: method}
+
+	b, ok := l.buckets[k]
+	if !ok {
+		b = &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		l.buckets[k] = b
+	}
+
+	b.refill(now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+
+		return true, 0
+	}
+
+	return false, time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+}
+
+// sweep drops the full buckets, as they are the same as new ones.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < sweepInterval {
+		return
+	}
+
+	l.swept = now
+
+	for k, b := range l.buckets {
+		if b.refill(now) {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// reject counts and logs a rejected call.
+func (l *Limiter) reject(ctx context.Context, client, method string, wait time.Duration) {
+	method = path.Base(method)
+
+	l.m.IncRateLimited(method)
+	l.logger.DebugContext(ctx, "rate limited call",
+		slog.String("client", client),
+		slog.String("method", method),
+		slog.Duration("retry_after", wait),
+	)
+}
@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/metrics"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newTestLimiter(t *testing.T, limits Limits) (*Limiter, *clock) {
+	l, err := New(limits, metrics.Noop{}, log.NoOp())
+	require.NoError(t, err)
+
+	c := &clock{now: time.Unix(0, 0)}
+	l.now = c.Now
+	l.swept = c.now
+
+	return l, c
+}
+
+func TestNew(t *testing.T) {
+	for _, testcase := range []struct {
+		name   string
+		limits Limits
+		err    error
+	}{
+		{
+			name:   "Valid",
+			limits: Limits{"List": {Rate: 0.5, Burst: 1}, Any: {Rate: 10, Burst: 20}},
+		},
+		{
+			name:   "ZeroRate",
+			limits: Limits{"List": {Rate: 0, Burst: 1}},
+			err:    ErrInvalidLimit,
+		},
+		{
+			name:   "NaNRate",
+			limits: Limits{"List": {Rate: math.NaN(), Burst: 1}},
+			err:    ErrInvalidLimit,
+		},
+		{
+			name:   "InfiniteRate",
+			limits: Limits{"List": {Rate: math.Inf(1), Burst: 1}},
+			err:    ErrInvalidLimit,
+		},
+		{
+			name:   "ZeroBurst",
+			limits: Limits{"List": {Rate: 1, Burst: 0}},
+			err:    ErrInvalidLimit,
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			_, err := New(testcase.limits, metrics.Noop{}, log.NoOp())
+			require.ErrorIs(t, err, testcase.err)
+		})
+	}
+}
+
+func TestAllow(t *testing.T) {
+	t.Run("Burst", func(t *testing.T) {
+		l, c := newTestLimiter(t, Limits{"List": {Rate: 2, Burst: 3}})
+
+		for range 3 {
+			allowed, _ := l.Allow("ip:10.0.0.1", "/primes.v1.Primes/List")
+			require.True(t, allowed)
+		}
+
+		allowed, wait := l.Allow("ip:10.0.0.1", "/primes.v1.Primes/List")
+		require.False(t, allowed)
+		require.Equal(t, 500*time.Millisecond, wait)
+
+		c.now = c.now.Add(wait)
+
+		allowed, _ = l.Allow("ip:10.0.0.1", "/primes.v1.Primes/List")
+		require.True(t, allowed)
+	})
+
+	t.Run("PerClientAndMethod", func(t *testing.T) {
+		l, _ := newTestLimiter(t, Limits{"List": {Rate: 1, Burst: 1}, "Random": {Rate: 1, Burst: 1}})
+
+		allowed, _ := l.Allow("key:a", "List")
+		require.True(t, allowed)
+
+		allowed, _ = l.Allow("key:a", "List")
+		require.False(t, allowed)
+
+		allowed, _ = l.Allow("key:b", "List")
+		require.True(t, allowed)
+
+		allowed, _ = l.Allow("key:a", "Random")
+		require.True(t, allowed)
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		l, _ := newTestLimiter(t, Limits{Any: {Rate: 1, Burst: 1}})
+
+		allowed, _ := l.Allow("key:a", "Random")
+		require.True(t, allowed)
+
+		allowed, _ = l.Allow("key:a", "Random")
+		require.False(t, allowed)
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		l, _ := newTestLimiter(t, Limits{"List": {Rate: 1, Burst: 1}})
+
+		for range 10 {
+			allowed, _ := l.Allow("key:a", "Random")
+			require.True(t, allowed)
+		}
+	})
+
+	t.Run("Sweep", func(t *testing.T) {
+		l, c := newTestLimiter(t, Limits{"List": {Rate: 1, Burst: 5}})
+
+		_, _ = l.Allow("key:a", "List")
+		_, _ = l.Allow("key:b", "List")
+
+		c.now = c.now.Add(sweepInterval)
+
+		_, _ = l.Allow("key:c", "List")
+		require.Len(t, l.buckets, 1)
+	})
+}
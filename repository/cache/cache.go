@@ -0,0 +1,219 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/zalgonoise/tendigitprimes/primes"
+)
+
+const (
+	defaultMaxEntries = 1 << 16
+
+	opIsPrime  = "is_prime"
+	opNext     = "next"
+	opPrevious = "previous"
+	opCount    = "count"
+	opNth      = "nth"
+)
+
+var ErrUnsupported = fmt.Errorf("%w: lookup is not supported by the underlying repository", primes.ErrUnsupported)
+
+type Repository interface {
+	Random(ctx context.Context, min, max int64) (int64, error)
+	List(ctx context.Context, min, max, limit int64) ([]int64, error)
+	Close() error
+}
+
+// Lookup describes deterministic queries, that return the same result for the same input on a given dataset.
+type Lookup interface {
+	IsPrime(ctx context.Context, n int64) (bool, error)
+	Next(ctx context.Context, n int64) (int64, error)
+	Previous(ctx context.Context, n int64) (int64, error)
+	Count(ctx context.Context, min, max int64) (int64, error)
+	Nth(ctx context.Context, n int64) (int64, error)
+}
+
+type Metrics interface {
+	IncCacheHits(op string)
+	IncCacheMisses(op string)
+	IncCacheEvictions()
+	SetCacheEntries(n int)
+}
+
+type key struct {
+	op      string
+	a, b    int64
+	version string
+}
+
+type entry struct {
+	key   key
+	value int64
+}
+
+// Cache is a primes.Repository decorator that caches the results of deterministic lookups in a size-bounded,
+// least-recently-used cache. Random and List calls are passed through to the underlying repository.
+//
+// Entries are keyed by the request and the dataset version. Since the dataset does not change for a given version, there
+// is no TTL; instead, all entries are dropped when the version is changed with SetVersion, e.g. on a dataset reload.
+type Cache struct {
+	repo   Repository
+	lookup Lookup
+
+	mu         sync.Mutex
+	version    string
+	maxEntries int
+	entries    map[key]*list.Element
+	lru        *list.List
+
+	m Metrics
+}
+
+func (c *Cache) Random(ctx context.Context, min, max int64) (int64, error) {
+	return c.repo.Random(ctx, min, max)
+}
+
+func (c *Cache) List(ctx context.Context, min, max, limit int64) ([]int64, error) {
+	return c.repo.List(ctx, min, max, limit)
+}
+
+func (c *Cache) Close() error {
+	return c.repo.Close()
+}
+
+func (c *Cache) IsPrime(ctx context.Context, n int64) (bool, error) {
+	v, err := c.get(opIsPrime, n, 0, func(l Lookup) (int64, error) {
+		ok, err := l.IsPrime(ctx, n)
+		if ok {
+			return 1, err
+		}
+
+		return 0, err
+	})
+
+	return v == 1, err
+}
+
+func (c *Cache) Next(ctx context.Context, n int64) (int64, error) {
+	return c.get(opNext, n, 0, func(l Lookup) (int64, error) {
+		return l.Next(ctx, n)
+	})
+}
+
+func (c *Cache) Previous(ctx context.Context, n int64) (int64, error) {
+	return c.get(opPrevious, n, 0, func(l Lookup) (int64, error) {
+		return l.Previous(ctx, n)
+	})
+}
+
+func (c *Cache) Count(ctx context.Context, min, max int64) (int64, error) {
+	return c.get(opCount, min, max, func(l Lookup) (int64, error) {
+		return l.Count(ctx, min, max)
+	})
+}
+
+func (c *Cache) Nth(ctx context.Context, n int64) (int64, error) {
+	return c.get(opNth, n, 0, func(l Lookup) (int64, error) {
+		return l.Nth(ctx, n)
+	})
+}
+
+// SetVersion sets the current dataset version, dropping all cached entries if it differs from the previous one.
+func (c *Cache) SetVersion(version string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if version == c.version {
+		return
+	}
+
+	c.version = version
+	c.entries = make(map[key]*list.Element, len(c.entries))
+	c.lru.Init()
+
+	c.m.SetCacheEntries(0)
+}
+
+func (c *Cache) get(op string, a, b int64, fn func(Lookup) (int64, error)) (int64, error) {
+	if c.lookup == nil {
+		return 0, ErrUnsupported
+	}
+
+	c.mu.Lock()
+	k := key{op: op, a: a, b: b, version: c.version}
+
+	if elem, ok := c.entries[k]; ok {
+		c.lru.MoveToFront(elem)
+		value := elem.Value.(*entry).value
+		c.mu.Unlock()
+
+		c.m.IncCacheHits(op)
+
+		return value, nil
+	}
+
+	c.mu.Unlock()
+	c.m.IncCacheMisses(op)
+
+	value, err := fn(c.lookup)
+	if err != nil {
+		return 0, err
+	}
+
+	c.store(k, value)
+
+	return value, nil
+}
+
+func (c *Cache) store(k key, value int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the dataset was reloaded while the lookup was running, its result is stale
+	if k.version != c.version {
+		return
+	}
+
+	if _, ok := c.entries[k]; ok {
+		return
+	}
+
+	c.entries[k] = c.lru.PushFront(&entry{key: k, value: value})
+
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+
+		c.m.IncCacheEvictions()
+	}
+
+	c.m.SetCacheEntries(c.lru.Len())
+}
+
+// New creates a Cache over repo, holding up to maxEntries results for the input dataset version. If repo does not
+// implement Lookup, all lookups return ErrUnsupported.
+func New(repo Repository, version string, maxEntries int, m Metrics) *Cache {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxEntries
+	}
+
+	lookup, _ := repo.(Lookup)
+
+	return &Cache{
+		repo:       repo,
+		lookup:     lookup,
+		version:    version,
+		maxEntries: maxEntries,
+		entries:    make(map[key]*list.Element, minAlloc(maxEntries)),
+		lru:        list.New(),
+		m:          m,
+	}
+}
+
+func minAlloc(maxEntries int) int {
+	return min(maxEntries, 1024)
+}
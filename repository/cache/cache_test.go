@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/metrics"
+	"github.com/zalgonoise/tendigitprimes/primes"
+	"github.com/zalgonoise/tendigitprimes/repository/reload"
+)
+
+var errTest = errors.New("test error")
+
+type testRepo struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+}
+
+func (r *testRepo) Random(context.Context, int64, int64) (int64, error) { return 7, nil }
+
+func (r *testRepo) List(context.Context, int64, int64, int64) ([]int64, error) {
+	return []int64{7}, nil
+}
+
+func (r *testRepo) Close() error { return nil }
+
+func (r *testRepo) call() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls++
+
+	return r.err
+}
+
+func (r *testRepo) IsPrime(_ context.Context, n int64) (bool, error) {
+	return n == 7, r.call()
+}
+
+func (r *testRepo) Next(_ context.Context, n int64) (int64, error) { return n + 1, r.call() }
+
+func (r *testRepo) Previous(_ context.Context, n int64) (int64, error) { return n - 1, r.call() }
+
+func (r *testRepo) Count(_ context.Context, min, max int64) (int64, error) {
+	return max - min, r.call()
+}
+
+func (r *testRepo) Nth(_ context.Context, n int64) (int64, error) { return n * 2, r.call() }
+
+type randomOnly struct{}
+
+func (randomOnly) Random(context.Context, int64, int64) (int64, error)        { return 7, nil }
+func (randomOnly) List(context.Context, int64, int64, int64) ([]int64, error) { return nil, nil }
+func (randomOnly) Close() error                                               { return nil }
+
+type testMetrics struct {
+	hits, misses, evictions, entries int
+}
+
+func (m *testMetrics) IncCacheHits(string)   { m.hits++ }
+func (m *testMetrics) IncCacheMisses(string) { m.misses++ }
+func (m *testMetrics) IncCacheEvictions()    { m.evictions++ }
+func (m *testMetrics) SetCacheEntries(n int) { m.entries = n }
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("HitsAndMisses", func(t *testing.T) {
+		repo := &testRepo{}
+		m := &testMetrics{}
+		c := New(repo, "v1", 16, m)
+
+		for range 3 {
+			ok, err := c.IsPrime(ctx, 7)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			ok, err = c.IsPrime(ctx, 8)
+			require.NoError(t, err)
+			require.False(t, ok)
+
+			n, err := c.Count(ctx, 10, 20)
+			require.NoError(t, err)
+			require.Equal(t, int64(10), n)
+		}
+
+		require.Equal(t, 3, repo.calls)
+		require.Equal(t, 3, m.misses)
+		require.Equal(t, 6, m.hits)
+		require.Equal(t, 3, m.entries)
+	})
+
+	t.Run("DistinctOps", func(t *testing.T) {
+		repo := &testRepo{}
+		c := New(repo, "v1", 16, &testMetrics{})
+
+		next, err := c.Next(ctx, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(11), next)
+
+		prev, err := c.Previous(ctx, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(9), prev)
+
+		nth, err := c.Nth(ctx, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(20), nth)
+
+		require.Equal(t, 3, repo.calls)
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		repo := &testRepo{}
+		m := &testMetrics{}
+		c := New(repo, "v1", 2, m)
+
+		for _, n := range []int64{1, 2, 3} {
+			_, err := c.Next(ctx, n)
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, 1, m.evictions)
+		require.Equal(t, 2, m.entries)
+
+		// 1 was the least recently used entry
+		_, err := c.Next(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, 4, repo.calls)
+
+		_, err = c.Next(ctx, 3)
+		require.NoError(t, err)
+		require.Equal(t, 4, repo.calls)
+	})
+
+	t.Run("SetVersion", func(t *testing.T) {
+		repo := &testRepo{}
+		m := &testMetrics{}
+		c := New(repo, "v1", 16, m)
+
+		_, err := c.Nth(ctx, 5)
+		require.NoError(t, err)
+
+		c.SetVersion("v1")
+		_, err = c.Nth(ctx, 5)
+		require.NoError(t, err)
+		require.Equal(t, 1, repo.calls)
+
+		c.SetVersion("v2")
+		require.Equal(t, 0, m.entries)
+
+		_, err = c.Nth(ctx, 5)
+		require.NoError(t, err)
+		require.Equal(t, 2, repo.calls)
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		first, second := &testRepo{}, &testRepo{}
+		datasets := []*testRepo{first, second}
+
+		r, err := reload.New(ctx, func(context.Context) (reload.Dataset, error) {
+			repo := datasets[0]
+			datasets = datasets[1:]
+
+			return reload.Dataset{Repo: repo}, nil
+		}, metrics.Noop{}, log.NoOp())
+		require.NoError(t, err)
+
+		c := New(r, strconv.FormatUint(r.Version(), 10), 16, &testMetrics{})
+		r.OnSwap(func(_ context.Context, version uint64) {
+			c.SetVersion(strconv.FormatUint(version, 10))
+		})
+
+		for range 2 {
+			_, err = c.Nth(ctx, 5)
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, 1, first.calls)
+
+		// the entries from the first dataset are dropped, so lookups go to the second one
+		require.NoError(t, r.Reload(ctx))
+
+		for range 2 {
+			_, err = c.Nth(ctx, 5)
+			require.NoError(t, err)
+		}
+
+		require.Equal(t, 1, first.calls)
+		require.Equal(t, 1, second.calls)
+	})
+
+	t.Run("ErrorsAreNotCached", func(t *testing.T) {
+		repo := &testRepo{err: errTest}
+		c := New(repo, "v1", 16, &testMetrics{})
+
+		for range 2 {
+			_, err := c.Next(ctx, 1)
+			require.ErrorIs(t, err, errTest)
+		}
+
+		require.Equal(t, 2, repo.calls)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		c := New(randomOnly{}, "v1", 16, &testMetrics{})
+
+		_, err := c.IsPrime(ctx, 7)
+		require.ErrorIs(t, err, ErrUnsupported)
+		require.ErrorIs(t, err, primes.ErrUnsupported)
+
+		n, err := c.Random(ctx, 1, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(7), n)
+	})
+}
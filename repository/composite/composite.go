@@ -0,0 +1,161 @@
+package composite
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+var ErrNoBackends = errors.New("no backends configured")
+
+type Repository interface {
+	Random(ctx context.Context, min, max int64) (int64, error)
+	List(ctx context.Context, min, max, limit int64) ([]int64, error)
+	Close() error
+}
+
+type Metrics interface {
+	IncBackendFailures(backend string)
+	IncHedgedRequests(backend string)
+}
+
+// Backend is a named repository served by a Composite.
+type Backend struct {
+	Name string
+	Repo Repository
+}
+
+type result[T any] struct {
+	value   T
+	err     error
+	backend string
+}
+
+// Composite is a primes.Repository that serves requests from a list of backends in order of preference.
+//
+// When a backend returns an error, the request is retried on the next backend. When a hedge delay is set, a request
+// that is still running after that delay is also fired on the next backend, and the first successful response wins.
+type Composite struct {
+	backends   []Backend
+	hedgeDelay time.Duration
+
+	m      Metrics
+	logger *slog.Logger
+}
+
+func (c *Composite) Random(ctx context.Context, min, max int64) (int64, error) {
+	return run(ctx, c, func(ctx context.Context, repo Repository) (int64, error) {
+		return repo.Random(ctx, min, max)
+	})
+}
+
+func (c *Composite) List(ctx context.Context, min, max, limit int64) ([]int64, error) {
+	return run(ctx, c, func(ctx context.Context, repo Repository) ([]int64, error) {
+		return repo.List(ctx, min, max, limit)
+	})
+}
+
+// Close closes all backends.
+func (c *Composite) Close() error {
+	errs := make([]error, 0, len(c.backends))
+
+	for i := range c.backends {
+		if err := c.backends[i].Repo.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func run[T any](ctx context.Context, c *Composite, fn func(context.Context, Repository) (T, error)) (T, error) {
+	var zero T
+
+	// cancels any request still in flight once a response is returned
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan result[T], len(c.backends))
+	errs := make([]error, 0, len(c.backends))
+
+	var next, pending int
+
+	launch := func() {
+		b := c.backends[next]
+		next++
+		pending++
+
+		go func() {
+			value, err := fn(ctx, b.Repo)
+			results <- result[T]{value: value, err: err, backend: b.Name}
+		}()
+	}
+
+	launch()
+
+	hedge := time.NewTimer(c.hedgeDelay)
+	defer hedge.Stop()
+
+	for pending > 0 {
+		if !hedge.Stop() {
+			select {
+			case <-hedge.C:
+			default:
+			}
+		}
+
+		var hedged <-chan time.Time
+
+		if c.hedgeDelay > 0 && next < len(c.backends) {
+			hedge.Reset(c.hedgeDelay)
+			hedged = hedge.C
+		}
+
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-hedged:
+			c.m.IncHedgedRequests(c.backends[next].Name)
+			launch()
+		case r := <-results:
+			pending--
+
+			if r.err == nil {
+				return r.value, nil
+			}
+
+			if ctx.Err() != nil {
+				return zero, ctx.Err()
+			}
+
+			c.m.IncBackendFailures(r.backend)
+			c.logger.WarnContext(ctx, "backend request failed",
+				slog.String("backend", r.backend),
+				slog.String("error", r.err.Error()),
+			)
+
+			errs = append(errs, r.err)
+
+			if pending == 0 && next < len(c.backends) {
+				launch()
+			}
+		}
+	}
+
+	return zero, errors.Join(errs...)
+}
+
+// New creates a Composite over the input backends, in order of preference. A zero hedgeDelay disables hedging.
+func New(backends []Backend, hedgeDelay time.Duration, m Metrics, logger *slog.Logger) (*Composite, error) {
+	if len(backends) == 0 {
+		return nil, ErrNoBackends
+	}
+
+	return &Composite{
+		backends:   backends,
+		hedgeDelay: hedgeDelay,
+		m:          m,
+		logger:     logger,
+	}, nil
+}
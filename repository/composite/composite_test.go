@@ -0,0 +1,180 @@
+package composite
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+)
+
+var errTest = errors.New("test error")
+
+type testRepository struct {
+	value int64
+	delay time.Duration
+	err   error
+
+	calls  atomic.Int64
+	closed atomic.Bool
+}
+
+func (r *testRepository) Random(ctx context.Context, _, _ int64) (int64, error) {
+	r.calls.Add(1)
+
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-time.After(r.delay):
+	}
+
+	return r.value, r.err
+}
+
+func (r *testRepository) List(ctx context.Context, min, max, limit int64) ([]int64, error) {
+	n, err := r.Random(ctx, min, max)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := make([]int64, limit)
+	for i := range ns {
+		ns[i] = n
+	}
+
+	return ns, nil
+}
+
+func (r *testRepository) Close() error {
+	r.closed.Store(true)
+
+	return nil
+}
+
+type testMetrics struct {
+	mu       sync.Mutex
+	failures map[string]int
+	hedges   map[string]int
+}
+
+func newTestMetrics() *testMetrics {
+	return &testMetrics{failures: map[string]int{}, hedges: map[string]int{}}
+}
+
+func (m *testMetrics) IncBackendFailures(backend string) {
+	m.mu.Lock()
+	m.failures[backend]++
+	m.mu.Unlock()
+}
+
+func (m *testMetrics) IncHedgedRequests(backend string) {
+	m.mu.Lock()
+	m.hedges[backend]++
+	m.mu.Unlock()
+}
+
+func TestComposite(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Primary", func(t *testing.T) {
+		primary := &testRepository{value: 2}
+		secondary := &testRepository{value: 3}
+
+		c, err := New([]Backend{{"primary", primary}, {"secondary", secondary}}, 0, newTestMetrics(), log.NoOp())
+		require.NoError(t, err)
+
+		n, err := c.Random(ctx, 0, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+		require.Zero(t, secondary.calls.Load())
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		primary := &testRepository{err: errTest}
+		secondary := &testRepository{value: 3}
+		m := newTestMetrics()
+
+		c, err := New([]Backend{{"primary", primary}, {"secondary", secondary}}, 0, m, log.NoOp())
+		require.NoError(t, err)
+
+		ns, err := c.List(ctx, 0, 10, 5)
+		require.NoError(t, err)
+		require.Equal(t, []int64{3, 3, 3, 3, 3}, ns)
+		require.Equal(t, 1, m.failures["primary"])
+	})
+
+	t.Run("AllFailing", func(t *testing.T) {
+		errOther := errors.New("other error")
+
+		c, err := New([]Backend{
+			{"primary", &testRepository{err: errTest}},
+			{"secondary", &testRepository{err: errOther}},
+		}, 0, newTestMetrics(), log.NoOp())
+		require.NoError(t, err)
+
+		_, err = c.Random(ctx, 0, 10)
+		require.ErrorIs(t, err, errTest)
+		require.ErrorIs(t, err, errOther)
+	})
+
+	t.Run("Hedged", func(t *testing.T) {
+		primary := &testRepository{value: 2, delay: time.Second}
+		secondary := &testRepository{value: 3}
+		m := newTestMetrics()
+
+		c, err := New([]Backend{{"primary", primary}, {"secondary", secondary}}, 10*time.Millisecond, m, log.NoOp())
+		require.NoError(t, err)
+
+		start := time.Now()
+
+		n, err := c.Random(ctx, 0, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), n)
+		require.Less(t, time.Since(start), time.Second)
+		require.Equal(t, 1, m.hedges["secondary"])
+	})
+
+	t.Run("NotHedgedWhenFast", func(t *testing.T) {
+		primary := &testRepository{value: 2}
+		secondary := &testRepository{value: 3}
+
+		c, err := New([]Backend{{"primary", primary}, {"secondary", secondary}}, time.Second, newTestMetrics(), log.NoOp())
+		require.NoError(t, err)
+
+		n, err := c.Random(ctx, 0, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+		require.Zero(t, secondary.calls.Load())
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		c, err := New([]Backend{{"primary", &testRepository{delay: time.Second}}}, 0, newTestMetrics(), log.NoOp())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		_, err = c.Random(ctx, 0, 10)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		primary := &testRepository{}
+		secondary := &testRepository{}
+
+		c, err := New([]Backend{{"primary", primary}, {"secondary", secondary}}, 0, newTestMetrics(), log.NoOp())
+		require.NoError(t, err)
+		require.NoError(t, c.Close())
+		require.True(t, primary.closed.Load())
+		require.True(t, secondary.closed.Load())
+	})
+
+	t.Run("NoBackends", func(t *testing.T) {
+		_, err := New(nil, 0, newTestMetrics(), log.NoOp())
+		require.ErrorIs(t, err, ErrNoBackends)
+	})
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package packed
+
+import "os"
+
+// mmap falls back to reading the whole file into memory on platforms without syscall.Mmap.
+func mmap(path string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() error { return nil }, nil
+}
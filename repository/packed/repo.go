@@ -0,0 +1,246 @@
+package packed
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/zalgonoise/tendigitprimes/primes"
+)
+
+const (
+	defaultLimit = 5000
+
+	magic      = "TDPRIMES"
+	version    = 1
+	headerSize = 64
+	indexEntry = 24
+)
+
+var (
+	ErrInvalidFormat   = errors.New("invalid packed dataset format")
+	ErrNoPrimesInRange = fmt.Errorf("%w: no prime numbers within the requested range", primes.ErrNotFound)
+	ErrOutOfBounds     = fmt.Errorf("%w: value is out of bounds", primes.ErrOutOfRange)
+)
+
+// Repository serves prime numbers from a memory-mapped, delta-encoded dataset, as written by database.Pack.
+type Repository struct {
+	data []byte
+
+	blockSize uint64
+	total     uint64
+	numBlocks uint64
+	index     []byte
+
+	unmap func() error
+}
+
+func (r *Repository) Random(_ context.Context, min, max int64) (int64, error) {
+	lo, hi := r.Bounds(min, max)
+	if hi <= lo {
+		return 0, fmt.Errorf("%w: [%d, %d]", ErrNoPrimesInRange, min, max)
+	}
+
+	return r.At(lo + rand.Int64N(hi-lo)), nil
+}
+
+func (r *Repository) List(_ context.Context, min, max, limit int64) ([]int64, error) {
+	if limit == 0 {
+		limit = defaultLimit
+	}
+
+	lo, hi := r.Bounds(min, max)
+	if hi <= lo {
+		return nil, fmt.Errorf("%w: [%d, %d]", ErrNoPrimesInRange, min, max)
+	}
+
+	results := make([]int64, 0, limit)
+
+	for int64(len(results)) < limit {
+		results = append(results, r.At(lo+rand.Int64N(hi-lo)))
+	}
+
+	return results, nil
+}
+
+func (r *Repository) Close() error {
+	return r.unmap()
+}
+
+// IsPrime returns whether n is a prime number in the dataset.
+func (r *Repository) IsPrime(_ context.Context, n int64) (bool, error) {
+	return r.Rank(n+1)-r.Rank(n) == 1, nil
+}
+
+// Next returns the smallest prime in the dataset that is greater than n.
+func (r *Repository) Next(_ context.Context, n int64) (int64, error) {
+	rank := r.Rank(n + 1)
+	if rank >= int64(r.total) {
+		return 0, fmt.Errorf("%w: after %d", ErrNoPrimesInRange, n)
+	}
+
+	return r.At(rank), nil
+}
+
+// Previous returns the greatest prime in the dataset that is lower than n.
+func (r *Repository) Previous(_ context.Context, n int64) (int64, error) {
+	rank := r.Rank(n)
+	if rank == 0 {
+		return 0, fmt.Errorf("%w: before %d", ErrNoPrimesInRange, n)
+	}
+
+	return r.At(rank - 1), nil
+}
+
+// Count returns the number of primes in the dataset within the [min, max] range.
+func (r *Repository) Count(_ context.Context, min, max int64) (int64, error) {
+	lo, hi := r.Bounds(min, max)
+
+	return hi - lo, nil
+}
+
+// Nth returns the n-th prime in the dataset, where the first prime (2) has n = 1.
+func (r *Repository) Nth(_ context.Context, n int64) (int64, error) {
+	if n < 1 || n > int64(r.total) {
+		return 0, fmt.Errorf("%w: %d", ErrOutOfBounds, n)
+	}
+
+	return r.At(n - 1), nil
+}
+
+// Len returns the total number of primes in the dataset.
+func (r *Repository) Len() int64 {
+	return int64(r.total)
+}
+
+// Bounds returns the rank of the first prime that is greater or equal to min, and the rank following the last prime
+// that is lower or equal to max.
+func (r *Repository) Bounds(min, max int64) (lo, hi int64) {
+	if max < min {
+		return 0, 0
+	}
+
+	return r.Rank(min), r.Rank(max + 1)
+}
+
+// Rank returns the number of primes in the dataset that are lower than value.
+func (r *Repository) Rank(value int64) int64 {
+	if value <= 0 || r.numBlocks == 0 {
+		return 0
+	}
+
+	v := uint64(value)
+
+	// find the last block whose first prime is lower than v
+	b := sort.Search(int(r.numBlocks), func(i int) bool {
+		first, _, _ := r.entry(uint64(i))
+
+		return first >= v
+	}) - 1
+
+	if b < 0 {
+		return 0
+	}
+
+	first, rank, offset := r.entry(uint64(b))
+	size := r.blockLen(uint64(b))
+	n := first
+	count := uint64(1)
+
+	for ; count < size; count++ {
+		gap, read := binary.Uvarint(r.data[offset:])
+		offset += uint64(read)
+
+		if n+gap >= v {
+			break
+		}
+
+		n += gap
+	}
+
+	return int64(rank + count)
+}
+
+// At returns the prime with the input rank, starting from zero.
+func (r *Repository) At(rank int64) int64 {
+	b := uint64(rank) / r.blockSize
+
+	n, _, offset := r.entry(b)
+
+	for i := uint64(0); i < uint64(rank)%r.blockSize; i++ {
+		gap, read := binary.Uvarint(r.data[offset:])
+		offset += uint64(read)
+		n += gap
+	}
+
+	return int64(n)
+}
+
+func (r *Repository) entry(b uint64) (first, rank, offset uint64) {
+	e := r.index[b*indexEntry : (b+1)*indexEntry]
+
+	return binary.LittleEndian.Uint64(e[0:]),
+		binary.LittleEndian.Uint64(e[8:]),
+		binary.LittleEndian.Uint64(e[16:])
+}
+
+func (r *Repository) blockLen(b uint64) uint64 {
+	if b < r.numBlocks-1 {
+		return r.blockSize
+	}
+
+	return r.total - b*r.blockSize
+}
+
+// NewRepository memory-maps the packed dataset file under path, and validates its header.
+func NewRepository(path string) (*Repository, error) {
+	data, unmap, err := mmap(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := newRepository(data)
+	if err != nil {
+		return nil, errors.Join(err, unmap())
+	}
+
+	r.unmap = unmap
+
+	return r, nil
+}
+
+func newRepository(data []byte) (*Repository, error) {
+	if len(data) < headerSize || string(data[:len(magic)]) != magic {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidFormat)
+	}
+
+	if v := binary.LittleEndian.Uint32(data[8:]); v != version {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidFormat, v)
+	}
+
+	r := &Repository{
+		data:      data,
+		blockSize: uint64(binary.LittleEndian.Uint32(data[12:])),
+		total:     binary.LittleEndian.Uint64(data[16:]),
+		numBlocks: binary.LittleEndian.Uint64(data[24:]),
+	}
+
+	indexOffset := binary.LittleEndian.Uint64(data[32:])
+	indexEnd := indexOffset + r.numBlocks*indexEntry
+
+	switch {
+	case r.blockSize == 0:
+		return nil, fmt.Errorf("%w: zero block size", ErrInvalidFormat)
+	case indexEnd > uint64(len(data)) || indexOffset < headerSize:
+		return nil, fmt.Errorf("%w: truncated index", ErrInvalidFormat)
+	case (r.total+r.blockSize-1)/r.blockSize != r.numBlocks:
+		return nil, fmt.Errorf("%w: block count mismatch", ErrInvalidFormat)
+	}
+
+	r.index = data[indexOffset:indexEnd]
+
+	return r, nil
+}
@@ -0,0 +1,151 @@
+package packed
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/database"
+	"github.com/zalgonoise/tendigitprimes/log"
+)
+
+func sieve(limit int) []int64 {
+	composite := make([]bool, limit+1)
+	primes := make([]int64, 0, limit/10)
+
+	for i := 2; i <= limit; i++ {
+		if composite[i] {
+			continue
+		}
+
+		primes = append(primes, int64(i))
+
+		for j := i * i; j <= limit; j += i {
+			composite[j] = true
+		}
+	}
+
+	return primes
+}
+
+func newTestRepository(t *testing.T, primes []int64, blockSize int) *Repository {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "raw")
+	output := filepath.Join(dir, "primes.bin")
+
+	require.NoError(t, os.Mkdir(input, 0o755))
+
+	sb := &strings.Builder{}
+	for i := range primes {
+		sb.WriteString(strconv.FormatInt(primes[i], 10))
+		sb.WriteByte('\n')
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(input, "primes-00"), []byte(sb.String()), 0o644))
+	require.NoError(t, database.Pack(context.Background(), blockSize, input, output, database.Validation{}, log.NoOp()))
+
+	repo, err := NewRepository(output)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, repo.Close())
+	})
+
+	return repo
+}
+
+func TestRepository(t *testing.T) {
+	primes := sieve(200_000)
+	repo := newTestRepository(t, primes, 64)
+
+	require.Equal(t, int64(len(primes)), repo.Len())
+
+	t.Run("At", func(t *testing.T) {
+		for i := range primes {
+			require.Equal(t, primes[i], repo.At(int64(i)))
+		}
+	})
+
+	t.Run("Rank", func(t *testing.T) {
+		for _, value := range []int64{0, 2, 3, 4, 100, 7919, 7920, 104_729, 199_999, 200_000, 1_000_000} {
+			wants := sort.Search(len(primes), func(i int) bool { return primes[i] >= value })
+
+			require.Equal(t, int64(wants), repo.Rank(value), "value: %d", value)
+		}
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		for i := 0; i < 1000; i++ {
+			n, err := repo.Random(context.Background(), 1_000, 2_000)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, n, int64(1_000))
+			require.LessOrEqual(t, n, int64(2_000))
+
+			idx := sort.Search(len(primes), func(i int) bool { return primes[i] >= n })
+			require.Equal(t, n, primes[idx])
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		ns, err := repo.List(context.Background(), 150_000, 160_000, 50)
+		require.NoError(t, err)
+		require.Len(t, ns, 50)
+
+		for i := range ns {
+			require.GreaterOrEqual(t, ns[i], int64(150_000))
+			require.LessOrEqual(t, ns[i], int64(160_000))
+		}
+	})
+
+	t.Run("Lookup", func(t *testing.T) {
+		ctx := context.Background()
+
+		isPrime, err := repo.IsPrime(ctx, 7919)
+		require.NoError(t, err)
+		require.True(t, isPrime)
+
+		isPrime, err = repo.IsPrime(ctx, 7917)
+		require.NoError(t, err)
+		require.False(t, isPrime)
+
+		n, err := repo.Next(ctx, 7919)
+		require.NoError(t, err)
+		require.Equal(t, int64(7927), n)
+
+		n, err = repo.Previous(ctx, 7919)
+		require.NoError(t, err)
+		require.Equal(t, int64(7907), n)
+
+		_, err = repo.Previous(ctx, 2)
+		require.ErrorIs(t, err, ErrNoPrimesInRange)
+
+		count, err := repo.Count(ctx, 2, 7919)
+		require.NoError(t, err)
+		require.Equal(t, int64(1000), count)
+
+		n, err = repo.Nth(ctx, 1000)
+		require.NoError(t, err)
+		require.Equal(t, int64(7919), n)
+
+		_, err = repo.Nth(ctx, 0)
+		require.ErrorIs(t, err, ErrOutOfBounds)
+	})
+
+	t.Run("EmptyRange", func(t *testing.T) {
+		_, err := repo.Random(context.Background(), 24, 28)
+		require.ErrorIs(t, err, ErrNoPrimesInRange)
+	})
+}
+
+func TestNewRepository_InvalidFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "primes.bin")
+	require.NoError(t, os.WriteFile(path, []byte("not a packed dataset"), 0o644))
+
+	_, err := NewRepository(path)
+	require.ErrorIs(t, err, ErrInvalidFormat)
+}
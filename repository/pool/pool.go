@@ -0,0 +1,270 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultSize    = 1024
+	defaultBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+type Repository interface {
+	Random(ctx context.Context, min, max int64) (int64, error)
+	List(ctx context.Context, min, max, limit int64) ([]int64, error)
+	Close() error
+}
+
+type Metrics interface {
+	IncPoolHits(minimum, maximum string)
+	IncPoolMisses(minimum, maximum string)
+}
+
+// Range configures a buffer of pre-sampled primes for requests on the [Min, Max] range.
+type Range struct {
+	Min int64
+	Max int64
+
+	// Size is the maximum number of primes buffered for this range.
+	Size int
+	// Batch is the number of primes sampled from the underlying repository on each refill. Defaults to half of Size.
+	Batch int
+}
+
+type key struct {
+	min int64
+	max int64
+}
+
+// entry is a pre-sampled prime, along with the pool's epoch when it was sampled.
+type entry struct {
+	prime int64
+	epoch uint64
+}
+
+type buffer struct {
+	primes chan entry
+
+	minimum string
+	maximum string
+}
+
+// Pool is a primes.Repository decorator that keeps a background-refilled buffer of random primes for a set of
+// frequently requested ranges, serving Random and small List calls on these ranges from memory.
+//
+// Requests on other ranges, or that the buffer cannot fully satisfy, are served by the underlying repository.
+type Pool struct {
+	repo    Repository
+	buffers map[key]buffer
+
+	// epoch is bumped on Flush; primes sampled on a previous epoch are dropped instead of served
+	epoch atomic.Uint64
+
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+
+	m      Metrics
+	logger *slog.Logger
+}
+
+func (p *Pool) Random(ctx context.Context, min, max int64) (int64, error) {
+	buf, ok := p.buffers[key{min, max}]
+	if !ok {
+		return p.repo.Random(ctx, min, max)
+	}
+
+	for {
+		select {
+		case e := <-buf.primes:
+			if e.epoch != p.epoch.Load() {
+				continue
+			}
+
+			p.m.IncPoolHits(buf.minimum, buf.maximum)
+
+			return e.prime, nil
+		default:
+			p.m.IncPoolMisses(buf.minimum, buf.maximum)
+
+			return p.repo.Random(ctx, min, max)
+		}
+	}
+}
+
+func (p *Pool) List(ctx context.Context, min, max, limit int64) ([]int64, error) {
+	buf, ok := p.buffers[key{min, max}]
+	if !ok || limit <= 0 || limit > int64(cap(buf.primes)) {
+		return p.repo.List(ctx, min, max, limit)
+	}
+
+	results := make([]int64, 0, limit)
+	epoch := p.epoch.Load()
+
+drain:
+	for int64(len(results)) < limit {
+		select {
+		case e := <-buf.primes:
+			if e.epoch == epoch {
+				results = append(results, e.prime)
+			}
+		default:
+			break drain
+		}
+	}
+
+	if int64(len(results)) == limit {
+		p.m.IncPoolHits(buf.minimum, buf.maximum)
+
+		return results, nil
+	}
+
+	p.m.IncPoolMisses(buf.minimum, buf.maximum)
+
+	ns, err := p.repo.List(ctx, min, max, limit-int64(len(results)))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(results, ns...), nil
+}
+
+// Flush drops all buffered primes, including the ones being sampled, so that the buffers are refilled from the
+// underlying repository, e.g. once it swapped in another dataset.
+func (p *Pool) Flush() {
+	p.epoch.Add(1)
+
+	for _, buf := range p.buffers {
+	drain:
+		for {
+			select {
+			case <-buf.primes:
+			default:
+				break drain
+			}
+		}
+	}
+}
+
+// Close stops all refill goroutines, and closes the underlying repository.
+func (p *Pool) Close() error {
+	p.cancel()
+	p.wg.Wait()
+
+	return p.repo.Close()
+}
+
+func (p *Pool) refill(ctx context.Context, r Range, buf buffer) {
+	defer p.wg.Done()
+
+	backoff := defaultBackoff
+
+	for {
+		epoch := p.epoch.Load()
+
+		ns, err := p.sample(ctx, r)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return
+			}
+
+			p.logger.WarnContext(ctx, "failed to refill primes pool",
+				slog.Int64("min", r.Min),
+				slog.Int64("max", r.Max),
+				slog.Duration("backoff", backoff),
+				slog.String("error", err.Error()),
+			)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff = min(backoff*2, maxBackoff)
+
+			continue
+		}
+
+		backoff = defaultBackoff
+
+		// sending blocks while the buffer is full, which keeps memory usage bounded to Size + Batch primes
+		for i := range ns {
+			// the pool was flushed while sampling or waiting, so the rest of the batch is stale
+			if epoch != p.epoch.Load() {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case buf.primes <- entry{prime: ns[i], epoch: epoch}:
+			}
+		}
+	}
+}
+
+// sample returns a batch of random primes within the range. Primes are sampled with Random, as List may return the
+// lowest primes in range, in order, depending on the underlying repository.
+func (p *Pool) sample(ctx context.Context, r Range) ([]int64, error) {
+	ns := make([]int64, 0, r.Batch)
+
+	for range r.Batch {
+		n, err := p.repo.Random(ctx, r.Min, r.Max)
+		if err != nil {
+			return nil, err
+		}
+
+		ns = append(ns, n)
+	}
+
+	return ns, nil
+}
+
+// New creates a Pool over repo, and starts refilling a buffer for each of the input ranges in the background.
+func New(repo Repository, ranges []Range, m Metrics, logger *slog.Logger) *Pool {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := &Pool{
+		repo:    repo,
+		buffers: make(map[key]buffer, len(ranges)),
+		cancel:  cancel,
+		wg:      &sync.WaitGroup{},
+		m:       m,
+		logger:  logger,
+	}
+
+	for _, r := range ranges {
+		if r.Size <= 0 {
+			r.Size = defaultSize
+		}
+
+		if r.Batch <= 0 || r.Batch > r.Size {
+			r.Batch = max(r.Size/2, 1)
+		}
+
+		k := key{r.Min, r.Max}
+		if _, ok := p.buffers[k]; ok {
+			continue
+		}
+
+		buf := buffer{
+			primes:  make(chan entry, r.Size),
+			minimum: strconv.FormatInt(r.Min, 10),
+			maximum: strconv.FormatInt(r.Max, 10),
+		}
+
+		p.buffers[k] = buf
+		p.wg.Add(1)
+
+		go p.refill(ctx, r, buf)
+	}
+
+	return p
+}
@@ -0,0 +1,227 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+)
+
+type testRepository struct {
+	calls atomic.Int64
+	err   error
+}
+
+func (r *testRepository) Random(_ context.Context, min, _ int64) (int64, error) {
+	r.calls.Add(1)
+
+	return min, r.err
+}
+
+func (r *testRepository) List(_ context.Context, min, _, limit int64) ([]int64, error) {
+	r.calls.Add(1)
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	ns := make([]int64, limit)
+	for i := range ns {
+		ns[i] = min
+	}
+
+	return ns, nil
+}
+
+func (r *testRepository) Close() error { return nil }
+
+// orderedRepository lists the lowest values in range, in order, like the SQLite, packed, sieve and PostgreSQL
+// repositories do.
+type orderedRepository struct{}
+
+func (orderedRepository) Random(_ context.Context, min, max int64) (int64, error) {
+	return min + rand.Int64N(max-min+1), nil
+}
+
+func (orderedRepository) List(_ context.Context, min, _, limit int64) ([]int64, error) {
+	ns := make([]int64, limit)
+	for i := range ns {
+		ns[i] = min + int64(i)
+	}
+
+	return ns, nil
+}
+
+func (orderedRepository) Close() error { return nil }
+
+type testMetrics struct {
+	mu     sync.Mutex
+	hits   int
+	misses int
+}
+
+func (m *testMetrics) IncPoolHits(string, string) {
+	m.mu.Lock()
+	m.hits++
+	m.mu.Unlock()
+}
+
+func (m *testMetrics) IncPoolMisses(string, string) {
+	m.mu.Lock()
+	m.misses++
+	m.mu.Unlock()
+}
+
+func waitFull(t *testing.T, p *Pool, min, max int64) {
+	buf := p.buffers[key{min, max}]
+
+	require.Eventually(t, func() bool {
+		return len(buf.primes) == cap(buf.primes)
+	}, time.Second, time.Millisecond)
+}
+
+func TestPool(t *testing.T) {
+	repo := &testRepository{}
+	m := &testMetrics{}
+	ctx := context.Background()
+
+	p := New(repo, []Range{{Min: 10, Max: 20, Size: 8}}, m, log.NoOp())
+
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	waitFull(t, p, 10, 20)
+
+	t.Run("RandomHit", func(t *testing.T) {
+		n, err := p.Random(ctx, 10, 20)
+		require.NoError(t, err)
+		require.Equal(t, int64(10), n)
+		require.Equal(t, 1, m.hits)
+	})
+
+	waitFull(t, p, 10, 20)
+
+	t.Run("ListHit", func(t *testing.T) {
+		ns, err := p.List(ctx, 10, 20, 8)
+		require.NoError(t, err)
+		require.Len(t, ns, 8)
+		require.Equal(t, 2, m.hits)
+	})
+
+	t.Run("ListOverSize", func(t *testing.T) {
+		calls := repo.calls.Load()
+
+		ns, err := p.List(ctx, 10, 20, 100)
+		require.NoError(t, err)
+		require.Len(t, ns, 100)
+		require.Greater(t, repo.calls.Load(), calls)
+		require.Equal(t, 2, m.hits)
+	})
+
+	t.Run("UnpooledRange", func(t *testing.T) {
+		n, err := p.Random(ctx, 30, 40)
+		require.NoError(t, err)
+		require.Equal(t, int64(30), n)
+		require.Equal(t, 2, m.hits)
+		require.Zero(t, m.misses)
+	})
+}
+
+func TestPool_Miss(t *testing.T) {
+	errTest := errors.New("test error")
+	repo := &testRepository{err: errTest}
+	m := &testMetrics{}
+
+	p := New(repo, []Range{{Min: 10, Max: 20, Size: 8}}, m, log.NoOp())
+
+	_, err := p.Random(context.Background(), 10, 20)
+	require.ErrorIs(t, err, errTest)
+	require.Equal(t, 1, m.misses)
+
+	require.NoError(t, p.Close())
+}
+
+func TestPool_OrderedRepository(t *testing.T) {
+	ctx := context.Background()
+
+	p := New(orderedRepository{}, []Range{{Min: 0, Max: 1_000_000, Size: 8}}, &testMetrics{}, log.NoOp())
+
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	seen := make(map[int64]struct{}, 64)
+
+	for range 64 {
+		waitFull(t, p, 0, 1_000_000)
+
+		n, err := p.Random(ctx, 0, 1_000_000)
+		require.NoError(t, err)
+
+		seen[n] = struct{}{}
+	}
+
+	// the buffer is not refilled with the same lowest values in range
+	require.Greater(t, len(seen), 8)
+}
+
+// datasetRepository returns the same value on all calls, standing in for a dataset that is swapped with another one.
+type datasetRepository struct {
+	value atomic.Int64
+}
+
+func (r *datasetRepository) Random(context.Context, int64, int64) (int64, error) {
+	return r.value.Load(), nil
+}
+
+func (r *datasetRepository) List(_ context.Context, _, _, limit int64) ([]int64, error) {
+	ns := make([]int64, limit)
+	for i := range ns {
+		ns[i] = r.value.Load()
+	}
+
+	return ns, nil
+}
+
+func (r *datasetRepository) Close() error { return nil }
+
+func TestPool_Flush(t *testing.T) {
+	ctx := context.Background()
+	repo := &datasetRepository{}
+	repo.value.Store(1)
+
+	p := New(repo, []Range{{Min: 10, Max: 20, Size: 8}}, &testMetrics{}, log.NoOp())
+
+	defer func() {
+		require.NoError(t, p.Close())
+	}()
+
+	waitFull(t, p, 10, 20)
+
+	repo.value.Store(2)
+	p.Flush()
+
+	// no primes from the previous dataset are served, even if they were being sampled during the flush
+	for range 32 {
+		n, err := p.Random(ctx, 10, 20)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+
+		ns, err := p.List(ctx, 10, 20, 4)
+		require.NoError(t, err)
+		require.Equal(t, []int64{2, 2, 2, 2}, ns)
+	}
+
+	waitFull(t, p, 10, 20)
+
+	n, err := p.Random(ctx, 10, 20)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+}
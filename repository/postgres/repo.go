@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // Database driver
+	"github.com/zalgonoise/tendigitprimes/primes"
+)
+
+const (
+	defaultLimit = 5000
+
+	// rankQuery counts the primes lower than a value, starting from the closest rank checkpoint below it, so that only
+	// the primes between the two are scanned.
+	rankQuery = `
+		WITH c AS (
+			SELECT rank, prime FROM ranks WHERE prime < $1 ORDER BY prime DESC LIMIT 1
+		)
+		SELECT COALESCE((SELECT rank FROM c), 0) + (
+			SELECT count(*) FROM primes WHERE prime >= COALESCE((SELECT prime FROM c), 0) AND prime < $1
+		)
+`
+
+	// primesAtQuery returns the primes at a set of zero-based ranks in a single round-trip, seeking each of them from
+	// the closest rank checkpoint below it.
+	primesAtQuery = `
+		SELECT p.prime FROM unnest($1::BIGINT[]) AS s(rank)
+			CROSS JOIN LATERAL (
+				SELECT rank, prime FROM ranks WHERE rank <= s.rank ORDER BY rank DESC LIMIT 1
+			) AS c
+			CROSS JOIN LATERAL (
+				SELECT prime FROM primes WHERE prime >= c.prime ORDER BY prime OFFSET s.rank - c.rank LIMIT 1
+			) AS p
+`
+
+	isPrimeQuery  = `SELECT EXISTS(SELECT 1 FROM primes WHERE prime = $1)`
+	nextQuery     = `SELECT prime FROM primes WHERE prime > $1 ORDER BY prime LIMIT 1`
+	previousQuery = `SELECT prime FROM primes WHERE prime < $1 ORDER BY prime DESC LIMIT 1`
+
+	hasRanksQuery = `SELECT to_regclass('ranks') IS NOT NULL`
+)
+
+var (
+	ErrNoPrimesInRange = fmt.Errorf("%w: no prime numbers within the requested range", primes.ErrNotFound)
+	ErrOutOfBounds     = fmt.Errorf("%w: value is out of bounds", primes.ErrOutOfRange)
+	ErrMissingRanks    = errors.New("database has no rank checkpoints, the dataset must be built again")
+)
+
+// Repository serves prime numbers from a range-partitioned primes table in PostgreSQL, as written by
+// database.PartitionPostgres.
+//
+// Random primes are picked by a uniformly random rank among the primes within the range, seeking each from the closest
+// rank checkpoint below it, which avoids scanning the table with a large OFFSET clause.
+type Repository struct {
+	DB *sql.DB
+}
+
+func (r Repository) Random(ctx context.Context, min, max int64) (int64, error) {
+	ns, err := r.sample(ctx, min, max, 1)
+	if err != nil {
+		return 0, err
+	}
+
+	return ns[0], nil
+}
+
+func (r Repository) List(ctx context.Context, min, max, limit int64) ([]int64, error) {
+	if limit == 0 {
+		limit = defaultLimit
+	}
+
+	return r.sample(ctx, min, max, limit)
+}
+
+// IsPrime returns whether n is a prime number in the dataset.
+func (r Repository) IsPrime(ctx context.Context, n int64) (bool, error) {
+	var ok bool
+
+	if err := r.DB.QueryRowContext(ctx, isPrimeQuery, n).Scan(&ok); err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
+// Next returns the smallest prime in the dataset that is greater than n.
+func (r Repository) Next(ctx context.Context, n int64) (int64, error) {
+	prime, err := r.queryPrime(ctx, nextQuery, n)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: after %d", ErrNoPrimesInRange, n)
+	}
+
+	return prime, err
+}
+
+// Previous returns the greatest prime in the dataset that is lower than n.
+func (r Repository) Previous(ctx context.Context, n int64) (int64, error) {
+	prime, err := r.queryPrime(ctx, previousQuery, n)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%w: before %d", ErrNoPrimesInRange, n)
+	}
+
+	return prime, err
+}
+
+// Count returns the number of primes in the dataset within the [min, max] range.
+func (r Repository) Count(ctx context.Context, min, max int64) (int64, error) {
+	if max < min {
+		return 0, nil
+	}
+
+	lo, hi, err := r.ranks(ctx, min, max)
+	if err != nil {
+		return 0, err
+	}
+
+	return hi - lo, nil
+}
+
+// Nth returns the n-th prime in the dataset, where the first prime (2) has n = 1.
+func (r Repository) Nth(ctx context.Context, n int64) (int64, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("%w: %d", ErrOutOfBounds, n)
+	}
+
+	ns, err := r.primesAt(ctx, []int64{n - 1})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(ns) == 0 {
+		return 0, fmt.Errorf("%w: %d", ErrOutOfBounds, n)
+	}
+
+	return ns[0], nil
+}
+
+func (r Repository) Close() error {
+	return r.DB.Close()
+}
+
+// sample returns limit primes within the [min, max] range, each picked by a uniformly random rank.
+func (r Repository) sample(ctx context.Context, min, max, limit int64) ([]int64, error) {
+	if max < min {
+		return nil, fmt.Errorf("%w: [%d, %d]", ErrNoPrimesInRange, min, max)
+	}
+
+	lo, hi, err := r.ranks(ctx, min, max)
+	if err != nil {
+		return nil, err
+	}
+
+	if hi <= lo {
+		return nil, fmt.Errorf("%w: [%d, %d]", ErrNoPrimesInRange, min, max)
+	}
+
+	ranks := make([]int64, limit)
+	for i := range ranks {
+		ranks[i] = lo + rand.Int64N(hi-lo)
+	}
+
+	ns, err := r.primesAt(ctx, ranks)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(ns)) != limit {
+		return nil, fmt.Errorf("%w: found %d out of %d ranks in [%d, %d]", ErrMissingRanks, len(ns), limit, min, max)
+	}
+
+	return ns, nil
+}
+
+// ranks returns the ranks of the primes within the [min, max] range, as a half-open [lo, hi) interval.
+func (r Repository) ranks(ctx context.Context, min, max int64) (lo, hi int64, err error) {
+	if err = r.DB.QueryRowContext(ctx, rankQuery, min).Scan(&lo); err != nil {
+		return 0, 0, err
+	}
+
+	if err = r.DB.QueryRowContext(ctx, rankQuery, max+1).Scan(&hi); err != nil {
+		return 0, 0, err
+	}
+
+	return lo, hi, nil
+}
+
+// primesAt returns the primes at the input zero-based ranks, skipping the ranks past the last prime.
+func (r Repository) primesAt(ctx context.Context, ranks []int64) ([]int64, error) {
+	rows, err := r.DB.QueryContext(ctx, primesAtQuery, ranks)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	ns := make([]int64, 0, len(ranks))
+
+	for rows.Next() {
+		var n int64
+
+		if err := rows.Scan(&n); err != nil {
+			return nil, err
+		}
+
+		ns = append(ns, n)
+	}
+
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ns, nil
+}
+
+func (r Repository) queryPrime(ctx context.Context, query string, n int64) (int64, error) {
+	var prime int64
+
+	if err := r.DB.QueryRowContext(ctx, query, n).Scan(&prime); err != nil {
+		return 0, err
+	}
+
+	return prime, nil
+}
+
+// NewRepository creates a Repository over db, which must hold the rank checkpoints written by
+// database.PartitionPostgres.
+func NewRepository(db *sql.DB) (Repository, error) {
+	var ok bool
+
+	if err := db.QueryRowContext(context.Background(), hasRanksQuery).Scan(&ok); err != nil {
+		return Repository{}, err
+	}
+
+	if !ok {
+		return Repository{}, ErrMissingRanks
+	}
+
+	return Repository{db}, nil
+}
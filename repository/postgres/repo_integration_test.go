@@ -0,0 +1,117 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/database"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+// TestRepository runs against the PostgreSQL instance in PRIMES_TEST_POSTGRES_URI, e.g. as started from
+// scripts/build-postgres/docker-compose.yaml.
+func TestRepository(t *testing.T) {
+	uri := os.Getenv("PRIMES_TEST_POSTGRES_URI")
+	if uri == "" {
+		t.Skip("PRIMES_TEST_POSTGRES_URI is not set")
+	}
+
+	ctx := context.Background()
+	logger := log.NoOp()
+
+	input := t.TempDir()
+	primes := sieve.BasePrimes(1_000_000)
+
+	sb := &strings.Builder{}
+	for i := range primes {
+		sb.WriteString(strconv.FormatInt(primes[i], 10))
+		sb.WriteByte('\n')
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(input, "primes-aa"), []byte(sb.String()), 0o644))
+
+	db, err := database.OpenPostgres(uri, logger)
+	require.NoError(t, err)
+	require.NoError(t, database.PartitionPostgres(ctx, db, 250_000, input, database.Validation{}, logger))
+
+	repo, err := NewRepository(db)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, repo.Close())
+	}()
+
+	for i := 0; i < 100; i++ {
+		n, err := repo.Random(ctx, 100_000, 900_000)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, n, int64(100_000))
+		require.LessOrEqual(t, n, int64(900_000))
+	}
+
+	ns, err := repo.List(ctx, 999_900, 1_000_000, 50)
+	require.NoError(t, err)
+	require.Len(t, ns, 50)
+
+	_, err = repo.Random(ctx, 24, 28)
+	require.ErrorIs(t, err, ErrNoPrimesInRange)
+
+	t.Run("Uniform", func(t *testing.T) {
+		// a prime following a large gap, like 23, is as likely as one following a small gap, like 3
+		counts := make(map[int64]int, 10)
+
+		ns, err := repo.List(ctx, 2, 30, 10_000)
+		require.NoError(t, err)
+
+		for _, n := range ns {
+			counts[n]++
+		}
+
+		require.Len(t, counts, 10)
+
+		for n, count := range counts {
+			require.InDelta(t, 1_000, count, 200, "prime: %d", n)
+		}
+	})
+
+	t.Run("Lookups", func(t *testing.T) {
+		ok, err := repo.IsPrime(ctx, 999_983)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		next, err := repo.Next(ctx, 249_999)
+		require.NoError(t, err)
+		require.Equal(t, int64(250_007), next)
+
+		prev, err := repo.Previous(ctx, 250_007)
+		require.NoError(t, err)
+		require.Equal(t, int64(249_989), prev)
+
+		_, err = repo.Next(ctx, 999_983)
+		require.ErrorIs(t, err, ErrNoPrimesInRange)
+
+		count, err := repo.Count(ctx, 0, 1_000_000)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(primes)), count)
+
+		count, err = repo.Count(ctx, 100_000, 900_000)
+		require.NoError(t, err)
+		require.Equal(t, int64(61_682), count)
+
+		for _, n := range []int64{1, 1_024, 1_025, 78_498} {
+			prime, err := repo.Nth(ctx, n)
+			require.NoError(t, err)
+			require.Equal(t, primes[n-1], prime)
+		}
+
+		_, err = repo.Nth(ctx, 78_499)
+		require.ErrorIs(t, err, ErrOutOfBounds)
+	})
+}
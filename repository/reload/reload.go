@@ -0,0 +1,307 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/zalgonoise/tendigitprimes/primes"
+)
+
+var (
+	ErrClosed      = errors.New("repository is closed")
+	ErrUnsupported = fmt.Errorf("%w: lookup is not supported by the current dataset", primes.ErrUnsupported)
+)
+
+type Repository interface {
+	Random(ctx context.Context, min, max int64) (int64, error)
+	List(ctx context.Context, min, max, limit int64) ([]int64, error)
+	Close() error
+}
+
+// Deterministic queries are each forwarded to the current dataset if its repository supports them, as some formats only
+// support a subset of them, like the sieve format, which only supports IsPrime.
+type (
+	primeChecker interface {
+		IsPrime(ctx context.Context, n int64) (bool, error)
+	}
+
+	nextFinder interface {
+		Next(ctx context.Context, n int64) (int64, error)
+	}
+
+	previousFinder interface {
+		Previous(ctx context.Context, n int64) (int64, error)
+	}
+
+	counter interface {
+		Count(ctx context.Context, min, max int64) (int64, error)
+	}
+
+	ranker interface {
+		Nth(ctx context.Context, n int64) (int64, error)
+	}
+)
+
+type Metrics interface {
+	IncDatasetReloads()
+	IncDatasetReloadsFailed()
+}
+
+// Dataset is a loaded repository, along with the collectors that export metrics on its underlying resources, such as
+// database connection stats.
+type Dataset struct {
+	Repo       Repository
+	Collectors []prometheus.Collector
+}
+
+// Loader opens a new Dataset.
+type Loader func(ctx context.Context) (Dataset, error)
+
+// SwapHook is called after a new Dataset is swapped in, with its version, to invalidate any state derived from the
+// previous one, like caches or pre-sampled primes.
+type SwapHook func(ctx context.Context, version uint64)
+
+// generation is a Dataset that is served until it is replaced by a reload. In-flight requests hold a read lock on
+// it, so that retiring it waits for them to finish.
+type generation struct {
+	mu      sync.RWMutex
+	retired bool
+	version uint64
+
+	Dataset
+}
+
+// retire waits for in-flight requests on g to finish, and closes its repository. Requests that load g afterward will
+// retry on the current generation.
+func (g *generation) retire() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.retired = true
+
+	return g.Repo.Close()
+}
+
+// Reloader is a primes.Repository decorator that allows swapping the underlying dataset while serving requests.
+//
+// On Reload, a new Dataset is loaded and swapped in atomically, bumping the dataset version and calling the SwapHook
+// set with OnSwap; the previous one is closed once its in-flight requests finish. Reloader also implements
+// prometheus.Collector, forwarding to the collectors of the current Dataset.
+type Reloader struct {
+	load    Loader
+	current atomic.Pointer[generation]
+
+	// mu serializes reloads, closing, and setting hooks
+	mu     sync.Mutex
+	closed bool
+	hooks  []SwapHook
+
+	m      Metrics
+	logger *slog.Logger
+}
+
+func (r *Reloader) Random(ctx context.Context, min, max int64) (int64, error) {
+	g, err := r.acquire()
+	if err != nil {
+		return 0, err
+	}
+
+	defer g.mu.RUnlock()
+
+	return g.Repo.Random(ctx, min, max)
+}
+
+func (r *Reloader) List(ctx context.Context, min, max, limit int64) ([]int64, error) {
+	g, err := r.acquire()
+	if err != nil {
+		return nil, err
+	}
+
+	defer g.mu.RUnlock()
+
+	return g.Repo.List(ctx, min, max, limit)
+}
+
+func (r *Reloader) IsPrime(ctx context.Context, n int64) (bool, error) {
+	return lookup(r, func(l primeChecker) (bool, error) {
+		return l.IsPrime(ctx, n)
+	})
+}
+
+func (r *Reloader) Next(ctx context.Context, n int64) (int64, error) {
+	return lookup(r, func(l nextFinder) (int64, error) {
+		return l.Next(ctx, n)
+	})
+}
+
+func (r *Reloader) Previous(ctx context.Context, n int64) (int64, error) {
+	return lookup(r, func(l previousFinder) (int64, error) {
+		return l.Previous(ctx, n)
+	})
+}
+
+func (r *Reloader) Count(ctx context.Context, min, max int64) (int64, error) {
+	return lookup(r, func(l counter) (int64, error) {
+		return l.Count(ctx, min, max)
+	})
+}
+
+func (r *Reloader) Nth(ctx context.Context, n int64) (int64, error) {
+	return lookup(r, func(l ranker) (int64, error) {
+		return l.Nth(ctx, n)
+	})
+}
+
+// Version returns the version of the current dataset, starting at zero for the initial one and bumped on each reload.
+func (r *Reloader) Version() uint64 {
+	return r.current.Load().version
+}
+
+// OnSwap adds a hook to call after each successful reload, once the new dataset is serving.
+func (r *Reloader) OnSwap(hook SwapHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.hooks = append(r.hooks, hook)
+}
+
+// Close waits for in-flight requests to finish, and closes the current dataset.
+func (r *Reloader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	r.closed = true
+
+	return r.current.Load().retire()
+}
+
+// Reload loads a new dataset and swaps it in, closing the previous one once its in-flight requests finish. If loading
+// fails, the current dataset is kept.
+func (r *Reloader) Reload(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return ErrClosed
+	}
+
+	start := time.Now()
+
+	dataset, err := r.load(ctx)
+	if err != nil {
+		r.m.IncDatasetReloadsFailed()
+		r.logger.ErrorContext(ctx, "failed to reload dataset, keeping the current one",
+			slog.String("error", err.Error()),
+		)
+
+		return err
+	}
+
+	version := r.current.Load().version + 1
+	prev := r.current.Swap(&generation{Dataset: dataset, version: version})
+
+	r.m.IncDatasetReloads()
+	r.logger.InfoContext(ctx, "reloaded dataset",
+		slog.Uint64("version", version),
+		slog.Duration("time_elapsed", time.Since(start)),
+	)
+
+	for i := range r.hooks {
+		r.hooks[i](ctx, version)
+	}
+
+	if err = prev.retire(); err != nil {
+		// the new dataset is already serving, so failing to close the previous one is not fatal
+		r.logger.WarnContext(ctx, "failed to close previous dataset", slog.String("error", err.Error()))
+	}
+
+	return nil
+}
+
+func (r *Reloader) Describe(descs chan<- *prometheus.Desc) {
+	g := r.current.Load()
+
+	for i := range g.Collectors {
+		g.Collectors[i].Describe(descs)
+	}
+}
+
+func (r *Reloader) Collect(metrics chan<- prometheus.Metric) {
+	g, err := r.acquire()
+	if err != nil {
+		return
+	}
+
+	defer g.mu.RUnlock()
+
+	for i := range g.Collectors {
+		g.Collectors[i].Collect(metrics)
+	}
+}
+
+// acquire read-locks the current generation. The caller must release it with g.mu.RUnlock.
+func (r *Reloader) acquire() (*generation, error) {
+	for {
+		g := r.current.Load()
+
+		g.mu.RLock()
+
+		if !g.retired {
+			return g, nil
+		}
+
+		g.mu.RUnlock()
+
+		// a retired generation is only current once the Reloader is closed
+		if r.current.Load() == g {
+			return nil, ErrClosed
+		}
+	}
+}
+
+// lookup runs fn on the current generation's repository, if it implements L.
+func lookup[L, T any](r *Reloader, fn func(L) (T, error)) (T, error) {
+	var zero T
+
+	g, err := r.acquire()
+	if err != nil {
+		return zero, err
+	}
+
+	defer g.mu.RUnlock()
+
+	l, ok := g.Repo.(L)
+	if !ok {
+		return zero, ErrUnsupported
+	}
+
+	return fn(l)
+}
+
+// New creates a Reloader, loading its initial dataset with load.
+func New(ctx context.Context, load Loader, m Metrics, logger *slog.Logger) (*Reloader, error) {
+	dataset, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reloader{
+		load:   load,
+		m:      m,
+		logger: logger,
+	}
+
+	r.current.Store(&generation{Dataset: dataset})
+
+	return r, nil
+}
@@ -0,0 +1,275 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/primes"
+)
+
+var errTest = errors.New("test error")
+
+type testRepository struct {
+	value int64
+
+	// block, if set, holds requests until it is closed
+	block   chan struct{}
+	started chan struct{}
+
+	closed atomic.Bool
+}
+
+func (r *testRepository) Random(context.Context, int64, int64) (int64, error) {
+	if r.block != nil {
+		close(r.started)
+		<-r.block
+	}
+
+	return r.value, nil
+}
+
+func (r *testRepository) List(ctx context.Context, min, max, limit int64) ([]int64, error) {
+	n, err := r.Random(ctx, min, max)
+
+	return []int64{n}, err
+}
+
+func (r *testRepository) Close() error {
+	r.closed.Store(true)
+
+	return nil
+}
+
+// lookupRepository is a testRepository that supports lookups, with value as its only prime.
+type lookupRepository struct {
+	testRepository
+}
+
+func (r *lookupRepository) IsPrime(_ context.Context, n int64) (bool, error) {
+	return n == r.value, nil
+}
+func (r *lookupRepository) Next(context.Context, int64) (int64, error)         { return r.value, nil }
+func (r *lookupRepository) Previous(context.Context, int64) (int64, error)     { return r.value, nil }
+func (r *lookupRepository) Count(context.Context, int64, int64) (int64, error) { return 1, nil }
+func (r *lookupRepository) Nth(context.Context, int64) (int64, error)          { return r.value, nil }
+
+// primeCheckerRepository is a testRepository that only supports IsPrime, like the sieve format.
+type primeCheckerRepository struct {
+	testRepository
+}
+
+func (r *primeCheckerRepository) IsPrime(_ context.Context, n int64) (bool, error) {
+	return n == r.value, nil
+}
+
+type testMetrics struct {
+	reloads atomic.Int64
+	failed  atomic.Int64
+}
+
+func (m *testMetrics) IncDatasetReloads()       { m.reloads.Add(1) }
+func (m *testMetrics) IncDatasetReloadsFailed() { m.failed.Add(1) }
+
+// testLoader returns the input datasets in order, or errTest once there are none left.
+func testLoader(repos ...*testRepository) Loader {
+	var (
+		mu  sync.Mutex
+		idx int
+	)
+
+	return func(context.Context) (Dataset, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if idx >= len(repos) {
+			return Dataset{}, errTest
+		}
+
+		idx++
+
+		return Dataset{Repo: repos[idx-1]}, nil
+	}
+}
+
+func TestReloader(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Swap", func(t *testing.T) {
+		first := &testRepository{value: 2}
+		second := &testRepository{value: 3}
+		m := &testMetrics{}
+
+		r, err := New(ctx, testLoader(first, second), m, log.NoOp())
+		require.NoError(t, err)
+
+		n, err := r.Random(ctx, 0, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+
+		require.NoError(t, r.Reload(ctx))
+		require.True(t, first.closed.Load())
+		require.Equal(t, int64(1), m.reloads.Load())
+
+		ns, err := r.List(ctx, 0, 10, 1)
+		require.NoError(t, err)
+		require.Equal(t, []int64{3}, ns)
+	})
+
+	t.Run("FailedReloadKeepsDataset", func(t *testing.T) {
+		first := &testRepository{value: 2}
+		m := &testMetrics{}
+
+		r, err := New(ctx, testLoader(first), m, log.NoOp())
+		require.NoError(t, err)
+
+		require.ErrorIs(t, r.Reload(ctx), errTest)
+		require.False(t, first.closed.Load())
+		require.Equal(t, int64(1), m.failed.Load())
+
+		n, err := r.Random(ctx, 0, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+	})
+
+	t.Run("DrainsInFlightRequests", func(t *testing.T) {
+		first := &testRepository{value: 2, block: make(chan struct{}), started: make(chan struct{})}
+		second := &testRepository{value: 3}
+
+		r, err := New(ctx, testLoader(first, second), &testMetrics{}, log.NoOp())
+		require.NoError(t, err)
+
+		results := make(chan int64, 1)
+
+		go func() {
+			n, _ := r.Random(ctx, 0, 10)
+			results <- n
+		}()
+
+		<-first.started
+
+		reloaded := make(chan error, 1)
+
+		go func() {
+			reloaded <- r.Reload(ctx)
+		}()
+
+		// the first dataset must not be closed while a request is running on it
+		time.Sleep(20 * time.Millisecond)
+		require.False(t, first.closed.Load())
+
+		close(first.block)
+
+		require.Equal(t, int64(2), <-results)
+		require.NoError(t, <-reloaded)
+		require.True(t, first.closed.Load())
+
+		n, err := r.Random(ctx, 0, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), n)
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		first := &testRepository{value: 2}
+
+		r, err := New(ctx, testLoader(first), &testMetrics{}, log.NoOp())
+		require.NoError(t, err)
+
+		require.NoError(t, r.Close())
+		require.True(t, first.closed.Load())
+
+		_, err = r.Random(ctx, 0, 10)
+		require.ErrorIs(t, err, ErrClosed)
+		require.ErrorIs(t, r.Reload(ctx), ErrClosed)
+	})
+
+	t.Run("Collectors", func(t *testing.T) {
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge"})
+
+		r, err := New(ctx, func(context.Context) (Dataset, error) {
+			return Dataset{Repo: &testRepository{}, Collectors: []prometheus.Collector{gauge}}, nil
+		}, &testMetrics{}, log.NoOp())
+		require.NoError(t, err)
+
+		registry := prometheus.NewRegistry()
+		require.NoError(t, registry.Register(r))
+
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		require.Len(t, families, 1)
+		require.Equal(t, "test_gauge", families[0].GetName())
+	})
+
+	t.Run("SwapHooks", func(t *testing.T) {
+		r, err := New(ctx, testLoader(&testRepository{value: 2}, &testRepository{value: 3}), &testMetrics{},
+			log.NoOp())
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), r.Version())
+
+		var versions []uint64
+
+		r.OnSwap(func(ctx context.Context, version uint64) {
+			// hooks run once the new dataset is serving
+			n, err := r.Random(ctx, 0, 10)
+			require.NoError(t, err)
+			require.Equal(t, int64(3), n)
+
+			versions = append(versions, version)
+		})
+
+		require.NoError(t, r.Reload(ctx))
+		require.ErrorIs(t, r.Reload(ctx), errTest)
+
+		require.Equal(t, []uint64{1}, versions)
+		require.Equal(t, uint64(1), r.Version())
+	})
+
+	t.Run("Lookups", func(t *testing.T) {
+		loader := func() Loader {
+			var loaded bool
+
+			return func(context.Context) (Dataset, error) {
+				if loaded {
+					return Dataset{Repo: &primeCheckerRepository{testRepository{value: 3}}}, nil
+				}
+
+				loaded = true
+
+				return Dataset{Repo: &lookupRepository{testRepository{value: 2}}}, nil
+			}
+		}()
+
+		r, err := New(ctx, loader, &testMetrics{}, log.NoOp())
+		require.NoError(t, err)
+
+		ok, err := r.IsPrime(ctx, 2)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		n, err := r.Nth(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+
+		// the reloaded dataset only supports IsPrime
+		require.NoError(t, r.Reload(ctx))
+
+		ok, err = r.IsPrime(ctx, 3)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		_, err = r.Next(ctx, 2)
+		require.ErrorIs(t, err, ErrUnsupported)
+		require.ErrorIs(t, err, primes.ErrUnsupported)
+	})
+
+	t.Run("InitialLoadFails", func(t *testing.T) {
+		_, err := New(ctx, testLoader(), &testMetrics{}, log.NoOp())
+		require.ErrorIs(t, err, errTest)
+	})
+}
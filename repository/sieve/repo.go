@@ -0,0 +1,132 @@
+package sieve
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/zalgonoise/tendigitprimes/primes"
+	primesieve "github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+const (
+	defaultLimit  = 5000
+	defaultWindow = 1 << 12
+
+	maxValue  = 9_999_999_999
+	baseLimit = 100_000
+)
+
+var (
+	ErrNoPrimesInRange = fmt.Errorf("%w: no prime numbers within the requested range", primes.ErrNotFound)
+	ErrOutOfBounds     = fmt.Errorf("%w: value is out of bounds", primes.ErrOutOfRange)
+)
+
+// Repository serves prime numbers without any storage, by sieving small windows of values on demand. It keeps a table
+// of base primes up to 10^5, enough to sieve any value up to 10^10.
+type Repository struct {
+	base   []int64
+	window int64
+}
+
+// Random picks a uniformly random starting point within [min, max], and returns the first prime found from it,
+// wrapping around to min if there are no primes between the starting point and max.
+func (r Repository) Random(ctx context.Context, min, max int64) (int64, error) {
+	min, max = clamp(min, max)
+	if max < min {
+		return 0, fmt.Errorf("%w: [%d, %d]", ErrNoPrimesInRange, min, max)
+	}
+
+	start := min + rand.Int64N(max-min+1)
+
+	n, ok, err := r.next(ctx, start, max)
+	if err != nil || ok {
+		return n, err
+	}
+
+	n, ok, err = r.next(ctx, min, start-1)
+	if err != nil || ok {
+		return n, err
+	}
+
+	return 0, fmt.Errorf("%w: [%d, %d]", ErrNoPrimesInRange, min, max)
+}
+
+func (r Repository) List(ctx context.Context, min, max, limit int64) ([]int64, error) {
+	if limit == 0 {
+		limit = defaultLimit
+	}
+
+	results := make([]int64, 0, limit)
+
+	for int64(len(results)) < limit {
+		n, err := r.Random(ctx, min, max)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, n)
+	}
+
+	return results, nil
+}
+
+// IsPrime returns whether n is a prime number, by trial division against the base primes table.
+func (r Repository) IsPrime(_ context.Context, n int64) (bool, error) {
+	if n > maxValue {
+		return false, fmt.Errorf("%w: %d", ErrOutOfBounds, n)
+	}
+
+	if n < 2 {
+		return false, nil
+	}
+
+	for _, p := range r.base {
+		if p*p > n {
+			break
+		}
+
+		if n%p == 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (r Repository) Close() error {
+	return nil
+}
+
+func (r Repository) next(ctx context.Context, from, to int64) (int64, bool, error) {
+	for lo := from; lo <= to; lo += r.window {
+		if err := ctx.Err(); err != nil {
+			return 0, false, err
+		}
+
+		if primes := primesieve.Segment(lo, min(lo+r.window-1, to), r.base); len(primes) > 0 {
+			return primes[0], true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+func clamp(min, max int64) (int64, int64) {
+	if min < 2 {
+		min = 2
+	}
+
+	if max > maxValue {
+		max = maxValue
+	}
+
+	return min, max
+}
+
+func NewRepository() (Repository, error) {
+	return Repository{
+		base:   primesieve.BasePrimes(baseLimit),
+		window: defaultWindow,
+	}, nil
+}
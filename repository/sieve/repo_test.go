@@ -0,0 +1,80 @@
+package sieve
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	primesieve "github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+func TestRepository_Random(t *testing.T) {
+	repo, err := NewRepository()
+	require.NoError(t, err)
+
+	for _, testcase := range []struct {
+		name string
+		min  int64
+		max  int64
+	}{
+		{name: "Small", min: 2, max: 100},
+		{name: "TenDigits", min: 1_000_000_000, max: 5_000_000_000},
+		{name: "NarrowRange", min: 9_998_789_200, max: 9_998_789_210},
+		{name: "UpperBound", min: 9_999_999_900, max: 9_999_999_999},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				n, err := repo.Random(context.Background(), testcase.min, testcase.max)
+				require.NoError(t, err)
+				require.GreaterOrEqual(t, n, testcase.min)
+				require.LessOrEqual(t, n, testcase.max)
+
+				isPrime, err := repo.IsPrime(context.Background(), n)
+				require.NoError(t, err)
+				require.True(t, isPrime, "n: %d", n)
+			}
+		})
+	}
+
+	t.Run("NoPrimesInRange", func(t *testing.T) {
+		_, err := repo.Random(context.Background(), 24, 28)
+		require.ErrorIs(t, err, ErrNoPrimesInRange)
+	})
+}
+
+func TestRepository_List(t *testing.T) {
+	repo, err := NewRepository()
+	require.NoError(t, err)
+
+	ns, err := repo.List(context.Background(), 1_000_000_000, 1_000_100_000, 50)
+	require.NoError(t, err)
+	require.Len(t, ns, 50)
+
+	for i := range ns {
+		require.GreaterOrEqual(t, ns[i], int64(1_000_000_000))
+		require.LessOrEqual(t, ns[i], int64(1_000_100_000))
+	}
+}
+
+func TestRepository_IsPrime(t *testing.T) {
+	repo, err := NewRepository()
+	require.NoError(t, err)
+
+	const limit = 100_000
+
+	primes := primesieve.BasePrimes(limit)
+	isPrime := make(map[int64]bool, len(primes))
+
+	for i := range primes {
+		isPrime[primes[i]] = true
+	}
+
+	for n := int64(0); n <= limit; n++ {
+		ok, err := repo.IsPrime(context.Background(), n)
+		require.NoError(t, err)
+		require.Equal(t, isPrime[n], ok, "n: %d", n)
+	}
+
+	_, err = repo.IsPrime(context.Background(), 10_000_000_000)
+	require.ErrorIs(t, err, ErrOutOfBounds)
+}
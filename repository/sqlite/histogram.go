@@ -0,0 +1,244 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+)
+
+const (
+	querySelectBuckets = `SELECT id, min, max, total FROM buckets ORDER BY id, min;`
+
+	countBelowQuery = `SELECT count(*) FROM %sprimes WHERE prime < ?;`
+	countFromQuery  = `SELECT count(*) FROM %sprimes WHERE prime >= ? AND prime < ?;`
+)
+
+// bucket is a histogram entry for a fixed-width range of values within a partition.
+type bucket struct {
+	from  int64
+	to    int64
+	total int64
+
+	// rank is the number of primes in the partition that precede this bucket
+	rank int64
+}
+
+// span is a range of ranks [lo, hi) within a partition.
+type span struct {
+	part partition
+	lo   int64
+	hi   int64
+}
+
+// getBuckets loads the histogram in the index database, keyed by partition ID.
+func getBuckets(ctx context.Context, db *sql.DB) (map[string][]bucket, error) {
+	rows, err := db.QueryContext(ctx, querySelectBuckets)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	buckets := make(map[string][]bucket, minAlloc)
+
+	for rows.Next() {
+		var (
+			id string
+			b  bucket
+		)
+
+		if err = rows.Scan(&id, &b.from, &b.to, &b.total); err != nil {
+			return nil, err
+		}
+
+		if prev := buckets[id]; len(prev) > 0 {
+			b.rank = prev[len(prev)-1].rank + prev[len(prev)-1].total
+		}
+
+		buckets[id] = append(buckets[id], b)
+	}
+
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// hasHistogram returns whether all input partitions have a histogram. Since all partitions in a dataset are built
+// together, they either all have one or none do.
+func hasHistogram(parts []partition) bool {
+	for i := range parts {
+		if len(parts[i].buckets) == 0 {
+			return false
+		}
+	}
+
+	return len(parts) > 0
+}
+
+// rankOf returns the number of primes in the target partition that are lower than value.
+//
+// With a histogram, only the primes within value's bucket are counted; otherwise all primes up to value are.
+func rankOf(ctx context.Context, src source, target partition, value int64) (int64, error) {
+	switch {
+	case value <= target.from:
+		return 0, nil
+	case value > target.to:
+		return target.total, nil
+	}
+
+	var (
+		query = countBelowQuery
+		args  = []any{value}
+		rank  int64
+	)
+
+	if len(target.buckets) > 0 {
+		// the last bucket that starts at or before value
+		b := target.buckets[sort.Search(len(target.buckets), func(i int) bool {
+			return target.buckets[i].from > value
+		})-1]
+
+		if value == b.from {
+			return b.rank, nil
+		}
+
+		query = countFromQuery
+		args = []any{b.from, value}
+		rank = b.rank
+	}
+
+	db, schema, release, err := src.resolve(target)
+	if err != nil {
+		return 0, err
+	}
+
+	defer release()
+
+	var count int64
+
+	if err = db.QueryRowContext(ctx, fmt.Sprintf(query, schema), args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return rank + count, nil
+}
+
+// spansIn returns the ranks of the primes within [min, max] for each partition overlapping it, and their total.
+func spansIn(ctx context.Context, src source, parts []partition, min, max int64) ([]span, int64, error) {
+	spans := make([]span, 0, len(parts))
+
+	var total int64
+
+	for i := range parts {
+		if parts[i].to < min || parts[i].from > max {
+			continue
+		}
+
+		lo, err := rankOf(ctx, src, parts[i], min)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		hi, err := rankOf(ctx, src, parts[i], max+1)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if hi > lo {
+			spans = append(spans, span{part: parts[i], lo: lo, hi: hi})
+			total += hi - lo
+		}
+	}
+
+	return spans, total, nil
+}
+
+// sampleSpans returns a uniformly random prime among the input spans, holding total primes.
+func sampleSpans(ctx context.Context, src source, spans []span, total int64) (int64, error) {
+	rank := rand.Int64N(total)
+
+	for i := range spans {
+		if n := spans[i].hi - spans[i].lo; rank >= n {
+			rank -= n
+
+			continue
+		}
+
+		return primeAt(ctx, src, spans[i].part, spans[i].lo+rank)
+	}
+
+	// unreachable as long as total is the sum of all spans
+	return 0, fmt.Errorf("%w: rank out of range", ErrOutOfBounds)
+}
+
+// randomIn returns a random prime within [min, max] from the input partitions.
+//
+// When the partitions have a histogram, primes are sampled uniformly from their exact ranks in range;
+// otherwise a random partition in range is picked, and random primes are fetched from it until one is within range.
+func randomIn(ctx context.Context, src source, parts []partition, min, max int64) (int64, error) {
+	if !hasHistogram(parts) {
+		targets := scanPartitions(parts, min, max)
+		if len(targets) == 0 {
+			return 0, fmt.Errorf("%w: [%d, %d]", ErrNotFound, min, max)
+		}
+
+		return randomPrimeInRange(ctx, src, targets, rand.IntN(len(targets)), min, max)
+	}
+
+	spans, total, err := spansIn(ctx, src, parts, min, max)
+	if err != nil {
+		return 0, err
+	}
+
+	if total == 0 {
+		return 0, fmt.Errorf("%w: [%d, %d]", ErrNotFound, min, max)
+	}
+
+	return sampleSpans(ctx, src, spans, total)
+}
+
+// listIn returns limit random primes within [min, max] from the input partitions, in the same fashion as randomIn.
+func listIn(ctx context.Context, src source, parts []partition, min, max, limit int64) ([]int64, error) {
+	if limit == 0 {
+		limit = defaultLimit
+	}
+
+	if !hasHistogram(parts) {
+		targets := scanPartitions(parts, min, max)
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("%w: [%d, %d]", ErrNotFound, min, max)
+		}
+
+		return listRandomPrimes(ctx, src, targets, min, max, int(limit))
+	}
+
+	spans, total, err := spansIn(ctx, src, parts, min, max)
+	if err != nil {
+		return nil, err
+	}
+
+	if total == 0 {
+		return nil, fmt.Errorf("%w: [%d, %d]", ErrNotFound, min, max)
+	}
+
+	results := make([]int64, 0, limit)
+
+	for int64(len(results)) < limit {
+		n, err := sampleSpans(ctx, src, spans, total)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, n)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/database"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+func TestHistogram(t *testing.T) {
+	dir := newTestPartitions(t, 300_000, 100_000)
+	primes := sieve.BasePrimes(300_000)
+
+	index, partitions, err := database.OpenPartitions(dir, database.ReadOnlyPragmas(), database.VerifySampled, log.NoOp())
+	require.NoError(t, err)
+
+	repo, err := NewPartitionPool(index, partitions)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, repo.Close())
+	}()
+
+	ctx := context.Background()
+
+	between := func(min, max int64) []int64 {
+		ns := make([]int64, 0, len(primes))
+
+		for i := range primes {
+			if primes[i] >= min && primes[i] <= max {
+				ns = append(ns, primes[i])
+			}
+		}
+
+		return ns
+	}
+
+	t.Run("Buckets", func(t *testing.T) {
+		for _, part := range repo.parts {
+			require.Len(t, part.buckets, 100)
+
+			var total int64
+			for i := range part.buckets {
+				require.Equal(t, total, part.buckets[i].rank)
+				total += part.buckets[i].total
+			}
+
+			require.Equal(t, part.total, total)
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		for _, r := range [][2]int64{
+			{0, 300_000},
+			{1_500, 1_500},
+			{1_001, 1_999},
+			{99_000, 101_000},
+			{12_345, 287_654},
+			{200_000, 299_999},
+		} {
+			count, err := repo.Count(ctx, r[0], r[1])
+			require.NoError(t, err)
+			require.Equal(t, int64(len(between(r[0], r[1]))), count, "range: %v", r)
+		}
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		// a narrow range across a partition boundary, where all primes should be sampled
+		wants := between(99_980, 100_060)
+		seen := make(map[int64]bool, len(wants))
+
+		for i := 0; i < 500; i++ {
+			n, err := repo.Random(ctx, 99_980, 100_060)
+			require.NoError(t, err)
+			require.Contains(t, wants, n)
+
+			seen[n] = true
+		}
+
+		require.Len(t, seen, len(wants))
+	})
+
+	t.Run("List", func(t *testing.T) {
+		wants := between(123_400, 123_500)
+
+		ns, err := repo.List(ctx, 123_400, 123_500, 50)
+		require.NoError(t, err)
+		require.Len(t, ns, 50)
+
+		for i := range ns {
+			require.Contains(t, wants, ns[i])
+		}
+	})
+
+	t.Run("EmptyRange", func(t *testing.T) {
+		_, err := repo.Random(ctx, 24, 28)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("WithoutHistogram", func(t *testing.T) {
+		// as loaded from an index built before the histogram was introduced
+		parts := make([]partition, len(repo.parts))
+		for i := range repo.parts {
+			parts[i] = repo.parts[i]
+			parts[i].buckets = nil
+		}
+
+		for _, r := range [][2]int64{
+			{400_000, 500_000},
+			{24, 28},
+		} {
+			_, err := randomIn(ctx, repo, parts, r[0], r[1])
+			require.ErrorIs(t, err, ErrNotFound, "range: %v", r)
+
+			_, err = listIn(ctx, repo, parts, r[0], r[1], 10)
+			require.ErrorIs(t, err, ErrNotFound, "range: %v", r)
+		}
+
+		// a single prime in range is found by counting, after missing it at random
+		wants := between(99_900, 100_100)[0]
+
+		n, err := randomIn(ctx, repo, parts, wants, wants)
+		require.NoError(t, err)
+		require.Equal(t, wants, n)
+
+		single := parts[0]
+		single.total = 1
+
+		n, err = randomPrime(ctx, repo, single)
+		require.NoError(t, err)
+		require.Equal(t, primes[0], n)
+	})
+}
@@ -0,0 +1,217 @@
+package sqlite
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+type Metrics interface {
+	IncPartitionsOpened()
+	IncPartitionsEvicted()
+	SetPartitionsOpen(n int)
+}
+
+// Opener opens a connection pool to the partition with the input ID.
+type Opener func(id string) (*sql.DB, error)
+
+// LazyPartitionPool is a PartitionPool variant that opens partitions on first use, keeping at most a fixed number of
+// open partitions in a least-recently-used cache.
+//
+// Partitions are only evicted when no queries are running against them, so the cache may temporarily hold more open
+// partitions than its limit under heavy concurrency.
+type LazyPartitionPool struct {
+	parts  []partition
+	layout Layout
+	cache  *handleCache
+
+	Index *sql.DB
+}
+
+func (r *LazyPartitionPool) Random(ctx context.Context, min, max int64) (int64, error) {
+	return randomIn(ctx, r, r.parts, min, max)
+}
+
+func (r *LazyPartitionPool) List(ctx context.Context, min, max, limit int64) ([]int64, error) {
+	return listIn(ctx, r, r.parts, min, max, limit)
+}
+
+// Layout returns how the dataset is split into partitions.
+func (r *LazyPartitionPool) Layout() Layout {
+	return r.layout
+}
+
+func (r *LazyPartitionPool) Close() error {
+	return errors.Join(r.cache.close(), r.Index.Close())
+}
+
+func (r *LazyPartitionPool) resolve(target partition) (*sql.DB, string, func(), error) {
+	h, err := r.cache.acquire(target.id)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return h.db, "", func() { r.cache.release(h) }, nil
+}
+
+// NewLazyPartitionPool creates a LazyPartitionPool from the index database, opening partitions with open as they are
+// queried, and keeping up to maxOpen partitions open.
+func NewLazyPartitionPool(index *sql.DB, open Opener, maxOpen int, m Metrics) (*LazyPartitionPool, error) {
+	parts, err := getPartitions(index)
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := getLayout(context.Background(), index)
+	if err != nil {
+		return nil, err
+	}
+
+	if maxOpen <= 0 {
+		maxOpen = 1
+	}
+
+	return &LazyPartitionPool{
+		parts:  parts,
+		layout: layout,
+		cache: &handleCache{
+			maxOpen: maxOpen,
+			open:    open,
+			handles: make(map[string]*handle, maxOpen),
+			lru:     list.New(),
+			m:       m,
+		},
+		Index: index,
+	}, nil
+}
+
+type handle struct {
+	id   string
+	db   *sql.DB
+	refs int
+	elem *list.Element
+}
+
+type handleCache struct {
+	mu sync.Mutex
+
+	maxOpen int
+	open    Opener
+	handles map[string]*handle
+	lru     *list.List
+	opening singleflight.Group
+
+	m Metrics
+}
+
+// acquire returns the handle to the partition with the input ID, opening it if needed. Partitions are opened outside
+// the cache's lock, so that queries on open partitions are not held back by a partition being opened; concurrent
+// callers for the same partition share a single open.
+func (c *handleCache) acquire(id string) (*handle, error) {
+	for {
+		c.mu.Lock()
+
+		if h, ok := c.handles[id]; ok {
+			h.refs++
+			c.lru.MoveToFront(h.elem)
+			c.mu.Unlock()
+
+			return h, nil
+		}
+
+		c.mu.Unlock()
+
+		opened, err, _ := c.opening.Do(id, func() (any, error) {
+			db, err := c.open(id)
+			if err != nil {
+				return nil, err
+			}
+
+			c.mu.Lock()
+			defer c.mu.Unlock()
+
+			// a previous open may have completed between the lookup and this one
+			if h, ok := c.handles[id]; ok {
+				_ = db.Close()
+
+				return h, nil
+			}
+
+			h := &handle{id: id, db: db}
+			h.elem = c.lru.PushFront(h)
+			c.handles[id] = h
+
+			c.m.IncPartitionsOpened()
+
+			return h, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+
+		// the handle is not referenced until this point, so it may have been evicted in the meantime
+		if h := opened.(*handle); c.handles[id] == h {
+			h.refs++
+			c.lru.MoveToFront(h.elem)
+			c.evict()
+			c.mu.Unlock()
+
+			return h, nil
+		}
+
+		c.mu.Unlock()
+	}
+}
+
+func (c *handleCache) release(h *handle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	h.refs--
+
+	c.evict()
+}
+
+// evict closes the least-recently-used partitions that are not in use, until the cache is within its limit.
+func (c *handleCache) evict() {
+	for e := c.lru.Back(); e != nil && c.lru.Len() > c.maxOpen; {
+		prev := e.Prev()
+
+		if h := e.Value.(*handle); h.refs == 0 {
+			c.lru.Remove(e)
+			delete(c.handles, h.id)
+
+			// reads against an immutable partition do not leave any state behind, so close errors are not actionable
+			_ = h.db.Close()
+
+			c.m.IncPartitionsEvicted()
+		}
+
+		e = prev
+	}
+
+	c.m.SetPartitionsOpen(c.lru.Len())
+}
+
+func (c *handleCache) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errs := make([]error, 0, len(c.handles))
+
+	for id, h := range c.handles {
+		errs = append(errs, h.db.Close())
+		delete(c.handles, id)
+	}
+
+	c.lru.Init()
+	c.m.SetPartitionsOpen(0)
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/database"
+	"github.com/zalgonoise/tendigitprimes/log"
+)
+
+type testMetrics struct {
+	mu      sync.Mutex
+	opened  int
+	evicted int
+	open    int
+}
+
+func (m *testMetrics) IncPartitionsOpened() {
+	m.mu.Lock()
+	m.opened++
+	m.mu.Unlock()
+}
+
+func (m *testMetrics) IncPartitionsEvicted() {
+	m.mu.Lock()
+	m.evicted++
+	m.mu.Unlock()
+}
+
+func (m *testMetrics) SetPartitionsOpen(n int) {
+	m.mu.Lock()
+	m.open = n
+	m.mu.Unlock()
+}
+
+func TestLazyPartitionPool(t *testing.T) {
+	dir := newTestPartitions(t, 300_000, 100_000)
+
+	index, err := database.OpenIndex(dir, database.ReadOnlyPragmas(), log.NoOp())
+	require.NoError(t, err)
+
+	m := &testMetrics{}
+
+	repo, err := NewLazyPartitionPool(index, func(id string) (*sql.DB, error) {
+		return database.OpenPartition(dir, id, database.ReadOnlyPragmas())
+	}, 2, m)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	t.Run("OpensOnFirstUse", func(t *testing.T) {
+		require.Zero(t, m.opened)
+
+		n, err := repo.Random(ctx, 10, 90_000)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, n, int64(10))
+		require.LessOrEqual(t, n, int64(90_000))
+
+		require.Equal(t, 1, m.opened)
+		require.Equal(t, 1, m.open)
+	})
+
+	t.Run("EvictsOverLimit", func(t *testing.T) {
+		ns, err := repo.List(ctx, 10, 299_999, 100)
+		require.NoError(t, err)
+		require.Len(t, ns, 100)
+
+		require.GreaterOrEqual(t, m.opened, 3)
+		require.GreaterOrEqual(t, m.evicted, 1)
+		require.LessOrEqual(t, m.open, 2)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		wg := &sync.WaitGroup{}
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				_, err := repo.List(ctx, 10, 299_999, 20)
+				require.NoError(t, err)
+			}()
+		}
+
+		wg.Wait()
+
+		require.LessOrEqual(t, m.open, 2)
+	})
+
+	require.NoError(t, repo.Close())
+	require.Zero(t, m.open)
+}
+
+func TestLazyPartitionPool_WithoutHistogram(t *testing.T) {
+	dir := newTestPartitions(t, 300_000, 100_000)
+	dropHistogram(t, dir)
+
+	index, err := database.OpenIndex(dir, database.ReadOnlyPragmas(), log.NoOp())
+	require.NoError(t, err)
+
+	m := &testMetrics{}
+
+	repo, err := NewLazyPartitionPool(index, func(id string) (*sql.DB, error) {
+		return database.OpenPartition(dir, id, database.ReadOnlyPragmas())
+	}, 2, m)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, repo.Close())
+	}()
+
+	ctx := context.Background()
+
+	require.False(t, hasHistogram(repo.parts))
+
+	_, err = repo.Random(ctx, 400_000, 500_000)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = repo.List(ctx, 400_000, 500_000, 10)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// no partition is opened for a range outside all of them
+	require.Zero(t, m.opened)
+}
+
+func TestHandleCache_OpenOutsideLock(t *testing.T) {
+	dir := newTestPartitions(t, 300_000, 100_000)
+
+	index, err := database.OpenIndex(dir, database.ReadOnlyPragmas(), log.NoOp())
+	require.NoError(t, err)
+
+	var (
+		m       = &testMetrics{}
+		blocked = make(chan struct{})
+		started = make(chan struct{}, 8)
+	)
+
+	repo, err := NewLazyPartitionPool(index, func(id string) (*sql.DB, error) {
+		if id == "01" {
+			started <- struct{}{}
+			<-blocked
+		}
+
+		return database.OpenPartition(dir, id, database.ReadOnlyPragmas())
+	}, 3, m)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, repo.Close())
+	}()
+
+	ctx := context.Background()
+
+	_, err = repo.Random(ctx, 10, 90_000)
+	require.NoError(t, err)
+
+	wg := &sync.WaitGroup{}
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := repo.Random(ctx, 110_000, 190_000)
+			require.NoError(t, err)
+		}()
+	}
+
+	<-started
+
+	// queries on open partitions are not held back by the partition being opened
+	_, err = repo.Random(ctx, 10, 90_000)
+	require.NoError(t, err)
+
+	close(blocked)
+	wg.Wait()
+
+	// concurrent queries on the same partition share a single open
+	require.Equal(t, 2, m.opened)
+	require.Empty(t, started)
+}
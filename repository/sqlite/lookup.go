@@ -0,0 +1,277 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/zalgonoise/tendigitprimes/primes"
+)
+
+const (
+	isPrimeQuery  = `SELECT EXISTS(SELECT 1 FROM %sprimes WHERE prime = ?);`
+	nextQuery     = `SELECT prime FROM %sprimes WHERE prime > ? ORDER BY prime LIMIT 1;`
+	previousQuery = `SELECT prime FROM %sprimes WHERE prime < ? ORDER BY prime DESC LIMIT 1;`
+	countQuery    = `SELECT count(*) FROM %sprimes WHERE prime BETWEEN ? AND ?;`
+	nthQuery      = `SELECT prime FROM %sprimes ORDER BY prime LIMIT 1 OFFSET ?;`
+)
+
+var (
+	ErrNotFound    = fmt.Errorf("%w: no prime number found", primes.ErrNotFound)
+	ErrOutOfBounds = fmt.Errorf("%w: value is out of bounds", primes.ErrOutOfRange)
+)
+
+// IsPrime returns whether n is a prime number in the dataset.
+func (r Repository) IsPrime(ctx context.Context, n int64) (bool, error) {
+	var ok bool
+
+	if err := r.DB.QueryRowContext(ctx, fmt.Sprintf(isPrimeQuery, ""), n).Scan(&ok); err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
+// Next returns the smallest prime in the dataset that is greater than n.
+func (r Repository) Next(ctx context.Context, n int64) (int64, error) {
+	return queryPrime(ctx, r.DB, fmt.Sprintf(nextQuery, ""), n)
+}
+
+// Previous returns the greatest prime in the dataset that is lower than n.
+func (r Repository) Previous(ctx context.Context, n int64) (int64, error) {
+	return queryPrime(ctx, r.DB, fmt.Sprintf(previousQuery, ""), n)
+}
+
+// Count returns the number of primes in the dataset within the [min, max] range.
+func (r Repository) Count(ctx context.Context, min, max int64) (int64, error) {
+	var count int64
+
+	if err := r.DB.QueryRowContext(ctx, fmt.Sprintf(countQuery, ""), min, max).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Nth returns the n-th prime in the dataset, where the first prime (2) has n = 1.
+func (r Repository) Nth(ctx context.Context, n int64) (int64, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("%w: %d", ErrOutOfBounds, n)
+	}
+
+	prime, err := queryPrime(ctx, r.DB, fmt.Sprintf(nthQuery, ""), n-1)
+	if errors.Is(err, ErrNotFound) {
+		return 0, fmt.Errorf("%w: %d", ErrOutOfBounds, n)
+	}
+
+	return prime, err
+}
+
+func (r *PartitionSet) IsPrime(ctx context.Context, n int64) (bool, error) {
+	return isPrimeIn(ctx, r, r.parts, r.layout, n)
+}
+
+func (r *PartitionSet) Next(ctx context.Context, n int64) (int64, error) {
+	return nextIn(ctx, r, r.parts, n)
+}
+
+func (r *PartitionSet) Previous(ctx context.Context, n int64) (int64, error) {
+	return previousIn(ctx, r, r.parts, n)
+}
+
+func (r *PartitionSet) Count(ctx context.Context, min, max int64) (int64, error) {
+	return countIn(ctx, r, r.parts, min, max)
+}
+
+func (r *PartitionSet) Nth(ctx context.Context, n int64) (int64, error) {
+	return nthIn(ctx, r, r.parts, n)
+}
+
+func (r *PartitionPool) IsPrime(ctx context.Context, n int64) (bool, error) {
+	return isPrimeIn(ctx, r, r.parts, r.layout, n)
+}
+
+func (r *PartitionPool) Next(ctx context.Context, n int64) (int64, error) {
+	return nextIn(ctx, r, r.parts, n)
+}
+
+func (r *PartitionPool) Previous(ctx context.Context, n int64) (int64, error) {
+	return previousIn(ctx, r, r.parts, n)
+}
+
+func (r *PartitionPool) Count(ctx context.Context, min, max int64) (int64, error) {
+	return countIn(ctx, r, r.parts, min, max)
+}
+
+func (r *PartitionPool) Nth(ctx context.Context, n int64) (int64, error) {
+	return nthIn(ctx, r, r.parts, n)
+}
+
+func (r *LazyPartitionPool) IsPrime(ctx context.Context, n int64) (bool, error) {
+	return isPrimeIn(ctx, r, r.parts, r.layout, n)
+}
+
+func (r *LazyPartitionPool) Next(ctx context.Context, n int64) (int64, error) {
+	return nextIn(ctx, r, r.parts, n)
+}
+
+func (r *LazyPartitionPool) Previous(ctx context.Context, n int64) (int64, error) {
+	return previousIn(ctx, r, r.parts, n)
+}
+
+func (r *LazyPartitionPool) Count(ctx context.Context, min, max int64) (int64, error) {
+	return countIn(ctx, r, r.parts, min, max)
+}
+
+func (r *LazyPartitionPool) Nth(ctx context.Context, n int64) (int64, error) {
+	return nthIn(ctx, r, r.parts, n)
+}
+
+func isPrimeIn(ctx context.Context, src source, parts []partition, layout Layout, n int64) (bool, error) {
+	i := locate(parts, layout, n)
+	if i < 0 {
+		return false, nil
+	}
+
+	db, schema, release, err := src.resolve(parts[i])
+	if err != nil {
+		return false, err
+	}
+
+	defer release()
+
+	var ok bool
+
+	err = db.QueryRowContext(ctx, fmt.Sprintf(isPrimeQuery, schema), n).Scan(&ok)
+
+	return ok, err
+}
+
+func nextIn(ctx context.Context, src source, parts []partition, n int64) (int64, error) {
+	for i := range parts {
+		if parts[i].to <= n {
+			continue
+		}
+
+		prime, err := queryPartition(ctx, src, parts[i], nextQuery, n)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+
+		return prime, err
+	}
+
+	return 0, fmt.Errorf("%w: after %d", ErrNotFound, n)
+}
+
+func previousIn(ctx context.Context, src source, parts []partition, n int64) (int64, error) {
+	for i := len(parts) - 1; i >= 0; i-- {
+		if parts[i].from >= n {
+			continue
+		}
+
+		prime, err := queryPartition(ctx, src, parts[i], previousQuery, n)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+
+		return prime, err
+	}
+
+	return 0, fmt.Errorf("%w: before %d", ErrNotFound, n)
+}
+
+func countIn(ctx context.Context, src source, parts []partition, min, max int64) (int64, error) {
+	var total int64
+
+	for i := range parts {
+		switch {
+		case parts[i].to < min || parts[i].from > max:
+			continue
+		case parts[i].from >= min && parts[i].to <= max:
+			// fully covered partitions are counted from the index
+			total += parts[i].total
+
+			continue
+		case len(parts[i].buckets) > 0:
+			// partially covered partitions with a histogram only count the primes in the buckets at the edges
+			lo, err := rankOf(ctx, src, parts[i], min)
+			if err != nil {
+				return 0, err
+			}
+
+			hi, err := rankOf(ctx, src, parts[i], max+1)
+			if err != nil {
+				return 0, err
+			}
+
+			total += hi - lo
+
+			continue
+		}
+
+		db, schema, release, err := src.resolve(parts[i])
+		if err != nil {
+			return 0, err
+		}
+
+		var count int64
+
+		err = db.QueryRowContext(ctx, fmt.Sprintf(countQuery, schema), min, max).Scan(&count)
+		release()
+
+		if err != nil {
+			return 0, err
+		}
+
+		total += count
+	}
+
+	return total, nil
+}
+
+func nthIn(ctx context.Context, src source, parts []partition, n int64) (int64, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("%w: %d", ErrOutOfBounds, n)
+	}
+
+	var offset int64
+
+	for i := range parts {
+		if n > offset+parts[i].total {
+			offset += parts[i].total
+
+			continue
+		}
+
+		return primeAt(ctx, src, parts[i], n-offset-1)
+	}
+
+	return 0, fmt.Errorf("%w: %d", ErrOutOfBounds, n)
+}
+
+func queryPartition(ctx context.Context, src source, target partition, query string, arg int64) (int64, error) {
+	db, schema, release, err := src.resolve(target)
+	if err != nil {
+		return 0, err
+	}
+
+	defer release()
+
+	return queryPrime(ctx, db, fmt.Sprintf(query, schema), arg)
+}
+
+func queryPrime(ctx context.Context, db *sql.DB, query string, args ...any) (int64, error) {
+	var n int64
+
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&n); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+
+		return 0, err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,142 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/database"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+type lookup interface {
+	IsPrime(ctx context.Context, n int64) (bool, error)
+	Next(ctx context.Context, n int64) (int64, error)
+	Previous(ctx context.Context, n int64) (int64, error)
+	Count(ctx context.Context, min, max int64) (int64, error)
+	Nth(ctx context.Context, n int64) (int64, error)
+}
+
+func testLookup(t *testing.T, repo lookup, primes []int64) {
+	ctx := context.Background()
+
+	rank := func(n int64) int {
+		return sort.Search(len(primes), func(i int) bool { return primes[i] >= n })
+	}
+
+	for _, n := range []int64{2, 3, 4, 99_991, 99_999, 100_000, 100_003, 150_001, 199_990} {
+		isPrime, err := repo.IsPrime(ctx, n)
+		require.NoError(t, err)
+		require.Equal(t, primes[rank(n)] == n, isPrime, "n: %d", n)
+
+		next, err := repo.Next(ctx, n)
+		require.NoError(t, err)
+		require.Equal(t, primes[rank(n+1)], next, "n: %d", n)
+
+		if n > 2 {
+			prev, err := repo.Previous(ctx, n)
+			require.NoError(t, err)
+			require.Equal(t, primes[rank(n)-1], prev, "n: %d", n)
+		}
+	}
+
+	_, err := repo.Previous(ctx, 2)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = repo.Next(ctx, primes[len(primes)-1])
+	require.ErrorIs(t, err, ErrNotFound)
+
+	for _, bounds := range [][2]int64{{2, 199_999}, {50_000, 150_000}, {99_990, 100_010}, {24, 28}} {
+		count, err := repo.Count(ctx, bounds[0], bounds[1])
+		require.NoError(t, err)
+		require.Equal(t, int64(rank(bounds[1]+1)-rank(bounds[0])), count, "bounds: %v", bounds)
+	}
+
+	for _, n := range []int64{1, 1000, 9592, 9593, int64(len(primes))} {
+		prime, err := repo.Nth(ctx, n)
+		require.NoError(t, err)
+		require.Equal(t, primes[n-1], prime, "n: %d", n)
+	}
+
+	_, err = repo.Nth(ctx, int64(len(primes))+1)
+	require.ErrorIs(t, err, ErrOutOfBounds)
+}
+
+func TestPartitionPool_Lookup(t *testing.T) {
+	dir := newTestPartitions(t, 200_000, 100_000)
+
+	index, partitions, err := database.OpenPartitions(dir, database.ReadOnlyPragmas(), database.VerifySampled, log.NoOp())
+	require.NoError(t, err)
+
+	repo, err := NewPartitionPool(index, partitions)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, repo.Close())
+	}()
+
+	testLookup(t, repo, sieve.BasePrimes(200_000))
+}
+
+func TestPartitionPool_LookupByCount(t *testing.T) {
+	dir := t.TempDir()
+	primes := sieve.BasePrimes(200_000)
+
+	data := make([]int, len(primes))
+	for i := range primes {
+		data[i] = int(primes[i])
+	}
+
+	layout := database.Layout{Strategy: database.StrategyCount, BlockSize: 5_000}
+
+	require.NoError(t, database.PartitionData(context.Background(), data, layout, 1, dir, log.NoOp()))
+
+	index, partitions, err := database.OpenPartitions(dir, database.ReadOnlyPragmas(), database.VerifySampled, log.NoOp())
+	require.NoError(t, err)
+	require.Len(t, partitions, 4)
+
+	repo, err := NewPartitionPool(index, partitions)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, repo.Close())
+	}()
+
+	require.Equal(t, Layout{Strategy: "count", BlockSize: 5_000}, repo.Layout())
+
+	testLookup(t, repo, primes)
+}
+
+func TestRepository_Lookup(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "raw")
+	primes := sieve.BasePrimes(200_000)
+
+	sb := &strings.Builder{}
+	for i := range primes {
+		sb.WriteString(strconv.FormatInt(primes[i], 10))
+		sb.WriteByte('\n')
+	}
+
+	require.NoError(t, os.Mkdir(input, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(input, "primes-aa"), []byte(sb.String()), 0o644))
+
+	db, err := database.OpenSQLite(filepath.Join(dir, "primes.db"), database.ReadWritePragmas(), log.NoOp())
+	require.NoError(t, err)
+	require.NoError(t, database.MigrateSQLite(context.Background(), db, input, database.Validation{}, log.NoOp()))
+
+	repo, err := NewRepository(db)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, repo.Close())
+	}()
+
+	testLookup(t, repo, primes)
+}
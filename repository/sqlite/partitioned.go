@@ -6,65 +6,117 @@ import (
 	"errors"
 	"fmt"
 	"math/rand/v2"
+	"sort"
 )
 
 const (
 	minAlloc = 64
 
-	querySelectScopes = `SELECT id, min, max, total FROM scopes;`
+	// maxRandomAttempts is the number of random primes fetched from a partition without a histogram to find one within
+	// range, before counting the primes in range instead
+	maxRandomAttempts = 64
 
-	primesPartitionedQuery = `SELECT prime FROM db%s.primes AS p
-	LIMIT 1 OFFSET %d;`
+	querySelectScopes       = `SELECT id, min, max, total, 0 FROM scopes;`
+	querySelectRankedScopes = `SELECT s.id, s.min, s.max, s.total, coalesce(r.interval, 0) FROM scopes AS s
+	LEFT JOIN rank_intervals AS r ON r.id = s.id;`
+	queryTableExists  = `SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type='table' AND name=?);`
+	querySelectLayout = `SELECT strategy, block_size FROM layout;`
+
+	// strategyWidth is the partitioning strategy of datasets split into partitions covering equal value ranges
+	strategyWidth = "width"
+
+	primesPartitionedQuery = `SELECT prime FROM %sprimes AS p
+	LIMIT 1 OFFSET ?;`
+
+	// primesRankedQuery jumps to a rank checkpoint by primary key, and only steps over the rows following it
+	primesRankedQuery = `SELECT prime FROM %[1]sprimes
+	WHERE prime >= (SELECT prime FROM %[1]sranks WHERE rank = ?)
+	ORDER BY prime LIMIT 1 OFFSET ?;`
 )
 
+// source resolves the connection pool and schema prefix to query for a given partition. The returned release
+// function must be called once the query is done.
+type source interface {
+	resolve(target partition) (db *sql.DB, schema string, release func(), err error)
+}
+
+func noRelease() {}
+
 type partition struct {
 	from  int64
 	to    int64
 	total int64
 	id    string
+
+	// interval is the number of primes between checkpoints in the partition's ranks table, or zero if it has none
+	interval int64
+	// buckets is the partition's histogram, in increasing order, or nil if the index has none
+	buckets []bucket
+}
+
+// Layout describes how a partitioned dataset is split into partitions, as recorded in its index by the builder.
+type Layout struct {
+	// Strategy is the partitioning strategy the dataset was built with, or empty if its index does not record one.
+	Strategy string
+	// BlockSize is the value range covered by each partition with the width strategy, or the number of primes held by
+	// each partition with the count strategy. It is zero for datasets split at arbitrary boundaries.
+	BlockSize int64
 }
 
 type PartitionSet struct {
-	parts []partition
+	parts  []partition
+	layout Layout
 
 	DB *sql.DB
 }
 
 func (r *PartitionSet) Random(ctx context.Context, min, max int64) (int64, error) {
-	targets := scanPartitions(r.parts, min, max)
+	return randomIn(ctx, r, r.parts, min, max)
+}
 
-	t := targets[rand.IntN(len(targets))]
+// randomPrimeInRange fetches random primes from targets[i] until one is within [min, max]. Since the partition may
+// hold few or no primes in range, it samples the exact ranks in range of all targets after maxRandomAttempts misses.
+func randomPrimeInRange(ctx context.Context, src source, targets []partition, i int, min, max int64) (int64, error) {
+	for range maxRandomAttempts {
+		n, err := randomPrime(ctx, src, targets[i])
+		if err != nil {
+			return 0, err
+		}
 
-	return randomPrimeInRange(ctx, r.DB, t, min, max)
-}
+		if n >= min && n <= max {
+			return n, nil
+		}
+	}
 
-func randomPrimeInRange(ctx context.Context, db *sql.DB, target partition, min, max int64) (int64, error) {
-	n, err := randomPrime(ctx, db, target)
+	spans, total, err := spansIn(ctx, src, targets, min, max)
 	if err != nil {
 		return 0, err
 	}
 
-	if n < min || n > max {
-		return randomPrimeInRange(ctx, db, target, min, max)
+	if total == 0 {
+		return 0, fmt.Errorf("%w: [%d, %d]", ErrNotFound, min, max)
 	}
 
-	return n, nil
+	return sampleSpans(ctx, src, spans, total)
 }
 
 func (r *PartitionSet) List(ctx context.Context, min, max, limit int64) ([]int64, error) {
-	if limit == 0 {
-		limit = defaultLimit
-	}
-
-	targets := scanPartitions(r.parts, min, max)
+	return listIn(ctx, r, r.parts, min, max, limit)
+}
 
-	return listRandomPrimes(ctx, r.DB, targets, min, max, int(limit))
+// Layout returns how the dataset is split into partitions.
+func (r *PartitionSet) Layout() Layout {
+	return r.layout
 }
 
 func (r *PartitionSet) Close() error {
 	return errors.Join(r.DB.Close())
 }
 
+func (r *PartitionSet) resolve(target partition) (*sql.DB, string, func(), error) {
+	return r.DB, "db" + target.id + ".", noRelease, nil
+}
+
 func scanPartitions(parts []partition, min, max int64) []partition {
 	targets := make([]partition, 0, len(parts))
 
@@ -82,13 +134,13 @@ func scanPartitions(parts []partition, min, max int64) []partition {
 	return targets
 }
 
-func listRandomPrimes(ctx context.Context, db *sql.DB, targets []partition, min, max int64, limit int) ([]int64, error) {
+func listRandomPrimes(ctx context.Context, src source, targets []partition, min, max int64, limit int) ([]int64, error) {
 	results := make([]int64, 0, limit)
 
 	var idx int
 
 	for len(results) < limit {
-		n, err := randomPrimeInRange(ctx, db, targets[idx], min, max)
+		n, err := randomPrimeInRange(ctx, src, targets, idx, min, max)
 		if err != nil {
 			return nil, err
 		}
@@ -101,24 +153,33 @@ func listRandomPrimes(ctx context.Context, db *sql.DB, targets []partition, min,
 	return results, nil
 }
 
-func randomPrime(ctx context.Context, db *sql.DB, target partition) (int64, error) {
-	offset := rand.Int64N(target.total - 1)
-
-	query := fmt.Sprintf(primesPartitionedQuery, target.id, offset)
-
-	row := db.QueryRowContext(ctx, query)
+func randomPrime(ctx context.Context, src source, target partition) (int64, error) {
+	if target.total <= 0 {
+		return 0, fmt.Errorf("%w: partition %s is empty", ErrNotFound, target.id)
+	}
 
-	var n int64
+	return primeAt(ctx, src, target, rand.Int64N(target.total))
+}
 
-	if err := row.Scan(&n); err != nil {
+// primeAt returns the prime with the input rank within the target partition, starting from zero.
+//
+// Partitions with a ranks table are queried from the closest checkpoint, stepping over fewer rows than the rank
+// interval; otherwise SQLite steps over all rows up to rank.
+func primeAt(ctx context.Context, src source, target partition, rank int64) (int64, error) {
+	db, schema, release, err := src.resolve(target)
+	if err != nil {
 		return 0, err
 	}
 
-	if err := row.Err(); err != nil {
-		return 0, err
+	defer release()
+
+	if target.interval <= 0 {
+		return queryPrime(ctx, db, fmt.Sprintf(primesPartitionedQuery, schema), rank)
 	}
 
-	return n, nil
+	checkpoint := rank - rank%target.interval
+
+	return queryPrime(ctx, db, fmt.Sprintf(primesRankedQuery, schema), checkpoint, rank-checkpoint)
 }
 
 func NewPartitionSet(db *sql.DB) (*PartitionSet, error) {
@@ -127,13 +188,29 @@ func NewPartitionSet(db *sql.DB) (*PartitionSet, error) {
 		return nil, err
 	}
 
-	return &PartitionSet{parts: parts, DB: db}, nil
+	layout, err := getLayout(context.Background(), db)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PartitionSet{parts: parts, layout: layout, DB: db}, nil
 }
 
 func getPartitions(db *sql.DB) ([]partition, error) {
 	ctx := context.Background()
 
-	rows, err := db.QueryContext(ctx, querySelectScopes)
+	// indexes built before rank checkpoints were introduced have no rank_intervals table
+	ranked, err := tableExists(ctx, db, "rank_intervals")
+	if err != nil {
+		return nil, err
+	}
+
+	query := querySelectScopes
+	if ranked {
+		query = querySelectRankedScopes
+	}
+
+	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -147,17 +224,19 @@ func getPartitions(db *sql.DB) ([]partition, error) {
 			id       string
 			from, to int64
 			total    int64
+			interval int64
 		)
 
-		if err = rows.Scan(&id, &from, &to, &total); err != nil {
+		if err = rows.Scan(&id, &from, &to, &total, &interval); err != nil {
 			return nil, err
 		}
 
 		parts = append(parts, partition{
-			from:  from,
-			to:    to,
-			total: total,
-			id:    id,
+			from:     from,
+			to:       to,
+			total:    total,
+			id:       id,
+			interval: interval,
 		})
 	}
 
@@ -169,9 +248,78 @@ func getPartitions(db *sql.DB) ([]partition, error) {
 		return nil, err
 	}
 
+	// likewise, indexes built before the histogram was introduced have no buckets table
+	hasBuckets, err := tableExists(ctx, db, "buckets")
+	if err != nil || !hasBuckets {
+		return parts, err
+	}
+
+	buckets, err := getBuckets(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range parts {
+		parts[i].buckets = buckets[parts[i].id]
+	}
+
 	return parts, nil
 }
 
+// getLayout returns the layout recorded in the index. Indexes built before partitioning strategies were introduced have
+// no layout table, and return an empty Layout.
+func getLayout(ctx context.Context, db *sql.DB) (Layout, error) {
+	ok, err := tableExists(ctx, db, "layout")
+	if err != nil || !ok {
+		return Layout{}, err
+	}
+
+	var layout Layout
+
+	err = db.QueryRowContext(ctx, querySelectLayout).Scan(&layout.Strategy, &layout.BlockSize)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return Layout{}, err
+	}
+
+	return layout, nil
+}
+
+// locate returns the index of the partition holding n, or -1 if none does. With the width strategy, the partition is
+// found from its offset to the first one; otherwise, partitions are binary searched by their range.
+func locate(parts []partition, layout Layout, n int64) int {
+	if len(parts) == 0 || n < parts[0].from || n > parts[len(parts)-1].to {
+		return -1
+	}
+
+	if layout.Strategy == strategyWidth && layout.BlockSize > 0 {
+		i := int((n - parts[0].from) / layout.BlockSize)
+
+		if i < len(parts) && n >= parts[i].from && n <= parts[i].to {
+			return i
+		}
+	}
+
+	i := sort.Search(len(parts), func(i int) bool {
+		return parts[i].to >= n
+	})
+
+	if i < len(parts) && n >= parts[i].from {
+		return i
+	}
+
+	return -1
+}
+
+func tableExists(ctx context.Context, db *sql.DB, table string) (bool, error) {
+	var ok bool
+
+	if err := db.QueryRowContext(ctx, queryTableExists, table).Scan(&ok); err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
 func contains(part partition, min, max int64) (isPresent bool, isOver bool) {
 	switch {
 	case part.from > max-1:
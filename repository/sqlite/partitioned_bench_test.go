@@ -27,7 +27,7 @@ func BenchmarkPartitionSet(b *testing.B) {
 
 	logger := log.New(c.LogLevel)
 
-	db, err := database.AttachSQLite(c.Database.URI, database.ReadOnlyPragmas(), logger)
+	db, err := database.AttachSQLite(c.Database.URI, database.ReadOnlyPragmas(), database.VerifySampled, logger)
 	require.NoError(b, err)
 
 	repo, err := NewPartitionSet(db)
@@ -27,7 +27,7 @@ func FuzzPartitionSet_Random(f *testing.F) {
 
 	logger := log.New(c.LogLevel)
 
-	db, err := database.AttachSQLite(c.Database.URI, database.ReadOnlyPragmas(), logger)
+	db, err := database.AttachSQLite(c.Database.URI, database.ReadOnlyPragmas(), database.VerifySampled, logger)
 	require.NoError(f, err)
 
 	repo, err := NewPartitionSet(db)
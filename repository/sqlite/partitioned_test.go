@@ -60,3 +60,31 @@ func TestContains(t *testing.T) {
 		})
 	}
 }
+
+func TestLocate(t *testing.T) {
+	width := []partition{{from: 0, to: 99, id: "00"}, {from: 100, to: 199, id: "01"}, {from: 200, to: 250, id: "02"}}
+	uneven := []partition{{from: 0, to: 9, id: "00"}, {from: 10, to: 149, id: "01"}, {from: 150, to: 250, id: "02"}}
+
+	for _, testcase := range []struct {
+		name   string
+		parts  []partition
+		layout Layout
+		n      int64
+		wants  int
+	}{
+		{name: "Width/First", parts: width, layout: Layout{Strategy: "width", BlockSize: 100}, n: 0, wants: 0},
+		{name: "Width/Edge", parts: width, layout: Layout{Strategy: "width", BlockSize: 100}, n: 199, wants: 1},
+		{name: "Width/Last", parts: width, layout: Layout{Strategy: "width", BlockSize: 100}, n: 250, wants: 2},
+		{name: "Width/Over", parts: width, layout: Layout{Strategy: "width", BlockSize: 100}, n: 251, wants: -1},
+		{name: "Count/First", parts: uneven, layout: Layout{Strategy: "count", BlockSize: 4}, n: 9, wants: 0},
+		{name: "Count/Middle", parts: uneven, layout: Layout{Strategy: "count", BlockSize: 4}, n: 10, wants: 1},
+		{name: "File/Last", parts: uneven, layout: Layout{Strategy: "file"}, n: 150, wants: 2},
+		{name: "Unknown/Middle", parts: uneven, n: 149, wants: 1},
+		{name: "Unknown/Under", parts: uneven, n: -1, wants: -1},
+		{name: "Empty", n: 1, wants: -1},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			require.Equal(t, testcase.wants, locate(testcase.parts, testcase.layout, testcase.n))
+		})
+	}
+}
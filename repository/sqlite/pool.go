@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrMissingPartition = errors.New("partition is registered in the index but was not opened")
+
+// PartitionPool is a PartitionSet variant that queries each partition through its own connection pool, instead of
+// attaching them to the index database.
+//
+// Since queries are routed in Go, it works with the stock SQLite driver, and it is not bound to SQLite's limit of
+// attached databases.
+type PartitionPool struct {
+	parts  []partition
+	layout Layout
+	dbs    map[string]*sql.DB
+
+	Index *sql.DB
+}
+
+func (r *PartitionPool) Random(ctx context.Context, min, max int64) (int64, error) {
+	return randomIn(ctx, r, r.parts, min, max)
+}
+
+func (r *PartitionPool) List(ctx context.Context, min, max, limit int64) ([]int64, error) {
+	return listIn(ctx, r, r.parts, min, max, limit)
+}
+
+// Layout returns how the dataset is split into partitions.
+func (r *PartitionPool) Layout() Layout {
+	return r.layout
+}
+
+func (r *PartitionPool) Close() error {
+	errs := make([]error, 0, len(r.dbs)+1)
+
+	for _, db := range r.dbs {
+		errs = append(errs, db.Close())
+	}
+
+	errs = append(errs, r.Index.Close())
+
+	return errors.Join(errs...)
+}
+
+func (r *PartitionPool) resolve(target partition) (*sql.DB, string, func(), error) {
+	return r.dbs[target.id], "", noRelease, nil
+}
+
+// NewPartitionPool creates a PartitionPool from the index database and a connection pool for each of its partitions,
+// keyed by partition ID, as returned by database.OpenPartitions.
+func NewPartitionPool(index *sql.DB, partitions map[string]*sql.DB) (*PartitionPool, error) {
+	parts, err := getPartitions(index)
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := getLayout(context.Background(), index)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range parts {
+		if _, ok := partitions[parts[i].id]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingPartition, parts[i].id)
+		}
+	}
+
+	return &PartitionPool{parts: parts, layout: layout, dbs: partitions, Index: index}, nil
+}
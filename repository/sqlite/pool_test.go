@@ -0,0 +1,166 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/database"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+func newTestPartitions(t *testing.T, limit int64, blockSize int) string {
+	dir := t.TempDir()
+	primes := sieve.BasePrimes(limit)
+
+	data := make([]int, len(primes))
+	for i := range primes {
+		data[i] = int(primes[i])
+	}
+
+	layout := database.WidthLayout(blockSize)
+
+	require.NoError(t, database.PartitionData(context.Background(), data, layout, 1, dir, log.NoOp()))
+
+	return dir
+}
+
+func TestPartitionPool(t *testing.T) {
+	dir := newTestPartitions(t, 300_000, 100_000)
+
+	index, partitions, err := database.OpenPartitions(dir, database.ReadOnlyPragmas(), database.VerifySampled, log.NoOp())
+	require.NoError(t, err)
+	require.Len(t, partitions, 3)
+
+	repo, err := NewPartitionPool(index, partitions)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, repo.Close())
+	}()
+
+	ctx := context.Background()
+
+	t.Run("Random", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			n, err := repo.Random(ctx, 150_000, 250_000)
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, n, int64(150_000))
+			require.LessOrEqual(t, n, int64(250_000))
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		ns, err := repo.List(ctx, 10, 299_999, 50)
+		require.NoError(t, err)
+		require.Len(t, ns, 50)
+
+		for i := range ns {
+			require.GreaterOrEqual(t, ns[i], int64(10))
+			require.LessOrEqual(t, ns[i], int64(299_999))
+		}
+	})
+}
+
+// dropHistogram removes the histogram from the index in dir, as with indexes built before it was introduced.
+func dropHistogram(t *testing.T, dir string) {
+	index, err := database.OpenIndex(dir, database.ReadWritePragmas(), log.NoOp())
+	require.NoError(t, err)
+
+	_, err = index.Exec(`DROP TABLE buckets;`)
+	require.NoError(t, err)
+	require.NoError(t, index.Close())
+}
+
+func TestPartitionPool_WithoutHistogram(t *testing.T) {
+	dir := newTestPartitions(t, 300_000, 100_000)
+	dropHistogram(t, dir)
+
+	index, partitions, err := database.OpenPartitions(dir, database.ReadOnlyPragmas(), database.VerifySampled, log.NoOp())
+	require.NoError(t, err)
+
+	repo, err := NewPartitionPool(index, partitions)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, repo.Close())
+	}()
+
+	ctx := context.Background()
+
+	require.False(t, hasHistogram(repo.parts))
+
+	n, err := repo.Random(ctx, 150_000, 250_000)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, n, int64(150_000))
+	require.LessOrEqual(t, n, int64(250_000))
+
+	_, err = repo.Random(ctx, 400_000, 500_000)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = repo.List(ctx, 400_000, 500_000, 10)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestNewPartitionPool_MissingPartition(t *testing.T) {
+	dir := newTestPartitions(t, 200_000, 100_000)
+
+	index, partitions, err := database.OpenPartitions(dir, database.ReadOnlyPragmas(), database.VerifySampled, log.NoOp())
+	require.NoError(t, err)
+
+	for id, db := range partitions {
+		require.NoError(t, db.Close())
+		delete(partitions, id)
+
+		break
+	}
+
+	_, err = NewPartitionPool(index, partitions)
+	require.ErrorIs(t, err, ErrMissingPartition)
+}
+
+func TestPrimeAt(t *testing.T) {
+	dir := newTestPartitions(t, 300_000, 100_000)
+	primes := sieve.BasePrimes(300_000)
+
+	index, partitions, err := database.OpenPartitions(dir, database.ReadOnlyPragmas(), database.VerifySampled, log.NoOp())
+	require.NoError(t, err)
+
+	repo, err := NewPartitionPool(index, partitions)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, repo.Close())
+	}()
+
+	ctx := context.Background()
+
+	for _, part := range repo.parts {
+		require.Positive(t, part.interval)
+
+		// checks ranks both with and without jumping to a checkpoint, across checkpoint boundaries
+		unranked := part
+		unranked.interval = 0
+
+		var offset int64
+
+		for i := range primes {
+			if primes[i] >= part.from {
+				offset = int64(i)
+
+				break
+			}
+		}
+
+		for _, rank := range []int64{0, 1, part.interval - 1, part.interval, part.interval + 1, part.total - 1} {
+			n, err := primeAt(ctx, repo, part, rank)
+			require.NoError(t, err)
+			require.Equal(t, primes[offset+rank], n, "partition: %s, rank: %d", part.id, rank)
+
+			n, err = primeAt(ctx, repo, unranked, rank)
+			require.NoError(t, err)
+			require.Equal(t, primes[offset+rank], n, "partition: %s, rank: %d", part.id, rank)
+		}
+	}
+}
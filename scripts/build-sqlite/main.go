@@ -42,7 +42,7 @@ func run(logger *slog.Logger) (int, error) {
 			return 1, err
 		}
 
-		if err = database.MigrateSQLite(ctx, db, *input, logger); err != nil {
+		if err = database.MigrateSQLite(ctx, db, *input, database.Validation{}, logger); err != nil {
 			return 1, err
 		}
 
@@ -53,7 +53,7 @@ func run(logger *slog.Logger) (int, error) {
 		return 0, nil
 	}
 
-	if err := database.Partition(ctx, 100_000_000, *input, *output, logger); err != nil {
+	if err := database.Partition(ctx, database.WidthLayout(100_000_000), 1, *input, *output, database.Validation{}, logger); err != nil {
 		return 1, err
 	}
 
@@ -0,0 +1,142 @@
+package sieve
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sync"
+)
+
+const (
+	// DefaultSegmentSize is the default width of the value range sieved by each worker at a time.
+	DefaultSegmentSize = 1 << 22
+
+	minAlloc = 64
+)
+
+// BasePrimes returns all primes lower or equal to limit, using a plain Sieve of Eratosthenes.
+func BasePrimes(limit int64) []int64 {
+	if limit < 2 {
+		return nil
+	}
+
+	composite := make([]bool, limit+1)
+	primes := make([]int64, 0, minAlloc)
+
+	for i := int64(2); i <= limit; i++ {
+		if composite[i] {
+			continue
+		}
+
+		primes = append(primes, i)
+
+		for j := i * i; j <= limit; j += i {
+			composite[j] = true
+		}
+	}
+
+	return primes
+}
+
+// BaseLimit returns the largest value that base primes must cover to sieve values up to limit.
+func BaseLimit(limit int64) int64 {
+	return int64(math.Sqrt(float64(limit))) + 1
+}
+
+// Segment returns all primes within the [lo, hi] range, crossing out multiples of the input base primes. base must
+// contain all primes lower or equal to the square root of hi.
+func Segment(lo, hi int64, base []int64) []int64 {
+	if lo < 2 {
+		lo = 2
+	}
+
+	if hi < lo {
+		return nil
+	}
+
+	composite := make([]bool, hi-lo+1)
+
+	for _, p := range base {
+		if p*p > hi {
+			break
+		}
+
+		start := ((lo + p - 1) / p) * p
+		if start < p*p {
+			start = p * p
+		}
+
+		for j := start; j <= hi; j += p {
+			composite[j-lo] = true
+		}
+	}
+
+	primes := make([]int64, 0, len(composite)/10+minAlloc)
+
+	for i := range composite {
+		if !composite[i] {
+			primes = append(primes, lo+int64(i))
+		}
+	}
+
+	return primes
+}
+
+// Generate runs a segmented Sieve of Eratosthenes up to limit, spreading segments of segmentSize values across a
+// number of workers. The primes found in each segment are passed to fn in increasing order.
+//
+// Memory usage is bounded by the number of workers and the segment size, as each round of segments is only sieved
+// once the previous round was consumed by fn.
+func Generate(ctx context.Context, limit int64, workers int, segmentSize int64, fn func([]int64) error) error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	if segmentSize <= 0 {
+		segmentSize = DefaultSegmentSize
+	}
+
+	base := BasePrimes(BaseLimit(limit))
+	results := make([][]int64, workers)
+
+	for lo := int64(0); lo <= limit; lo += segmentSize * int64(workers) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		wg := &sync.WaitGroup{}
+
+		for i := 0; i < workers; i++ {
+			segLo := lo + int64(i)*segmentSize
+			segHi := min(segLo+segmentSize-1, limit)
+
+			if segLo > limit {
+				results[i] = nil
+
+				continue
+			}
+
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				results[i] = Segment(segLo, segHi, base)
+			}(i)
+		}
+
+		wg.Wait()
+
+		for i := range results {
+			if len(results[i]) == 0 {
+				continue
+			}
+
+			if err := fn(results[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
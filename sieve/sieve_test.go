@@ -0,0 +1,79 @@
+package sieve
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSegment(t *testing.T) {
+	base := BasePrimes(BaseLimit(9_999_999_999))
+
+	for _, testcase := range []struct {
+		name  string
+		lo    int64
+		hi    int64
+		wants []int64
+	}{
+		{
+			name:  "Small",
+			lo:    0,
+			hi:    30,
+			wants: []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29},
+		},
+		{
+			name:  "TenDigits",
+			lo:    9_998_789_200,
+			hi:    9_998_789_243,
+			wants: []int64{9_998_789_201, 9_998_789_209, 9_998_789_219, 9_998_789_237, 9_998_789_243},
+		},
+		{
+			name:  "NoPrimes",
+			lo:    24,
+			hi:    28,
+			wants: []int64{},
+		},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			require.Equal(t, testcase.wants, Segment(testcase.lo, testcase.hi, base))
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	const limit = 1_000_003
+
+	wants := BasePrimes(limit)
+
+	for _, testcase := range []struct {
+		name        string
+		workers     int
+		segmentSize int64
+	}{
+		{name: "SingleWorker", workers: 1, segmentSize: 1 << 16},
+		{name: "MultipleWorkers", workers: 4, segmentSize: 10_007},
+		{name: "SegmentOverLimit", workers: 3, segmentSize: 1 << 22},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			primes := make([]int64, 0, len(wants))
+
+			require.NoError(t, Generate(context.Background(), limit, testcase.workers, testcase.segmentSize,
+				func(values []int64) error {
+					primes = append(primes, values...)
+
+					return nil
+				}))
+
+			require.Equal(t, wants, primes)
+		})
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		errTest := errors.New("test error")
+
+		err := Generate(context.Background(), limit, 2, 1<<16, func([]int64) error { return errTest })
+		require.ErrorIs(t, err, errTest)
+	})
+}
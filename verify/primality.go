@@ -0,0 +1,73 @@
+package verify
+
+import "math/bits"
+
+// witnesses is a set of Miller-Rabin bases that correctly classifies all 64-bit integers.
+var witnesses = []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+
+// IsPrime runs a deterministic Miller-Rabin primality test on n.
+func IsPrime(n int64) bool {
+	if n < 2 {
+		return false
+	}
+
+	v := uint64(n)
+
+	for _, p := range witnesses {
+		if v%p == 0 {
+			return v == p
+		}
+	}
+
+	// v - 1 = d * 2^s, with d odd
+	d := v - 1
+	s := bits.TrailingZeros64(d)
+	d >>= s
+
+	for _, a := range witnesses {
+		if !strongProbablePrime(v, a, d, s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func strongProbablePrime(n, a, d uint64, s int) bool {
+	x := powMod(a, d, n)
+	if x == 1 || x == n-1 {
+		return true
+	}
+
+	for i := 1; i < s; i++ {
+		x = mulMod(x, x, n)
+		if x == n-1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func powMod(base, exp, mod uint64) uint64 {
+	result := uint64(1)
+	base %= mod
+
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result = mulMod(result, base, mod)
+		}
+
+		base = mulMod(base, base, mod)
+	}
+
+	return result
+}
+
+// mulMod returns a * b % mod, without overflowing. a and b must be lower than mod.
+func mulMod(a, b, mod uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi, lo, mod)
+
+	return rem
+}
@@ -0,0 +1,40 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+func TestIsPrime(t *testing.T) {
+	t.Run("AgainstSieve", func(t *testing.T) {
+		primes := sieve.BasePrimes(100_000)
+
+		idx := 0
+		for n := int64(0); n <= 100_000; n++ {
+			isPrime := idx < len(primes) && primes[idx] == n
+			if isPrime {
+				idx++
+			}
+
+			require.Equal(t, isPrime, IsPrime(n), n)
+		}
+	})
+
+	for _, testcase := range []struct {
+		name    string
+		n       int64
+		isPrime bool
+	}{
+		{name: "LargestTenDigitPrime", n: 9_999_999_967, isPrime: true},
+		{name: "TenDigitComposite", n: 9_999_999_969},
+		{name: "StrongPseudoprimeBases2To7", n: 3_215_031_751},
+		{name: "LargestInt64Prime", n: 9_223_372_036_854_775_783, isPrime: true},
+		{name: "Negative", n: -7},
+	} {
+		t.Run(testcase.name, func(t *testing.T) {
+			require.Equal(t, testcase.isPrime, IsPrime(testcase.n))
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package verify
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/zalgonoise/tendigitprimes/database"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+const (
+	// maxIssues is the number of issues listed in the report for each partition; the remaining ones are only counted.
+	maxIssues = 10
+
+	querySelectScopes = `SELECT id, min, max, total FROM scopes ORDER BY min;`
+	querySelectPrimes = `SELECT prime FROM primes ORDER BY prime;`
+)
+
+var ErrNoPartitions = errors.New("dataset has no partitions")
+
+// Options configures the checks run by Dataset.
+type Options struct {
+	// SampleRate is the fraction of rows whose primality is tested with Miller-Rabin, in (0, 1].
+	SampleRate float64
+	// Sieve enables comparing each partition against a segmented sieve over its range, which finds missing primes.
+	Sieve bool
+	// Workers is the number of partitions checked concurrently.
+	Workers int
+	// Max is the value the dataset is expected to cover up to, like the limit it was generated with. If zero, the
+	// dataset is only expected to cover up to its manifest's last partition, if any.
+	Max int64
+}
+
+// Report is the outcome of a dataset verification.
+type Report struct {
+	Dir     string `json:"dir"`
+	OK      bool   `json:"ok"`
+	Elapsed string `json:"elapsed"`
+
+	Rows       int64             `json:"rows"`
+	Partitions []PartitionReport `json:"partitions"`
+
+	// Issues lists dataset-wide issues, such as manifest mismatches or gaps between partitions.
+	Issues []string `json:"issues,omitempty"`
+}
+
+// PartitionReport is the outcome of checking a single partition.
+type PartitionReport struct {
+	ID  string `json:"id"`
+	Min int64  `json:"min"`
+	Max int64  `json:"max"`
+
+	ExpectedRows     int64 `json:"expected_rows"`
+	Rows             int64 `json:"rows"`
+	PrimalityChecked int64 `json:"primality_checked"`
+	SieveChecked     bool  `json:"sieve_checked"`
+
+	NumIssues int      `json:"num_issues"`
+	Issues    []string `json:"issues,omitempty"`
+}
+
+func (p *PartitionReport) issue(format string, args ...any) {
+	p.NumIssues++
+
+	if len(p.Issues) < maxIssues {
+		p.Issues = append(p.Issues, fmt.Sprintf(format, args...))
+	}
+}
+
+type scope struct {
+	id       string
+	min, max int64
+	total    int64
+}
+
+// Dataset checks the partitioned dataset under dir, verifying that:
+//   - the partition files match the dataset's manifest, if any, with full hashing;
+//   - partitions cover contiguous ranges, from 2 up to the last partition in the manifest, and up to opts.Max if set;
+//   - each partition's row count matches its total in the index;
+//   - each partition's values are strictly increasing and within its range;
+//   - a sample of each partition's values are prime;
+//   - each partition holds exactly the primes a segmented sieve finds over its range, if enabled.
+//
+// Issues found in the dataset are listed in the returned Report; an error is only returned if the dataset's index
+// cannot be read, or if ctx is done.
+func Dataset(ctx context.Context, dir string, opts Options, logger *slog.Logger) (*Report, error) {
+	start := time.Now()
+	report := &Report{Dir: dir}
+
+	if err := database.VerifyManifest(dir, database.VerifyFull, logger); err != nil {
+		report.Issues = append(report.Issues, err.Error())
+	}
+
+	index, err := database.OpenIndex(dir, database.ReadOnlyPragmas(), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, err := getScopes(ctx, index)
+	if err = errors.Join(err, index.Close()); err != nil {
+		return nil, err
+	}
+
+	if len(scopes) == 0 {
+		return nil, ErrNoPartitions
+	}
+
+	report.Issues = append(report.Issues, checkCoverage(dir, scopes, opts.Max)...)
+
+	base := sieve.BasePrimes(sieve.BaseLimit(scopes[len(scopes)-1].max))
+
+	report.Partitions = make([]PartitionReport, len(scopes))
+
+	jobs := make(chan int)
+	wg := &sync.WaitGroup{}
+
+	for range max(opts.Workers, 1) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				report.Partitions[i] = checkPartition(ctx, dir, scopes[i], base, opts)
+
+				logger.InfoContext(ctx, "checked partition",
+					slog.String("id", scopes[i].id),
+					slog.Int64("rows", report.Partitions[i].Rows),
+					slog.Int("num_issues", report.Partitions[i].NumIssues),
+				)
+			}
+		}()
+	}
+
+feed:
+	for i := range scopes {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report.OK = len(report.Issues) == 0
+
+	for i := range report.Partitions {
+		report.Rows += report.Partitions[i].Rows
+
+		if report.Partitions[i].NumIssues > 0 {
+			report.OK = false
+		}
+	}
+
+	report.Elapsed = time.Since(start).String()
+
+	return report, nil
+}
+
+// checkCoverage returns the issues found in the ranges covered by scopes: gaps between partitions, and primes left out
+// before the first partition or after the last one, as expected from the manifest under dir, if any, and from maximum.
+func checkCoverage(dir string, scopes []scope, maximum int64) []string {
+	var issues []string
+
+	if first := scopes[0]; first.min > 2 {
+		issues = append(issues, fmt.Sprintf("partition %s starts at %d, leaving out the primes from 2", first.id, first.min))
+	}
+
+	for i := 1; i < len(scopes); i++ {
+		if scopes[i].min != scopes[i-1].max+1 {
+			issues = append(issues, fmt.Sprintf(
+				"partition %s starts at %d, expected %d following partition %s",
+				scopes[i].id, scopes[i].min, scopes[i-1].max+1, scopes[i-1].id))
+		}
+	}
+
+	// the last partition ends at the greatest prime in the dataset, rather than at the expected maximum
+	expected := lastPrime(maximum)
+
+	if manifest, err := database.ReadManifest(dir); err == nil && len(manifest.Partitions) > 0 {
+		expected = max(expected, int64(manifest.Partitions[len(manifest.Partitions)-1].Max))
+	}
+
+	if last := scopes[len(scopes)-1]; last.max < expected {
+		issues = append(issues, fmt.Sprintf("partition %s ends at %d, leaving out the primes up to %d",
+			last.id, last.max, expected))
+	}
+
+	return issues
+}
+
+// lastPrime returns the greatest prime lower or equal to n, or zero if there is none.
+func lastPrime(n int64) int64 {
+	for ; n >= 2; n-- {
+		if IsPrime(n) {
+			return n
+		}
+	}
+
+	return 0
+}
+
+func checkPartition(ctx context.Context, dir string, s scope, base []int64, opts Options) PartitionReport {
+	report := PartitionReport{
+		ID:           s.id,
+		Min:          s.min,
+		Max:          s.max,
+		ExpectedRows: s.total,
+		SieveChecked: opts.Sieve,
+	}
+
+	db, err := database.OpenPartition(dir, s.id, database.ReadOnlyPragmas())
+	if err != nil {
+		report.issue("failed to open partition: %v", err)
+
+		return report
+	}
+
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, querySelectPrimes)
+	if err != nil {
+		report.issue("failed to query partition: %v", err)
+
+		return report
+	}
+
+	defer rows.Close()
+
+	var (
+		prev   int64 = -1
+		cursor       = &segments{next: max(s.min, 2), hi: s.max, base: base}
+	)
+
+	for rows.Next() {
+		var n int64
+
+		if err = rows.Scan(&n); err != nil {
+			report.issue("failed to read row %d: %v", report.Rows, err)
+
+			return report
+		}
+
+		report.Rows++
+
+		if n <= prev {
+			report.issue("value %d at row %d is not greater than the previous value %d", n, report.Rows-1, prev)
+		}
+
+		if n < s.min || n > s.max {
+			report.issue("value %d at row %d is out of the partition's range", n, report.Rows-1)
+		}
+
+		if opts.SampleRate >= 1 || rand.Float64() < opts.SampleRate {
+			report.PrimalityChecked++
+
+			if !IsPrime(n) {
+				report.issue("value %d at row %d is not prime", n, report.Rows-1)
+			}
+		}
+
+		if opts.Sieve {
+			for p, ok := cursor.peek(); ok && p < n; p, ok = cursor.peek() {
+				report.issue("prime %d is missing", p)
+				cursor.pop()
+			}
+
+			if p, ok := cursor.peek(); ok && p == n {
+				cursor.pop()
+			} else {
+				report.issue("value %d at row %d is not found by the sieve", n, report.Rows-1)
+			}
+		}
+
+		prev = n
+	}
+
+	if err = rows.Err(); err != nil {
+		report.issue("failed to read partition: %v", err)
+
+		return report
+	}
+
+	if opts.Sieve {
+		for p, ok := cursor.peek(); ok; p, ok = cursor.peek() {
+			report.issue("prime %d is missing", p)
+			cursor.pop()
+		}
+	}
+
+	if report.Rows != s.total {
+		report.issue("partition has %d rows, but the index registers %d", report.Rows, s.total)
+	}
+
+	return report
+}
+
+// segments iterates over the primes in [next, hi], sieving one segment at a time.
+type segments struct {
+	next int64
+	hi   int64
+	base []int64
+
+	buf []int64
+	idx int
+}
+
+func (s *segments) peek() (int64, bool) {
+	for s.idx >= len(s.buf) {
+		if s.next > s.hi {
+			return 0, false
+		}
+
+		to := min(s.next+sieve.DefaultSegmentSize-1, s.hi)
+
+		s.buf = sieve.Segment(s.next, to, s.base)
+		s.idx = 0
+		s.next = to + 1
+	}
+
+	return s.buf[s.idx], true
+}
+
+func (s *segments) pop() {
+	s.idx++
+}
+
+func getScopes(ctx context.Context, db *sql.DB) ([]scope, error) {
+	rows, err := db.QueryContext(ctx, querySelectScopes)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	scopes := make([]scope, 0, 64)
+
+	for rows.Next() {
+		var s scope
+
+		if err = rows.Scan(&s.id, &s.min, &s.max, &s.total); err != nil {
+			return nil, err
+		}
+
+		scopes = append(scopes, s)
+	}
+
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return scopes, nil
+}
@@ -0,0 +1,184 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zalgonoise/tendigitprimes/database"
+	"github.com/zalgonoise/tendigitprimes/log"
+	"github.com/zalgonoise/tendigitprimes/sieve"
+)
+
+var testOptions = Options{SampleRate: 1, Sieve: true, Workers: 2}
+
+func newTestDataset(t *testing.T) (string, *database.Manifest) {
+	dir := t.TempDir()
+	primes := sieve.BasePrimes(300_000)
+
+	data := make([]int, len(primes))
+	for i := range primes {
+		data[i] = int(primes[i])
+	}
+
+	layout := database.WidthLayout(100_000)
+
+	require.NoError(t, database.PartitionData(context.Background(), data, layout, 2, dir, log.NoOp()))
+
+	manifest, err := database.ReadManifest(dir)
+	require.NoError(t, err)
+
+	return dir, manifest
+}
+
+func exec(t *testing.T, dir, file, query string) {
+	db, err := database.OpenSQLite(filepath.Join(dir, file), nil, log.NoOp())
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(query)
+	require.NoError(t, err)
+}
+
+func TestDataset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Valid", func(t *testing.T) {
+		dir, _ := newTestDataset(t)
+
+		report, err := Dataset(ctx, dir, testOptions, log.NoOp())
+		require.NoError(t, err)
+		require.True(t, report.OK, report)
+		require.Empty(t, report.Issues)
+		require.Equal(t, int64(25_997), report.Rows)
+		require.Len(t, report.Partitions, 3)
+
+		for _, part := range report.Partitions {
+			require.Equal(t, part.ExpectedRows, part.Rows)
+			require.Equal(t, part.Rows, part.PrimalityChecked)
+			require.Zero(t, part.NumIssues)
+		}
+	})
+
+	t.Run("Sampled", func(t *testing.T) {
+		dir, _ := newTestDataset(t)
+
+		report, err := Dataset(ctx, dir, Options{SampleRate: 0.1, Workers: 1}, log.NoOp())
+		require.NoError(t, err)
+		require.True(t, report.OK, report)
+
+		var checked int64
+		for _, part := range report.Partitions {
+			checked += part.PrimalityChecked
+			require.False(t, part.SieveChecked)
+		}
+
+		require.Less(t, checked, report.Rows/2)
+		require.Positive(t, checked)
+	})
+
+	t.Run("MissingAndCompositeValues", func(t *testing.T) {
+		dir, manifest := newTestDataset(t)
+		part := manifest.Partitions[1]
+
+		exec(t, dir, part.File, `DELETE FROM primes WHERE prime = 100003;`)
+		exec(t, dir, part.File, `UPDATE primes SET prime = 100005 WHERE prime = 100019;`)
+
+		report, err := Dataset(ctx, dir, testOptions, log.NoOp())
+		require.NoError(t, err)
+		require.False(t, report.OK)
+
+		// the files no longer match the manifest
+		require.Len(t, report.Issues, 1)
+		require.Contains(t, report.Issues[0], part.File)
+
+		require.Zero(t, report.Partitions[0].NumIssues)
+		require.Zero(t, report.Partitions[2].NumIssues)
+		require.Equal(t, []string{
+			"value 100005 at row 0 is not prime",
+			"prime 100003 is missing",
+			"value 100005 at row 0 is not found by the sieve",
+			"prime 100019 is missing",
+			fmt.Sprintf("partition has %d rows, but the index registers %d", part.Rows-1, part.Rows),
+		}, report.Partitions[1].Issues)
+		require.Equal(t, part.Rows-1, int(report.Partitions[1].Rows))
+	})
+
+	t.Run("RowCountMismatch", func(t *testing.T) {
+		dir, manifest := newTestDataset(t)
+
+		exec(t, dir, "index.db", `UPDATE scopes SET total = total + 1 WHERE id = '`+manifest.Partitions[2].ID+`';`)
+
+		report, err := Dataset(ctx, dir, testOptions, log.NoOp())
+		require.NoError(t, err)
+		require.False(t, report.OK)
+		require.Empty(t, report.Issues)
+		rows := manifest.Partitions[2].Rows
+		require.Equal(t, []string{
+			fmt.Sprintf("partition has %d rows, but the index registers %d", rows, rows+1),
+		}, report.Partitions[2].Issues)
+	})
+
+	t.Run("MissingFirstPartition", func(t *testing.T) {
+		dir, manifest := newTestDataset(t)
+		first := manifest.Partitions[0]
+
+		exec(t, dir, "index.db", `DELETE FROM scopes WHERE id = '`+first.ID+`';`)
+
+		report, err := Dataset(ctx, dir, testOptions, log.NoOp())
+		require.NoError(t, err)
+		require.False(t, report.OK)
+		require.Equal(t, []string{
+			fmt.Sprintf("partition %s starts at %d, leaving out the primes from 2",
+				manifest.Partitions[1].ID, manifest.Partitions[1].Min),
+		}, report.Issues)
+	})
+
+	t.Run("MissingLastPartition", func(t *testing.T) {
+		dir, manifest := newTestDataset(t)
+		last := manifest.Partitions[2]
+
+		exec(t, dir, "index.db", `DELETE FROM scopes WHERE id = '`+last.ID+`';`)
+
+		report, err := Dataset(ctx, dir, testOptions, log.NoOp())
+		require.NoError(t, err)
+		require.False(t, report.OK)
+		require.Equal(t, []string{
+			fmt.Sprintf("partition %s ends at %d, leaving out the primes up to %d",
+				manifest.Partitions[1].ID, manifest.Partitions[1].Max, last.Max),
+		}, report.Issues)
+	})
+
+	t.Run("TruncatedInput", func(t *testing.T) {
+		// the dataset was built from the primes up to 300_000, but is expected to cover up to 400_000
+		dir, manifest := newTestDataset(t)
+		last := manifest.Partitions[2]
+
+		opts := testOptions
+		opts.Max = 400_000
+
+		report, err := Dataset(ctx, dir, opts, log.NoOp())
+		require.NoError(t, err)
+		require.False(t, report.OK)
+		require.Equal(t, []string{
+			fmt.Sprintf("partition %s ends at %d, leaving out the primes up to 399989", last.ID, last.Max),
+		}, report.Issues)
+
+		opts.Max = 300_000
+
+		report, err = Dataset(ctx, dir, opts, log.NoOp())
+		require.NoError(t, err)
+		require.True(t, report.OK, report)
+	})
+
+	t.Run("NoPartitions", func(t *testing.T) {
+		dir := t.TempDir()
+		exec(t, dir, "index.db", `CREATE TABLE scopes (id TEXT PRIMARY KEY, min INTEGER, max INTEGER, total INTEGER);`)
+
+		_, err := Dataset(ctx, dir, testOptions, log.NoOp())
+		require.ErrorIs(t, err, ErrNoPartitions)
+	})
+}